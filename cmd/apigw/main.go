@@ -5,6 +5,7 @@ import (
 	"eduseal/internal/apigw/apiv1"
 	"eduseal/internal/apigw/db"
 	"eduseal/internal/apigw/httpserver"
+	"eduseal/internal/apigw/metrics"
 	"eduseal/internal/apigw/stream"
 	"eduseal/pkg/configuration"
 	"eduseal/pkg/grpcclient"
@@ -42,6 +43,11 @@ func main() {
 		panic(err)
 	}
 
+	metricsService, err := metrics.New("eduseal_apigw")
+	if err != nil {
+		panic(err)
+	}
+
 	grpcClient, err := grpcclient.New(ctx, cfg, tracer, log.New("grpcclient"))
 	if err != nil {
 		panic(err)
@@ -53,24 +59,31 @@ func main() {
 		panic(err)
 	}
 
-	streamService, err := stream.New(ctx, kvClient, tracer, cfg, log.New("stream"))
-	services["streamService"] = streamService
+	dbService, err := db.New(ctx, cfg, tracer, log.New("db"))
+	services["dbService"] = dbService
 	if err != nil {
 		panic(err)
 	}
 
-	dbService, err := db.New(ctx, cfg, tracer, log.New("db"))
-	services["dbService"] = dbService
+	streamService, err := stream.New(ctx, kvClient, dbService, metricsService, tracer, cfg, log.New("stream"))
+	services["streamService"] = streamService
 	if err != nil {
 		panic(err)
 	}
 
-	apiv1Client, err := apiv1.New(ctx, kvClient, grpcClient, dbService, streamService, tracer, cfg, log.New("apiv1"))
+	apiv1Client, err := apiv1.New(ctx, kvClient, grpcClient, dbService, streamService, metricsService, tracer, cfg, log.New("apiv1"))
 	if err != nil {
 		panic(err)
 	}
 
-	httpService, err := httpserver.New(ctx, cfg, apiv1Client, tracer, log.New("httpserver"))
+	if err := metricsService.ObserveConsumers(streamService.ConsumerStats); err != nil {
+		panic(err)
+	}
+	if err := metricsService.ObserveProbes(apiv1Client.Probes); err != nil {
+		panic(err)
+	}
+
+	httpService, err := httpserver.New(ctx, cfg, apiv1Client, kvClient, metricsService, tracer, log.New("httpserver"))
 	services["httpService"] = httpService
 	if err != nil {
 		panic(err)
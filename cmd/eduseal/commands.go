@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"eduseal/pkg/client"
+	"eduseal/pkg/model"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// labels is a repeatable key=value flag
+type labels map[string]string
+
+func (l labels) String() string {
+	pairs := make([]string, 0, len(l))
+	for key, value := range l {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (l labels) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("label %q is not key=value", s)
+	}
+	l[key] = value
+	return nil
+}
+
+// cmdSeal seals files, directories are expanded to the pdf files directly in them.
+// The sealed pdfs keep the file name with -out, so the files must have distinct names.
+func cmdSeal(ctx context.Context, c *client.Client, args []string) error {
+	flags := newFlagSet("seal", "file|directory...")
+	wait := flags.Bool("wait", false, "wait until every document is sealed or failed")
+	out := flags.String("out", "", "download the sealed pdfs to this directory, implies -wait")
+	externalReference := flags.String("external-reference", "", "client identifier of the documents")
+	callbackURL := flags.String("callback-url", "", "url notified when a document is sealed, failed or revoked")
+	documentLabels := labels{}
+	flags.Var(documentLabels, "label", "key=value label of the documents, repeatable")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	files, err := pdfFiles(flags.Args())
+	if err != nil {
+		return &usageError{msg: err.Error()}
+	}
+	names := make([]string, len(files))
+	if *out != "" {
+		*wait = true
+		seen := map[string]string{}
+		for i, file := range files {
+			names[i] = filepath.Join(*out, filepath.Base(file))
+			if other, ok := seen[names[i]]; ok {
+				return &usageError{msg: fmt.Sprintf("%s and %s would both be downloaded to %s", other, file, names[i])}
+			}
+			seen[names[i]] = file
+		}
+		if err := os.MkdirAll(*out, 0o750); err != nil {
+			return err
+		}
+	}
+
+	opts := &client.SignOptions{
+		ExternalReference: *externalReference,
+		CallbackURL:       *callbackURL,
+		Labels:            documentLabels,
+	}
+
+	transactions := make([]string, 0, len(files))
+	for _, file := range files {
+		transactionID, err := sealFile(ctx, c, file, opts)
+		if err != nil {
+			if *wait {
+				printQueued(transactions, files)
+			}
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		transactions = append(transactions, transactionID)
+		if !*wait {
+			fmt.Printf("%s\t%s\n", transactionID, file)
+		}
+	}
+	if !*wait {
+		return nil
+	}
+
+	failed := false
+	for i, transactionID := range transactions {
+		ok, err := waitAndDownload(ctx, c, transactionID, files[i], names[i])
+		if err != nil {
+			printQueued(transactions[i+1:], files[i+1:])
+			return err
+		}
+		failed = failed || !ok
+	}
+	if failed {
+		return errFailed
+	}
+
+	return nil
+}
+
+// printQueued prints the transaction id and file of queued documents, a seal that stops early prints the
+// documents it did not report yet so their transaction ids are not lost
+func printQueued(transactions, files []string) {
+	for i, transactionID := range transactions {
+		fmt.Printf("%s\t%s\n", transactionID, files[i])
+	}
+}
+
+func sealFile(ctx context.Context, c *client.Client, file string, opts *client.SignOptions) (string, error) {
+	f, err := os.Open(filepath.Clean(file))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	reply, err := c.Sign(ctx, f, opts)
+	if err != nil {
+		return "", err
+	}
+	return reply.Data.TransactionID, nil
+}
+
+// pdfFiles returns the files in paths, a directory is replaced by the .pdf files directly in it
+func pdfFiles(paths []string) ([]string, error) {
+	files := []string{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		n := len(files)
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && strings.EqualFold(filepath.Ext(entry.Name()), ".pdf") {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+		if len(files) == n {
+			return nil, fmt.Errorf("%s holds no pdf files", path)
+		}
+	}
+	return files, nil
+}
+
+// waitAndDownload waits for the transaction and prints its final state, label is printed after it.
+// The sealed pdf is downloaded to name unless name is empty. It reports false when sealing did not succeed.
+func waitAndDownload(ctx context.Context, c *client.Client, transactionID, label, name string) (bool, error) {
+	reply, err := c.Wait(ctx, transactionID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", transactionID, err)
+	}
+	status := reply.Data
+
+	fmt.Println(line(transactionID, string(status.State), label, status.Error))
+	if status.State != model.TransactionStateSealed {
+		return false, nil
+	}
+	if name == "" {
+		return true, nil
+	}
+	if err := downloadFile(ctx, c, transactionID, name); err != nil {
+		return false, fmt.Errorf("%s: %w", transactionID, err)
+	}
+	return true, nil
+}
+
+// downloadFile downloads the sealed pdf next to name and renames it into place, so name is never a partial pdf
+func downloadFile(ctx context.Context, c *client.Client, transactionID, name string) error {
+	f, err := os.CreateTemp(filepath.Dir(name), ".eduseal-*.pdf")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := c.Download(ctx, transactionID, f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
+
+// cmdWait waits for transactions, sealed pdfs are downloaded as <transaction_id>.pdf with -out
+func cmdWait(ctx context.Context, c *client.Client, args []string) error {
+	flags := newFlagSet("wait", "transaction_id...")
+	out := flags.String("out", "", "download the sealed pdfs to this directory")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *out != "" {
+		if err := os.MkdirAll(*out, 0o750); err != nil {
+			return err
+		}
+	}
+
+	failed := false
+	for _, transactionID := range flags.Args() {
+		name := ""
+		if *out != "" {
+			name = filepath.Join(*out, transactionID+".pdf")
+		}
+		ok, err := waitAndDownload(ctx, c, transactionID, "", name)
+		if err != nil {
+			return err
+		}
+		failed = failed || !ok
+	}
+	if failed {
+		return errFailed
+	}
+
+	return nil
+}
+
+// cmdStatus prints the state of transactions
+func cmdStatus(ctx context.Context, c *client.Client, args []string) error {
+	flags := newFlagSet("status", "transaction_id...")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	for _, transactionID := range flags.Args() {
+		reply, err := c.Status(ctx, transactionID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", transactionID, err)
+		}
+		fmt.Println(line(transactionID, string(reply.Data.State), reply.Data.SealerBackend, reply.Data.Error))
+	}
+
+	return nil
+}
+
+// cmdDownload downloads one sealed pdf
+func cmdDownload(ctx context.Context, c *client.Client, args []string) error {
+	flags := newFlagSet("download", "transaction_id")
+	output := flags.String("o", "", "output file, - for stdout, <transaction_id>.pdf when empty")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return &usageError{msg: "download takes one transaction_id"}
+	}
+	transactionID := flags.Arg(0)
+
+	var err error
+	switch *output {
+	case "-":
+		_, err = c.Download(ctx, transactionID, os.Stdout)
+	case "":
+		err = downloadFile(ctx, c, transactionID, transactionID+".pdf")
+	default:
+		err = downloadFile(ctx, c, transactionID, *output)
+	}
+	if errors.Is(err, client.ErrNoPDF) {
+		fmt.Fprintf(os.Stderr, "eduseal: %s has no sealed pdf, see eduseal status %s\n", transactionID, transactionID)
+		return errFailed
+	}
+
+	return err
+}
+
+// cmdValidate validates local files, the outcome is valid, invalid or error
+func cmdValidate(ctx context.Context, c *client.Client, args []string) error {
+	flags := newFlagSet("validate", "file...")
+	full := flags.Bool("full", false, "print the report of every signature as json")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	detail := ""
+	if *full {
+		detail = "full"
+	}
+
+	failed := false
+	for _, file := range flags.Args() {
+		f, err := os.Open(filepath.Clean(file))
+		if err != nil {
+			return err
+		}
+		reply, err := c.Validate(ctx, f, detail)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		validation := reply.Data
+		outcome := "invalid"
+		switch {
+		case validation.Error != "":
+			outcome = "error"
+		case validation.IntactSignature && validation.ValidSignature:
+			outcome = "valid"
+		}
+		failed = failed || outcome != "valid"
+
+		fmt.Println(line(file, outcome, validation.ValidationBackend, validation.Error))
+		if *full {
+			if err := printJSON(os.Stdout, validation.Signatures); err != nil {
+				return err
+			}
+		}
+	}
+	if failed {
+		return errFailed
+	}
+
+	return nil
+}
+
+// cmdRevoke revokes sealed documents
+func cmdRevoke(ctx context.Context, c *client.Client, args []string) error {
+	flags := newFlagSet("revoke", "transaction_id...")
+	reasonCode := flags.String("reason-code", string(model.RevocationReasonUnspecified), fmt.Sprintf("one of %v", model.RevocationReasons))
+	reason := flags.String("reason", "", "free text kept with the document")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if !model.RevocationReason(*reasonCode).Valid() {
+		return &usageError{msg: fmt.Sprintf("reason code %q is not one of %v", *reasonCode, model.RevocationReasons)}
+	}
+
+	for _, transactionID := range flags.Args() {
+		if _, err := c.Revoke(ctx, transactionID, model.RevocationReason(*reasonCode), *reason); err != nil {
+			return fmt.Errorf("%s: %w", transactionID, err)
+		}
+		fmt.Printf("%s\t%s\n", transactionID, model.TransactionStateRevoked)
+	}
+
+	return nil
+}
+
+// cmdHealth prints the apigw health as json, it fails when a probe is unhealthy
+func cmdHealth(ctx context.Context, c *client.Client, args []string) error {
+	flags := newFlagSet("health", "")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{msg: err.Error()}
+	}
+
+	reply, err := c.Health(ctx)
+	if err != nil {
+		return err
+	}
+	if err := printJSON(os.Stdout, reply); err != nil {
+		return err
+	}
+
+	if reply.Data == nil {
+		return errFailed
+	}
+	for _, probe := range reply.Data.Probes {
+		if !probe.Healthy {
+			return errFailed
+		}
+	}
+
+	return nil
+}
+
+func printJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// line joins the fields of a tab separated output line, trailing empty fields are left out so columns keep their position
+func line(fields ...string) string {
+	for len(fields) > 0 && fields[len(fields)-1] == "" {
+		fields = fields[:len(fields)-1]
+	}
+	return strings.Join(fields, "\t")
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"eduseal/pkg/client"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// exit codes, scripts can tell a negative outcome from a failed request
+const (
+	exitOK = 0
+	// exitFailed is used when every request succeeded but the outcome is negative: a failed seal, an invalid signature or an unhealthy service
+	exitFailed = 1
+	// exitUsage is used for unknown commands, bad flags and a missing profile
+	exitUsage = 2
+	// exitError is used when a request fails, the api replied with an error or could not be reached
+	exitError = 3
+)
+
+const usage = `usage: eduseal [-config file] [-profile name] [-timeout duration] <command> [flags] [args]
+
+commands:
+  seal      seal pdf files and the pdf files of directories, optionally wait and download the results
+  wait      wait for transactions to finish, optionally download the sealed pdfs
+  status    show the state of transactions
+  download  download a sealed pdf
+  validate  validate local signed pdf files
+  revoke    revoke sealed documents
+  health    show the apigw health
+
+run "eduseal <command> -h" for the flags of a command.
+
+exit codes: 0 ok, 1 a document failed sealing or validation or apigw is unhealthy,
+2 usage error, 3 a request failed.
+`
+
+// command runs with the flags and arguments following its name
+type command func(ctx context.Context, c *client.Client, args []string) error
+
+var commands = map[string]command{
+	"seal":     cmdSeal,
+	"wait":     cmdWait,
+	"status":   cmdStatus,
+	"download": cmdDownload,
+	"validate": cmdValidate,
+	"revoke":   cmdRevoke,
+	"health":   cmdHealth,
+}
+
+// usageError is returned for bad arguments
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// errFailed is returned when every request succeeded but at least one outcome is negative, the details are already printed
+var errFailed = errors.New("failed")
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	flags := flag.NewFlagSet("eduseal", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	configPath := flags.String("config", envOr("EDUSEAL_CONFIG", defaultProfilesPath()), "profile file")
+	profileName := flags.String("profile", os.Getenv("EDUSEAL_PROFILE"), "profile name, the default profile of the file when empty")
+	timeout := flags.Duration("timeout", 0, "give up after this long, e.g. 10m, no limit when zero")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "eduseal: unknown command %q\n\n", flags.Arg(0))
+		flags.Usage()
+		return exitUsage
+	}
+
+	p, err := loadProfile(*configPath, *profileName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "eduseal:", err)
+		return exitUsage
+	}
+
+	cfg := &client.Config{URL: p.URL}
+	switch {
+	case p.Token != "":
+		cfg.TokenSource = client.StaticToken(p.Token)
+	case p.TokenFile != "":
+		cfg.TokenSource = fileToken(p.TokenFile)
+	}
+	c, err := client.New(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "eduseal:", err)
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	err = cmd(ctx, c, flags.Args()[1:])
+	var usageErr *usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, errFailed):
+		return exitFailed
+	case errors.As(err, &usageErr):
+		fmt.Fprintln(os.Stderr, "eduseal:", err)
+		return exitUsage
+	default:
+		fmt.Fprintln(os.Stderr, "eduseal:", err)
+		return exitError
+	}
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// newFlagSet returns the flag set of a command, parse errors are returned as usage errors
+func newFlagSet(name, args string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: eduseal %s [flags] %s\n", name, args)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseFlags parses args and requires at least one positional argument
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{msg: err.Error()}
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return &usageError{msg: fmt.Sprintf("%s needs at least one argument", flags.Name())}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// profiles is the profile file, e.g.
+//
+//	default: test
+//	profiles:
+//	  test:
+//	    url: https://apigw.test.example.org
+//	    token_file: ~/.config/eduseal/test.jwt
+//	  prod:
+//	    url: https://apigw.example.org
+//	    token: eyJhbGciOi...
+type profiles struct {
+	Default  string              `yaml:"default"`
+	Profiles map[string]*profile `yaml:"profiles"`
+}
+
+// profile holds the endpoint and token of one apigw
+type profile struct {
+	URL string `yaml:"url"`
+	// Token is the jwt, it takes precedence over TokenFile
+	Token string `yaml:"token"`
+	// TokenFile is read before every request, so a token refreshed by another tool is picked up
+	TokenFile string `yaml:"token_file"`
+}
+
+// defaultProfilesPath is the profile file used when neither -config nor EDUSEAL_CONFIG is set
+func defaultProfilesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "eduseal", "profiles.yaml")
+}
+
+// loadProfile returns the named profile, or the default one when name is empty.
+// EDUSEAL_URL and EDUSEAL_TOKEN override the profile, and are enough without a profile file.
+func loadProfile(path, name string) (*profile, error) {
+	p := &profile{}
+
+	b, err := os.ReadFile(filepath.Clean(expandHome(path)))
+	switch {
+	case err == nil:
+		file := &profiles{}
+		if err := yaml.Unmarshal(b, file); err != nil {
+			return nil, fmt.Errorf("profile file %s: %w", path, err)
+		}
+		if name == "" {
+			name = file.Default
+		}
+		if name != "" {
+			found, ok := file.Profiles[name]
+			if !ok {
+				return nil, fmt.Errorf("profile %q is not in %s", name, path)
+			}
+			p = found
+		}
+	case errors.Is(err, os.ErrNotExist) && name == "":
+	default:
+		return nil, err
+	}
+
+	if url := os.Getenv("EDUSEAL_URL"); url != "" {
+		p.URL = url
+	}
+	if token := os.Getenv("EDUSEAL_TOKEN"); token != "" {
+		p.Token = token
+	}
+	if p.URL == "" {
+		return nil, errors.New("no apigw url, set it in a profile or with EDUSEAL_URL")
+	}
+
+	return p, nil
+}
+
+// fileToken is a client.TokenSource that reads the token from a file
+type fileToken string
+
+// Token returns the content of the file without surrounding whitespace
+func (f fileToken) Token(ctx context.Context) (string, error) {
+	b, err := os.ReadFile(filepath.Clean(expandHome(string(f))))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
@@ -15,9 +15,204 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/batch/{batch_id}": {
+            "get": {
+                "description": "state of every document in a batch",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "batch status",
+                "operationId": "batch-get",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "batch_id",
+                        "name": "batch_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.BatchGetReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/batch/{batch_id}/zip": {
+            "get": {
+                "description": "every sealed document of a batch, as one zip, with a manifest.json that lists each document and why a document was left out, e.g. document_is_revoked",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "batch zip download",
+                "operationId": "batch-zip",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "batch_id",
+                        "name": "batch_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pdf/legal_hold/{transaction_id}": {
+            "put": {
+                "description": "place, hold=true, or release, hold=false, a legal hold on a signed pdf",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "legal hold",
+                "operationId": "pdf-legal-hold",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "transaction_id",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "hold and reason",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFLegalHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFLegalHoldReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pdf/reinstate/{transaction_id}": {
+            "put": {
+                "description": "lift the suspension of a signed pdf, the transaction status is sealed again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "reinstate signed pdf",
+                "operationId": "pdf-reinstate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "transaction_id",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "reason",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFReinstateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFReinstateReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/pdf/revoke/{transaction_id}": {
             "put": {
-                "description": "revoke a singed pdf",
+                "description": "revoke a singed pdf, with a reason code and free text",
                 "consumes": [
                     "application/json"
                 ],
@@ -36,6 +231,14 @@ const docTemplate = `{
                         "name": "transaction_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "reason",
+                        "name": "req",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFRevokeRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -45,6 +248,62 @@ const docTemplate = `{
                             "$ref": "#/definitions/apiv1.PDFRevokeReply"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pdf/search": {
+            "get": {
+                "description": "find the caller's transactions by external_reference and labels, newest first, e.g. ?labels[course]=TDA123",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "search transactions",
+                "operationId": "pdf-search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "external_reference",
+                        "name": "external_reference",
+                        "in": "query"
+                    },
+                    {
+                        "type": "object",
+                        "description": "labels[key]=value, every label has to match",
+                        "name": "labels",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max number of transactions, default 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFSearchReply"
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -56,9 +315,11 @@ const docTemplate = `{
         },
         "/pdf/sign": {
             "post": {
-                "description": "sign base64 encoded PDF",
+                "description": "sign a PDF sent as base64 json, raw application/pdf or multipart/form-data field pdf, with wait set the reply holds the sealed document or status pending. A malformed, encrypted, too large or otherwise unsealable document is rejected with error invalid_pdf, the details list the failed checks",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/pdf",
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
@@ -77,6 +338,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/apiv1.PDFSignRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "wait for the sealed document, e.g. 20s",
+                        "name": "wait",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "retries with the same key return the original transaction instead of sealing again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -91,13 +364,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/pdf/validate": {
+        "/pdf/sign/batch": {
             "post": {
-                "description": "validate a signed base64 encoded PDF",
+                "description": "sign many base64 encoded PDFs, each document gets its own transaction, a document that fails the preflight checks is marked failed and not queued",
                 "consumes": [
                     "application/json"
                 ],
@@ -107,8 +386,8 @@ const docTemplate = `{
                 "tags": [
                     "eduseal"
                 ],
-                "summary": "Validate pdf",
-                "operationId": "pdf-validate",
+                "summary": "Sign a batch of pdfs",
+                "operationId": "pdf-sign-batch",
                 "parameters": [
                     {
                         "description": " ",
@@ -116,7 +395,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apiv1.PDFValidateRequest"
+                            "$ref": "#/definitions/apiv1.PDFSignBatchRequest"
                         }
                     }
                 ],
@@ -124,7 +403,7 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/apiv1.PDFValidateReply"
+                            "$ref": "#/definitions/apiv1.PDFSignBatchReply"
                         }
                     },
                     "400": {
@@ -132,13 +411,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/pdf/{transaction_id}": {
-            "get": {
-                "description": "fetch a singed pdf",
+        "/pdf/suspend/{transaction_id}": {
+            "put": {
+                "description": "hold a signed pdf, e.g. while an investigation runs, until it is reinstated or revoked, the transaction status is suspended meanwhile",
                 "consumes": [
                     "application/json"
                 ],
@@ -148,8 +433,8 @@ const docTemplate = `{
                 "tags": [
                     "eduseal"
                 ],
-                "summary": "fetch singed pdf",
-                "operationId": "pdf-fetch",
+                "summary": "suspend signed pdf",
+                "operationId": "pdf-suspend",
                 "parameters": [
                     {
                         "type": "string",
@@ -157,13 +442,22 @@ const docTemplate = `{
                         "name": "transaction_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "reason",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFSuspendRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/apiv1.PDFGetSignedReply"
+                            "$ref": "#/definitions/apiv1.PDFSuspendReply"
                         }
                     },
                     "400": {
@@ -171,108 +465,1515 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "apiv1.PDFGetSignedReply": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "$ref": "#/definitions/model.Document"
-                }
-            }
         },
-        "apiv1.PDFRevokeReply": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "object",
-                    "properties": {
-                        "status": {
+        "/pdf/validate": {
+            "post": {
+                "description": "validate a signed PDF sent as base64 json, raw application/pdf or multipart/form-data field pdf",
+                "consumes": [
+                    "application/json",
+                    "application/pdf",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "Validate pdf",
+                "operationId": "pdf-validate",
+                "parameters": [
+                    {
+                        "description": " ",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFValidateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "summary or full, full adds a report for every signature",
+                        "name": "detail",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFValidateReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pdf/{transaction_id}": {
+            "get": {
+                "description": "fetch a singed pdf, as json or as raw pdf with Accept: application/pdf, from the cache or the archive, only the organization that created the transaction can fetch it and a revoked or suspended document is rejected",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "fetch singed pdf",
+                "operationId": "pdf-fetch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "transaction_id",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFGetSignedReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "remove the document from the kv, the database, pending stream messages, its batch and its idempotency key, a tombstone with the hash, timestamps and reason is kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "erase transaction",
+                "operationId": "pdf-erase",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "transaction_id",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "reason",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFEraseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFEraseReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pdf/{transaction_id}/status": {
+            "get": {
+                "description": "lifecycle state of a transaction, queued, sealing, sealed, failed, expired, suspended or revoked, only the organization that created the transaction can read it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "transaction status",
+                "operationId": "pdf-status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "transaction_id",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFStatusReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pdf/{transaction_id}/validate": {
+            "post": {
+                "description": "validate the sealed document of a transaction, from the cache or the archive, and store the outcome with the transaction, a revoked or suspended document is rejected",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "Validate sealed pdf",
+                "operationId": "pdf-validate-transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "transaction_id",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "summary or full, full adds a report for every signature",
+                        "name": "detail",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFValidateReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pdf/{transaction_id}/webhooks": {
+            "get": {
+                "description": "list webhook delivery attempts for a transaction, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "webhook delivery attempts",
+                "operationId": "pdf-webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "transaction_id",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFWebhooksReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/revocations": {
+            "get": {
+                "description": "revocations in the order they were recorded, each has an increasing seq, relying parties poll it with the next_cursor of the previous reply",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "revocation feed",
+                "operationId": "revocations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "unix time, earlier revocations are skipped",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous reply",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max number of revocations, default 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.RevocationsReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "description": "signings, fetches and validations per hour or day, admins get every organization unless organization_id is given",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "usage statistics",
+                "operationId": "stats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "unix time, default 24 hours (hour) or 30 days (day) before to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix time, default now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "hour, the default, or day",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "admins only, one organization",
+                        "name": "organization_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.StatsReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions": {
+            "get": {
+                "description": "the caller's transactions, newest first unless sort=created_at, follow next_cursor for the next page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "list transactions",
+                "operationId": "transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "queued, sealed, failed, revoked, suspended or erased",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix time, transactions created at or after it",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix time, transactions created before it",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "object",
+                        "description": "labels[key]=value, every label has to match",
+                        "name": "labels",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "-created_at, the default, or created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous reply",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max number of transactions, default 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.TransactionsReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/usage": {
+            "get": {
+                "description": "current usage of the caller's request rate limit and sealing quotas",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "usage",
+                "operationId": "usage",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.UsageReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/verify/{sha256}": {
+            "get": {
+                "description": "public and rate limited check that a pdf was sealed here and is not revoked or suspended, by the hex encoded sha256 of the file",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eduseal"
+                ],
+                "summary": "verify sealed pdf by hash",
+                "operationId": "pdf-verify",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sha256",
+                        "name": "sha256",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/apiv1.PDFVerifyReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apiv1.BatchGetReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Batch"
+                }
+            }
+        },
+        "apiv1.BatchSignDocument": {
+            "type": "object",
+            "required": [
+                "pdf"
+            ],
+            "properties": {
+                "external_reference": {
+                    "description": "ExternalReference and Labels are optional client references of the document",
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "Name is an optional client name of the document, it is used as file name in the zip download",
+                    "type": "string"
+                },
+                "pdf": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.OrganizationStats": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StatsBucket"
+                    }
+                },
+                "granularity": {
+                    "type": "string",
+                    "enum": [
+                        "hour",
+                        "day"
+                    ]
+                },
+                "organization_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.PDFEraseReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Document"
+                }
+            }
+        },
+        "apiv1.PDFEraseRequest": {
+            "type": "object",
+            "required": [
+                "reason",
+                "transactionID"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "Reason is kept in the tombstone, it can also be given as a query parameter",
+                    "type": "string"
+                },
+                "transactionID": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.PDFGetSignedReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Document"
+                }
+            }
+        },
+        "apiv1.PDFLegalHoldReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "properties": {
+                        "status": {
+                            "type": "boolean"
+                        }
+                    }
+                }
+            }
+        },
+        "apiv1.PDFLegalHoldRequest": {
+            "type": "object",
+            "required": [
+                "hold",
+                "reason",
+                "transactionID"
+            ],
+            "properties": {
+                "hold": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "transactionID": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.PDFReinstateReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "properties": {
+                        "status": {
+                            "type": "boolean"
+                        }
+                    }
+                }
+            }
+        },
+        "apiv1.PDFReinstateRequest": {
+            "type": "object",
+            "required": [
+                "reason",
+                "transactionID"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "transactionID": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.PDFRevokeReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "properties": {
+                        "status": {
+                            "type": "boolean"
+                        }
+                    }
+                }
+            }
+        },
+        "apiv1.PDFRevokeRequest": {
+            "type": "object",
+            "required": [
+                "transactionID"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "Reason is free text kept with the document, it is not published in the revocation feed",
+                    "type": "string"
+                },
+                "reason_code": {
+                    "description": "ReasonCode defaults to unspecified",
+                    "enum": [
+                        "unspecified",
+                        "issued_in_error",
+                        "superseded",
+                        "withdrawn",
+                        "fraud"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.RevocationReason"
+                        }
+                    ]
+                },
+                "transactionID": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.PDFSearchReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Document"
+                    }
+                }
+            }
+        },
+        "apiv1.PDFSignBatchReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Batch"
+                }
+            }
+        },
+        "apiv1.PDFSignBatchRequest": {
+            "type": "object",
+            "required": [
+                "documents"
+            ],
+            "properties": {
+                "callback_url": {
+                    "description": "CallbackURL is an optional url that is notified for each document",
+                    "type": "string"
+                },
+                "documents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apiv1.BatchSignDocument"
+                    }
+                },
+                "metadata": {
+                    "description": "Metadata is optional signature metadata for every document, it has to match one of the organization's signature templates",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v1_sealer.SignatureMetadata"
+                        }
+                    ]
+                }
+            }
+        },
+        "apiv1.PDFSignReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/v1_sealer.SealReply"
+                },
+                "status": {
+                    "description": "Status is only set in synchronous sign mode",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.PDFSignRequest": {
+            "type": "object",
+            "required": [
+                "pdf"
+            ],
+            "properties": {
+                "appearance": {
+                    "description": "Appearance is an optional visible seal stamp",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v1_sealer.SignatureAppearance"
+                        }
+                    ]
+                },
+                "callback_url": {
+                    "description": "CallbackURL is an optional url that is notified when the document is sealed, failed or revoked",
+                    "type": "string"
+                },
+                "external_reference": {
+                    "description": "ExternalReference is an optional client identifier of the document, e.g. a student uid",
+                    "type": "string"
+                },
+                "labels": {
+                    "description": "Labels are optional client key value pairs, transactions can be searched by them",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "metadata": {
+                    "description": "Metadata is optional signature metadata, it has to match one of the organization's signature templates",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v1_sealer.SignatureMetadata"
+                        }
+                    ]
+                },
+                "pdf": {
+                    "type": "string"
+                },
+                "wait": {
+                    "description": "Wait is an optional duration, for example \"30s\", to wait for the sealed document before replying",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.PDFStatusReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.TransactionStatus"
+                }
+            }
+        },
+        "apiv1.PDFSuspendReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "properties": {
+                        "status": {
                             "type": "boolean"
                         }
                     }
                 }
             }
         },
-        "apiv1.PDFSignReply": {
+        "apiv1.PDFSuspendRequest": {
+            "type": "object",
+            "required": [
+                "reason",
+                "transactionID"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "transactionID": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.PDFValidateReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/v1_validator.ValidateReply"
+                }
+            }
+        },
+        "apiv1.PDFValidateRequest": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "description": "Detail is \"full\" for the per signature report, the default is the summary only",
+                    "type": "string"
+                },
+                "pdf": {
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.PDFVerifyReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/apiv1.VerifyResult"
+                }
+            }
+        },
+        "apiv1.PDFWebhooksReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebhookAttempt"
+                    }
+                }
+            }
+        },
+        "apiv1.RevocationsReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Revocation"
+                    }
+                },
+                "has_more": {
+                    "description": "HasMore is true when data is a full page and the next page should be fetched right away",
+                    "type": "boolean"
+                },
+                "next_cursor": {
+                    "description": "NextCursor continues the feed after the last revocation in data, it is the request cursor when data is empty",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.StatsReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apiv1.OrganizationStats"
+                    }
+                }
+            }
+        },
+        "apiv1.TransactionListItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "string"
+                },
+                "erased_at": {
+                    "type": "integer"
+                },
+                "erased_by": {
+                    "type": "string"
+                },
+                "erasure_reason": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "external_reference": {
+                    "type": "string"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DocumentEvent"
+                    }
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "legal_hold": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "the fields below are only stored in the database",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reason_code": {
+                    "$ref": "#/definitions/model.RevocationReason"
+                },
+                "revocation_seq": {
+                    "type": "integer"
+                },
+                "revoked_at": {
+                    "type": "integer"
+                },
+                "revoked_by": {
+                    "type": "string"
+                },
+                "sealed_at": {
+                    "type": "integer"
+                },
+                "sealer_backend": {
+                    "type": "string"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "queued",
+                        "sealed",
+                        "failed",
+                        "revoked",
+                        "suspended",
+                        "erased"
+                    ]
+                },
+                "suspend_reason": {
+                    "type": "string"
+                },
+                "suspended_at": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "string"
+                },
+                "validation": {
+                    "$ref": "#/definitions/model.ValidationOutcome"
+                }
+            }
+        },
+        "apiv1.TransactionsReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apiv1.TransactionListItem"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor continues the listing after the last transaction in data, it is empty when there are no more transactions",
+                    "type": "string"
+                }
+            }
+        },
+        "apiv1.Usage": {
+            "type": "object",
+            "properties": {
+                "daily_seals": {
+                    "$ref": "#/definitions/kvclient.QuotaUsage"
+                },
+                "monthly_seals": {
+                    "$ref": "#/definitions/kvclient.QuotaUsage"
+                },
+                "organization_id": {
+                    "type": "string"
+                },
+                "requests_per_second": {
+                    "$ref": "#/definitions/kvclient.QuotaUsage"
+                }
+            }
+        },
+        "apiv1.UsageReply": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/apiv1.Usage"
+                }
+            }
+        },
+        "apiv1.VerifyResult": {
+            "type": "object",
+            "properties": {
+                "erased_at": {
+                    "type": "integer"
+                },
+                "organization_id": {
+                    "type": "string"
+                },
+                "reason_code": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "integer"
+                },
+                "sealed_at": {
+                    "type": "integer"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "sealed",
+                        "revoked",
+                        "suspended",
+                        "erased"
+                    ]
+                },
+                "suspended_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "helpers.Error": {
+            "type": "object",
+            "properties": {
+                "details": {},
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "helpers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/helpers.Error"
+                }
+            }
+        },
+        "kvclient.QuotaUsage": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "resets_at": {
+                    "type": "integer"
+                },
+                "used": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Batch": {
+            "type": "object",
+            "properties": {
+                "batch_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BatchItem"
+                    }
+                },
+                "organization_id": {
+                    "type": "string"
+                },
+                "summary": {
+                    "description": "Summary counts the items per state, it is only set when the batch is read",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "model.BatchItem": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "problems": {
+                    "description": "Problems are the failed preflight checks of a document that was not queued",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pdfinspect.Problem"
+                    }
+                },
+                "state": {
+                    "$ref": "#/definitions/model.TransactionState"
+                },
+                "transaction_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Document": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "type": "integer"
+                },
                 "data": {
-                    "$ref": "#/definitions/v1_sealer.SealReply"
+                    "type": "string"
+                },
+                "erased_at": {
+                    "type": "integer"
+                },
+                "erased_by": {
+                    "type": "string"
+                },
+                "erasure_reason": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "external_reference": {
+                    "type": "string"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DocumentEvent"
+                    }
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "legal_hold": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "the fields below are only stored in the database",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reason_code": {
+                    "$ref": "#/definitions/model.RevocationReason"
+                },
+                "revocation_seq": {
+                    "type": "integer"
+                },
+                "revoked_at": {
+                    "type": "integer"
+                },
+                "revoked_by": {
+                    "type": "string"
+                },
+                "sealed_at": {
+                    "type": "integer"
+                },
+                "sealer_backend": {
+                    "type": "string"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "suspend_reason": {
+                    "type": "string"
+                },
+                "suspended_at": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "string"
+                },
+                "validation": {
+                    "$ref": "#/definitions/model.ValidationOutcome"
                 }
             }
         },
-        "apiv1.PDFSignRequest": {
-            "type": "object",
-            "required": [
-                "pdf"
+        "model.DocumentAction": {
+            "type": "string",
+            "enum": [
+                "revoked",
+                "suspended",
+                "reinstated",
+                "legal_hold_placed",
+                "legal_hold_released"
             ],
+            "x-enum-varnames": [
+                "DocumentActionRevoked",
+                "DocumentActionSuspended",
+                "DocumentActionReinstated",
+                "DocumentActionLegalHoldPlaced",
+                "DocumentActionLegalHoldReleased"
+            ]
+        },
+        "model.DocumentEvent": {
+            "type": "object",
             "properties": {
-                "pdf": {
+                "action": {
+                    "$ref": "#/definitions/model.DocumentAction"
+                },
+                "by": {
+                    "type": "string"
+                },
+                "reason": {
                     "type": "string"
+                },
+                "reason_code": {
+                    "$ref": "#/definitions/model.RevocationReason"
+                },
+                "ts": {
+                    "type": "integer"
                 }
             }
         },
-        "apiv1.PDFValidateReply": {
+        "model.Revocation": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/v1_validator.ValidateReply"
+                "reason_code": {
+                    "$ref": "#/definitions/model.RevocationReason"
+                },
+                "revoked_at": {
+                    "type": "integer"
+                },
+                "seq": {
+                    "description": "Seq orders the feed, it is taken from a counter when the document is revoked",
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "string"
                 }
             }
         },
-        "apiv1.PDFValidateRequest": {
+        "model.RevocationReason": {
+            "type": "string",
+            "enum": [
+                "unspecified",
+                "issued_in_error",
+                "superseded",
+                "withdrawn",
+                "fraud"
+            ],
+            "x-enum-varnames": [
+                "RevocationReasonUnspecified",
+                "RevocationReasonIssuedInError",
+                "RevocationReasonSuperseded",
+                "RevocationReasonWithdrawn",
+                "RevocationReasonFraud"
+            ]
+        },
+        "model.StatsBucket": {
             "type": "object",
             "properties": {
-                "pdf": {
-                    "type": "string"
+                "fetches": {
+                    "type": "integer"
+                },
+                "signings": {
+                    "type": "integer"
+                },
+                "start": {
+                    "description": "Start is the unix time the bucket starts",
+                    "type": "integer"
+                },
+                "validations": {
+                    "type": "integer"
                 }
             }
         },
-        "helpers.Error": {
+        "model.TransactionState": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "sealing",
+                "sealed",
+                "failed",
+                "expired",
+                "suspended",
+                "revoked"
+            ],
+            "x-enum-varnames": [
+                "TransactionStateQueued",
+                "TransactionStateSealing",
+                "TransactionStateSealed",
+                "TransactionStateFailed",
+                "TransactionStateExpired",
+                "TransactionStateSuspended",
+                "TransactionStateRevoked"
+            ]
+        },
+        "model.TransactionStatus": {
             "type": "object",
             "properties": {
-                "details": {},
-                "title": {
+                "error": {
+                    "type": "string"
+                },
+                "sealer_backend": {
+                    "type": "string"
+                },
+                "state": {
+                    "$ref": "#/definitions/model.TransactionState"
+                },
+                "transaction_id": {
                     "type": "string"
+                },
+                "transitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TransactionTransition"
+                    }
+                },
+                "validation": {
+                    "description": "Validation is the outcome of the latest validation of the sealed document, if any",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ValidationOutcome"
+                        }
+                    ]
                 }
             }
         },
-        "helpers.ErrorResponse": {
+        "model.TransactionTransition": {
             "type": "object",
             "properties": {
-                "error": {
-                    "$ref": "#/definitions/helpers.Error"
+                "state": {
+                    "$ref": "#/definitions/model.TransactionState"
+                },
+                "ts": {
+                    "type": "integer"
                 }
             }
         },
-        "model.Document": {
+        "model.ValidationOutcome": {
             "type": "object",
             "properties": {
-                "data": {
+                "error": {
                     "type": "string"
                 },
-                "message": {
+                "intact_signature": {
+                    "type": "boolean"
+                },
+                "valid_signature": {
+                    "type": "boolean"
+                },
+                "validated_at": {
+                    "type": "integer"
+                },
+                "validation_backend": {
                     "type": "string"
+                }
+            }
+        },
+        "model.WebhookAttempt": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer"
                 },
-                "reason": {
+                "delivered": {
+                    "type": "boolean"
+                },
+                "error": {
                     "type": "string"
                 },
-                "revoked_at": {
+                "event": {
+                    "$ref": "#/definitions/model.TransactionState"
+                },
+                "final": {
+                    "type": "boolean"
+                },
+                "status_code": {
                     "type": "integer"
                 },
-                "sealer_backend": {
+                "ts": {
+                    "type": "integer"
+                }
+            }
+        },
+        "pdfinspect.Problem": {
+            "type": "object",
+            "properties": {
+                "check": {
                     "type": "string"
                 },
-                "transaction_id": {
+                "message": {
                     "type": "string"
                 }
             }
         },
+        "v1_sealer.Rectangle": {
+            "type": "object",
+            "properties": {
+                "llx": {
+                    "type": "number"
+                },
+                "lly": {
+                    "type": "number"
+                },
+                "urx": {
+                    "type": "number"
+                },
+                "ury": {
+                    "type": "number"
+                }
+            }
+        },
         "v1_sealer.SealReply": {
             "type": "object",
             "properties": {
@@ -290,6 +1991,144 @@ const docTemplate = `{
                 }
             }
         },
+        "v1_sealer.SignatureAppearance": {
+            "type": "object",
+            "properties": {
+                "image": {
+                    "description": "image is the name of a background image in the sealer's image registry",
+                    "type": "string"
+                },
+                "page": {
+                    "description": "page is the page number of the stamp, starting at 1",
+                    "type": "integer"
+                },
+                "rect": {
+                    "$ref": "#/definitions/v1_sealer.Rectangle"
+                },
+                "text": {
+                    "description": "text is the stamp text, {signer}, {ts}, {reason}, {location} and {transaction_id} are replaced",
+                    "type": "string"
+                }
+            }
+        },
+        "v1_sealer.SignatureMetadata": {
+            "type": "object",
+            "properties": {
+                "contact_info": {
+                    "type": "string"
+                },
+                "field_name": {
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1_validator.Certificate": {
+            "type": "object",
+            "properties": {
+                "issuer": {
+                    "type": "string"
+                },
+                "not_after": {
+                    "type": "integer"
+                },
+                "not_before": {
+                    "type": "integer"
+                },
+                "serial_number": {
+                    "type": "string"
+                },
+                "sha256_fingerprint": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1_validator.SignatureReport": {
+            "type": "object",
+            "properties": {
+                "chain": {
+                    "description": "chain is the signer certificate first, up to the trust root when a path was found",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1_validator.Certificate"
+                    }
+                },
+                "coverage": {
+                    "description": "coverage is entire_file, entire_revision or partial",
+                    "type": "string"
+                },
+                "eduseal": {
+                    "description": "eduseal is true when the signer is one of the eduSeal sealing certificates",
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "field_name": {
+                    "type": "string"
+                },
+                "intact": {
+                    "type": "boolean"
+                },
+                "modification_level": {
+                    "description": "modification_level is none, lta_updates, form_filling, annotations or other",
+                    "type": "string"
+                },
+                "modified_after": {
+                    "description": "modified_after is true when the document was changed after it was signed",
+                    "type": "boolean"
+                },
+                "revocation_status": {
+                    "description": "revocation_status is good, revoked or not_checked",
+                    "type": "string"
+                },
+                "signer_subject": {
+                    "type": "string"
+                },
+                "signing_time": {
+                    "description": "signing_time is the signer reported time, unix seconds, zero if not given",
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "$ref": "#/definitions/v1_validator.TimestampReport"
+                },
+                "trusted": {
+                    "type": "boolean"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1_validator.TimestampReport": {
+            "type": "object",
+            "properties": {
+                "present": {
+                    "type": "boolean"
+                },
+                "time": {
+                    "description": "time is unix seconds",
+                    "type": "integer"
+                },
+                "tsa_subject": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
         "v1_validator.ValidateReply": {
             "type": "object",
             "properties": {
@@ -299,6 +2138,13 @@ const docTemplate = `{
                 "intact_signature": {
                     "type": "boolean"
                 },
+                "signatures": {
+                    "description": "signatures is one report per embedded signature, in document order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1_validator.SignatureReport"
+                    }
+                },
                 "transaction_id": {
                     "type": "string"
                 },
@@ -0,0 +1,80 @@
+package apiv1
+
+import (
+	"eduseal/internal/gen/sealer/v1_sealer"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/pdfinspect"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+)
+
+// defaultMaxAppearanceText is used when apigw.signature_appearance.max_text_length is not configured
+const defaultMaxAppearanceText = 500
+
+// appearancePlaceholder matches the placeholders of a stamp text template
+var appearancePlaceholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// appearancePlaceholders are the placeholders the sealer replaces in the stamp text
+var appearancePlaceholders = []string{"signer", "ts", "reason", "location", "transaction_id"}
+
+// checkAppearance makes sure the sealer can draw the stamp, the rectangle has to lie on the page.
+// It is done here since a stamp outside the page would only fail, or be invisible, long after the client got its reply.
+func (c *Client) checkAppearance(appearance *v1_sealer.SignatureAppearance, doc *pdfinspect.Document) error {
+	if appearance.Page == 0 {
+		appearance.Page = 1
+	}
+	if appearance.Page < 0 {
+		return helpers.NewErrorDetails("invalid_appearance", "page should be 1 or higher")
+	}
+
+	r := appearance.Rect
+	if r == nil || r.Urx <= r.Llx || r.Ury <= r.Lly {
+		return helpers.NewErrorDetails("invalid_appearance", "rect should have llx < urx and lly < ury")
+	}
+
+	if appearance.Image != "" && !slices.Contains(c.cfg.APIGW.SignatureAppearance.Images, appearance.Image) {
+		return helpers.NewErrorDetails("invalid_appearance", fmt.Sprintf("image %q is not in the image registry", appearance.Image))
+	}
+
+	maxText := c.cfg.APIGW.SignatureAppearance.MaxTextLength
+	if maxText == 0 {
+		maxText = defaultMaxAppearanceText
+	}
+	if utf8.RuneCountInString(appearance.Text) > maxText {
+		return helpers.NewErrorDetails("invalid_appearance", fmt.Sprintf("text is longer than %d characters", maxText))
+	}
+	for _, m := range appearancePlaceholder.FindAllStringSubmatch(appearance.Text, -1) {
+		if !slices.Contains(appearancePlaceholders, m[1]) {
+			return helpers.NewErrorDetails("invalid_appearance", fmt.Sprintf("unknown text placeholder %q", m[0]))
+		}
+	}
+
+	pages, err := doc.Pages()
+	if err != nil {
+		return helpers.NewErrorDetails("invalid_pdf", err.Error())
+	}
+
+	if int(appearance.Page) > len(pages) {
+		return helpers.NewErrorDetails("invalid_appearance", fmt.Sprintf("page %d is out of range, the document has %d pages", appearance.Page, len(pages)))
+	}
+
+	bounds := pages[appearance.Page-1].Bounds()
+	rect := pdfinspect.Rect{LLX: r.Llx, LLY: r.Lly, URX: r.Urx, URY: r.Ury}
+	if !bounds.Contains(rect) {
+		return helpers.NewErrorDetails("invalid_appearance", fmt.Sprintf("rect is outside page %d, the page bounds are [%g %g %g %g]", appearance.Page, bounds.LLX, bounds.LLY, bounds.URX, bounds.URY))
+	}
+
+	return nil
+}
+
+// decodePDF decodes a base64 pdf, the sealer accepts both the standard and the url safe alphabet
+func decodePDF(pdf string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(pdf)
+	if err != nil {
+		return base64.URLEncoding.DecodeString(pdf)
+	}
+	return data, nil
+}
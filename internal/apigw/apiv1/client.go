@@ -3,6 +3,7 @@ package apiv1
 import (
 	"context"
 	"eduseal/internal/apigw/db"
+	"eduseal/internal/apigw/metrics"
 	"eduseal/internal/apigw/stream"
 	"eduseal/pkg/grpcclient"
 	"eduseal/pkg/kvclient"
@@ -24,12 +25,14 @@ type Client struct {
 	tp         *trace.Tracer
 	kv         *kvclient.Client
 	grpcClient *grpcclient.Client
+	metrics    *metrics.Metrics
 }
 
 // New creates a new instance of the public api
-func New(ctx context.Context, kv *kvclient.Client, grpcClient *grpcclient.Client, db *db.Service, streamService *stream.Service, tp *trace.Tracer, cfg *model.Cfg, logger *logger.Log) (*Client, error) {
+func New(ctx context.Context, kv *kvclient.Client, grpcClient *grpcclient.Client, db *db.Service, streamService *stream.Service, metrics *metrics.Metrics, tp *trace.Tracer, cfg *model.Cfg, logger *logger.Log) (*Client, error) {
 	c := &Client{
 		cfg:        cfg,
+		metrics:    metrics,
 		db:         db,
 		stream:     streamService,
 		log:        logger,
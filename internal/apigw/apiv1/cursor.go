@@ -0,0 +1,60 @@
+package apiv1
+
+import (
+	"eduseal/pkg/helpers"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// keysetCursor is a position in a listing sorted on a timestamp, ties are broken by transaction id
+type keysetCursor struct {
+	ts            int64
+	transactionID string
+}
+
+func (k keysetCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", k.ts, k.transactionID)))
+}
+
+func parseKeysetCursor(s string) (keysetCursor, error) {
+	invalid := helpers.NewErrorDetails("invalid_cursor", "cursor should be a next_cursor from an earlier reply")
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return keysetCursor{}, invalid
+	}
+	ts, id, found := strings.Cut(string(b), ":")
+	if !found || id == "" {
+		return keysetCursor{}, invalid
+	}
+	n, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return keysetCursor{}, invalid
+	}
+
+	return keysetCursor{ts: n, transactionID: id}, nil
+}
+
+// seqCursor is a position in a listing sorted on an insertion ordered sequence
+type seqCursor int64
+
+func (s seqCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(int64(s), 10)))
+}
+
+func parseSeqCursor(s string) (seqCursor, error) {
+	invalid := helpers.NewErrorDetails("invalid_cursor", "cursor should be a next_cursor from an earlier reply")
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, invalid
+	}
+	n, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil || n < 0 {
+		return 0, invalid
+	}
+
+	return seqCursor(n), nil
+}
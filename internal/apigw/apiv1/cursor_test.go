@@ -0,0 +1,70 @@
+package apiv1
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysetCursor(t *testing.T) {
+	tts := []struct {
+		name   string
+		cursor keysetCursor
+	}{
+		{
+			name:   "transaction id",
+			cursor: keysetCursor{ts: 1711836900, transactionID: "0a6e1b7c-7d3e-4a3c-9f7e-2b8f1f3c0d11"},
+		},
+		{
+			name:   "id with separator",
+			cursor: keysetCursor{ts: 1, transactionID: "a:b"},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseKeysetCursor(tt.cursor.String())
+			require.NoError(t, err)
+			assert.Equal(t, tt.cursor, got)
+		})
+	}
+}
+
+func TestSeqCursor(t *testing.T) {
+	for _, seq := range []seqCursor{0, 1, 42, 1 << 40} {
+		got, err := parseSeqCursor(seq.String())
+		require.NoError(t, err)
+		assert.Equal(t, seq, got)
+	}
+}
+
+func TestParseCursorInvalid(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	tts := []struct {
+		name   string
+		cursor string
+		keyset bool
+		seq    bool
+	}{
+		{name: "not base64", cursor: "!!!", keyset: true, seq: true},
+		{name: "empty id", cursor: encode("10:"), keyset: true, seq: true},
+		{name: "no separator", cursor: encode("10"), keyset: true},
+		{name: "ts not a number", cursor: encode("x:tx-1"), keyset: true, seq: true},
+		{name: "negative seq", cursor: encode("-1"), keyset: true, seq: true},
+		{name: "keyset cursor as seq", cursor: keysetCursor{ts: 10, transactionID: "tx-1"}.String(), seq: true},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseKeysetCursor(tt.cursor)
+			assert.Equal(t, tt.keyset, err != nil, "keyset cursor")
+			_, err = parseSeqCursor(tt.cursor)
+			assert.Equal(t, tt.seq, err != nil, "seq cursor")
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package apiv1
+
+import (
+	"context"
+	"eduseal/internal/apigw/stream"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// defaultMaxPause is used when apigw.admin.max_pause is not configured
+const defaultMaxPause = 24 * time.Hour
+
+// The admin endpoints are served on the admin listener only, they are left out of the public swagger documentation.
+
+// AdminStreamsReply is the reply for the stream and consumer state
+type AdminStreamsReply struct {
+	Data []*stream.StreamInfo `json:"data"`
+}
+
+// AdminStreams returns the state of the streams and their consumers
+func (c *Client) AdminStreams(ctx context.Context) (*AdminStreamsReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:AdminStreams")
+	defer span.End()
+
+	streams, err := c.stream.Streams(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return &AdminStreamsReply{Data: streams}, nil
+}
+
+// AdminPauseSealerRequest is the request for pausing the sealer consumer
+type AdminPauseSealerRequest struct {
+	// Duration is how many seconds the consumer stays paused unless resumed earlier, zero means the configured maximum
+	Duration int64 `json:"duration" form:"duration"`
+
+	// Principal is set from the operator token
+	Principal string `json:"-"`
+}
+
+// AdminSealerReply is the reply for pausing or resuming the sealer consumer
+type AdminSealerReply struct {
+	Data *stream.ConsumerInfo `json:"data"`
+}
+
+// AdminPauseSealer stops the delivery of documents to the sealers, e.g. to drain a sealer node before maintenance
+func (c *Client) AdminPauseSealer(ctx context.Context, req *AdminPauseSealerRequest) (*AdminSealerReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:AdminPauseSealer")
+	defer span.End()
+
+	maxPause := time.Duration(c.cfg.APIGW.Admin.MaxPause) * time.Second
+	if maxPause <= 0 {
+		maxPause = defaultMaxPause
+	}
+	duration := time.Duration(req.Duration) * time.Second
+	switch {
+	case req.Duration < 0 || duration > maxPause:
+		return nil, helpers.NewErrorDetails("invalid_duration", fmt.Sprintf("duration should be between 1 and %d seconds", int64(maxPause.Seconds())))
+	case duration == 0:
+		duration = maxPause
+	}
+
+	info, err := c.stream.PauseSealer(ctx, time.Now().Add(duration))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	c.log.Info("sealer paused", "principal", req.Principal, "duration", duration)
+
+	return &AdminSealerReply{Data: info}, nil
+}
+
+// AdminResumeSealerRequest is the request for resuming the sealer consumer
+type AdminResumeSealerRequest struct {
+	// Principal is set from the operator token
+	Principal string `json:"-"`
+}
+
+// AdminResumeSealer resumes the delivery of documents to the sealers
+func (c *Client) AdminResumeSealer(ctx context.Context, req *AdminResumeSealerRequest) (*AdminSealerReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:AdminResumeSealer")
+	defer span.End()
+
+	info, err := c.stream.ResumeSealer(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	c.log.Info("sealer resumed", "principal", req.Principal)
+
+	return &AdminSealerReply{Data: info}, nil
+}
+
+// AdminTransactionRequest is the request for purging or republishing the messages of a transaction
+type AdminTransactionRequest struct {
+	TransactionID string `uri:"transaction_id" binding:"required"`
+
+	// Principal is set from the operator token
+	Principal string `json:"-"`
+}
+
+// AdminPurgeReply is the reply for purging the messages of a transaction
+type AdminPurgeReply struct {
+	Data struct {
+		Deleted int `json:"deleted"`
+	} `json:"data"`
+}
+
+// AdminPurge removes the pending stream messages of a transaction, e.g. a document every sealer fails on.
+// The transaction is recorded as failed, so clients waiting for it stop.
+func (c *Client) AdminPurge(ctx context.Context, req *AdminTransactionRequest) (*AdminPurgeReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:AdminPurge")
+	defer span.End()
+
+	deleted, err := c.stream.Purge(ctx, req.TransactionID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, helpers.ErrNoPendingMessage
+	}
+
+	errMsg := "purged by operator"
+	if err := c.kv.Transaction.Transition(ctx, req.TransactionID, model.TransactionStateFailed, "", errMsg); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if err := c.stream.Webhook.Enqueue(ctx, &model.WebhookEvent{
+		TransactionID: req.TransactionID,
+		Event:         model.TransactionStateFailed,
+		Error:         errMsg,
+		TS:            time.Now().Unix(),
+	}); err != nil {
+		c.log.Error(err, "failed to enqueue webhook", "transaction_id", req.TransactionID)
+	}
+	c.log.Info("transaction purged", "transaction_id", req.TransactionID, "principal", req.Principal, "deleted", deleted)
+
+	reply := &AdminPurgeReply{}
+	reply.Data.Deleted = deleted
+	return reply, nil
+}
+
+// AdminRepublishReply is the reply for republishing a transaction
+type AdminRepublishReply struct {
+	Data struct {
+		Status bool `json:"status"`
+	} `json:"data"`
+}
+
+// AdminRepublish publishes the pending document of a transaction to the sealers again
+func (c *Client) AdminRepublish(ctx context.Context, req *AdminTransactionRequest) (*AdminRepublishReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:AdminRepublish")
+	defer span.End()
+
+	if err := c.stream.Republish(ctx, req.TransactionID); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	c.log.Info("transaction republished", "transaction_id", req.TransactionID, "principal", req.Principal)
+
+	reply := &AdminRepublishReply{}
+	reply.Data.Status = true
+	return reply, nil
+}
+
+const (
+	// defaultDLQLimit and maxDLQLimit bound the DLQ entries of one listing
+	defaultDLQLimit = 50
+	maxDLQLimit     = 500
+)
+
+// AdminDLQListRequest is the request for listing DLQ entries
+type AdminDLQListRequest struct {
+	// Cursor is the next_cursor of an earlier reply
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+// AdminDLQListReply is the reply for listing DLQ entries, oldest first
+type AdminDLQListReply struct {
+	Data []*stream.DLQEntry `json:"data"`
+	// NextCursor continues the listing after the last entry in data, it is empty when data is not a full page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// AdminDLQList lists the messages moved to the DLQ stream, without their payload
+func (c *Client) AdminDLQList(ctx context.Context, req *AdminDLQListRequest) (*AdminDLQListReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:AdminDLQList")
+	defer span.End()
+
+	limit := req.Limit
+	switch {
+	case limit == 0:
+		limit = defaultDLQLimit
+	case limit < 0 || limit > maxDLQLimit:
+		return nil, helpers.NewErrorDetails("invalid_limit", fmt.Sprintf("limit should be between 1 and %d", maxDLQLimit))
+	}
+	after := uint64(0)
+	if req.Cursor != "" {
+		var err error
+		after, err = strconv.ParseUint(req.Cursor, 10, 64)
+		if err != nil {
+			return nil, helpers.NewErrorDetails("invalid_cursor", "cursor should be a next_cursor from an earlier reply")
+		}
+	}
+
+	entries, err := c.stream.DLQ.List(ctx, after, limit)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply := &AdminDLQListReply{Data: entries}
+	if len(entries) == limit {
+		reply.NextCursor = strconv.FormatUint(entries[len(entries)-1].Seq, 10)
+	}
+	return reply, nil
+}
+
+// AdminDLQRequest is the request for one DLQ entry
+type AdminDLQRequest struct {
+	Seq uint64 `uri:"seq" binding:"required"`
+
+	// Principal is set from the operator token
+	Principal string `json:"-"`
+}
+
+// AdminDLQReply is the reply for one DLQ entry
+type AdminDLQReply struct {
+	Data *stream.DLQEntry `json:"data"`
+}
+
+// AdminDLQGet returns a DLQ entry with its payload
+func (c *Client) AdminDLQGet(ctx context.Context, req *AdminDLQRequest) (*AdminDLQReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:AdminDLQGet")
+	defer span.End()
+
+	entry, err := c.stream.DLQ.Get(ctx, req.Seq)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return &AdminDLQReply{Data: entry}, nil
+}
+
+// AdminDLQReplay publishes a DLQ entry to its original stream again, e.g. once the cause of the failure is fixed
+func (c *Client) AdminDLQReplay(ctx context.Context, req *AdminDLQRequest) (*AdminDLQReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:AdminDLQReplay")
+	defer span.End()
+
+	entry, err := c.stream.DLQ.Replay(ctx, req.Seq)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	c.log.Info("dlq entry replayed", "seq", req.Seq, "transaction_id", entry.TransactionID, "principal", req.Principal)
+
+	return &AdminDLQReply{Data: entry}, nil
+}
+
+// AdminDLQDiscard removes a DLQ entry, its transaction stays failed
+func (c *Client) AdminDLQDiscard(ctx context.Context, req *AdminDLQRequest) (*AdminDLQReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:AdminDLQDiscard")
+	defer span.End()
+
+	entry, err := c.stream.DLQ.Discard(ctx, req.Seq)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	c.log.Info("dlq entry discarded", "seq", req.Seq, "transaction_id", entry.TransactionID, "principal", req.Principal)
+
+	return &AdminDLQReply{Data: entry}, nil
+}
@@ -0,0 +1,423 @@
+package apiv1
+
+import (
+	"archive/zip"
+	"context"
+	"eduseal/internal/gen/sealer/v1_sealer"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// defaultBatchMaxDocuments is used when apigw.batch.max_documents is not configured
+	defaultBatchMaxDocuments = 1000
+	// defaultBatchKeepSigned is used when apigw.batch.keep_signed is not configured
+	defaultBatchKeepSigned = int64(3600)
+)
+
+// BatchSignDocument is one document in a batch sign request
+type BatchSignDocument struct {
+	// Name is an optional client name of the document, it is used as file name in the zip download
+	Name string `json:"name,omitempty"`
+	PDF  string `json:"pdf" validate:"required,base64"`
+
+	// ExternalReference and Labels are optional client references of the document
+	ExternalReference string            `json:"external_reference,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+}
+
+// PDFSignBatchRequest is the request for sign many pdfs at once
+type PDFSignBatchRequest struct {
+	Documents []*BatchSignDocument `json:"documents" validate:"required"`
+
+	// CallbackURL is an optional url that is notified for each document
+	CallbackURL string `json:"callback_url,omitempty"`
+
+	// Metadata is optional signature metadata for every document, it has to match one of the organization's signature templates
+	Metadata *v1_sealer.SignatureMetadata `json:"metadata,omitempty"`
+
+	// OrganizationID is set from the caller's jwt
+	OrganizationID string `json:"-"`
+}
+
+// PDFSignBatchReply is the reply for sign many pdfs at once
+type PDFSignBatchReply struct {
+	Data *model.Batch `json:"data"`
+}
+
+// PDFSignBatch is the request to sign many pdfs at once
+//
+//	@Summary		Sign a batch of pdfs
+//	@ID				pdf-sign-batch
+//	@Description	sign many base64 encoded PDFs, each document gets its own transaction, a document that fails the preflight checks is marked failed and not queued
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	PDFSignBatchReply		"Success"
+//	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
+//	@Failure		429	{object}	helpers.ErrorResponse	"Too Many Requests"
+//	@Param			req	body		PDFSignBatchRequest		true	" "
+//	@Router			/pdf/sign/batch [post]
+func (c *Client) PDFSignBatch(ctx context.Context, req *PDFSignBatchRequest) (*PDFSignBatchReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:PDFSignBatch")
+	defer span.End()
+
+	if err := c.checkBatchLimits(req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if req.CallbackURL != "" {
+		if err := c.checkCallbackURL(ctx, req.CallbackURL, req.OrganizationID); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+	}
+
+	metadata, err := c.signatureMetadata(req.Metadata, req.OrganizationID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	keepSigned := c.cfg.APIGW.Batch.KeepSigned
+	if keepSigned == 0 {
+		keepSigned = defaultBatchKeepSigned
+	}
+
+	batch := &model.Batch{
+		BatchID:        uuid.NewString(),
+		OrganizationID: req.OrganizationID,
+		CreatedAt:      time.Now().Unix(),
+		Items:          make([]*model.BatchItem, 0, len(req.Documents)),
+	}
+	for _, doc := range req.Documents {
+		batch.Items = append(batch.Items, &model.BatchItem{
+			Name:          doc.Name,
+			TransactionID: uuid.NewString(),
+		})
+	}
+
+	quotaKeys, err := c.kv.Quota.Consume(ctx, req.OrganizationID, int64(len(batch.Items)), c.cfg.APIGW.JWTAuth.LimitsFor(req.OrganizationID))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	// the batch has to exist before any item can be looked up through it
+	if err := c.kv.Batch.Save(ctx, batch); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		c.log.Error(err, "failed to save batch")
+		return nil, err
+	}
+
+	failed := 0
+	for i, doc := range req.Documents {
+		item := batch.Items[i]
+
+		request := &v1_sealer.SealRequest{
+			Data:          doc.PDF,
+			TransactionId: item.TransactionID,
+			Metadata:      metadata,
+		}
+		meta := &model.TransactionMeta{
+			OrganizationID:    req.OrganizationID,
+			CallbackURL:       req.CallbackURL,
+			BatchID:           batch.BatchID,
+			KeepSigned:        keepSigned,
+			ExternalReference: doc.ExternalReference,
+			Labels:            doc.Labels,
+		}
+
+		if _, err := c.preflight(ctx, doc.PDF, req.OrganizationID); err != nil {
+			item.State = model.TransactionStateFailed
+			item.Error = fmt.Sprintf("not queued: %s", preflightText(err))
+			_, item.Problems = preflightProblems(err)
+			failed++
+			continue
+		}
+
+		if err := c.publishSeal(ctx, request, meta); err != nil {
+			item.State = model.TransactionStateFailed
+			item.Error = fmt.Sprintf("not queued: %s", err.Error())
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		c.log.Info("batch partially queued", "batch_id", batch.BatchID, "failed", failed)
+		if err := c.kv.Quota.Refund(ctx, quotaKeys, int64(failed)); err != nil {
+			c.log.Error(err, "failed to refund quota")
+		}
+		if err := c.kv.Batch.Save(ctx, batch); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			c.log.Error(err, "failed to save batch")
+			return nil, err
+		}
+	}
+
+	c.log.Debug("PDFSignBatch", "batch_id", batch.BatchID, "documents", len(batch.Items))
+
+	reply := &PDFSignBatchReply{
+		Data: batch,
+	}
+
+	return reply, nil
+}
+
+// checkBatchLimits enforces the per batch size limits
+func (c *Client) checkBatchLimits(req *PDFSignBatchRequest) error {
+	maxDocuments := c.cfg.APIGW.Batch.MaxDocuments
+	if maxDocuments == 0 {
+		maxDocuments = defaultBatchMaxDocuments
+	}
+
+	if len(req.Documents) == 0 {
+		return helpers.ErrEmptyBatch
+	}
+	if len(req.Documents) > maxDocuments {
+		return helpers.NewErrorDetails("batch_too_large", fmt.Sprintf("a batch can hold at most %d documents", maxDocuments))
+	}
+
+	maxDocumentSize := int64(c.cfg.APIGW.Batch.MaxDocumentSize)
+	if maxEncoded := c.maxEncodedPDF(); maxEncoded > 0 && (maxDocumentSize == 0 || maxEncoded < maxDocumentSize) {
+		maxDocumentSize = maxEncoded
+	}
+
+	for i, doc := range req.Documents {
+		if doc == nil || doc.PDF == "" {
+			return helpers.NewErrorDetails("empty_pdf", fmt.Sprintf("document %d is empty", i))
+		}
+		if maxDocumentSize > 0 && int64(len(doc.PDF)) > maxDocumentSize {
+			return helpers.NewErrorDetails("document_too_large", fmt.Sprintf("document %d is larger than %d bytes", i, maxDocumentSize))
+		}
+		if err := checkLabels(doc.ExternalReference, doc.Labels); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BatchGetRequest is the request for a batch
+type BatchGetRequest struct {
+	BatchID        string `uri:"batch_id" binding:"required"`
+	OrganizationID string `json:"-"`
+}
+
+// BatchGetReply is the reply for a batch
+type BatchGetReply struct {
+	Data *model.Batch `json:"data"`
+}
+
+// BatchGet is the request to get the state of every document in a batch
+//
+//	@Summary		batch status
+//	@ID				batch-get
+//	@Description	state of every document in a batch
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200			{object}	BatchGetReply			"Success"
+//	@Failure		400			{object}	helpers.ErrorResponse	"Bad Request"
+//	@Failure		404			{object}	helpers.ErrorResponse	"Not Found"
+//	@Param			batch_id	path		string					true	"batch_id"
+//	@Router			/batch/{batch_id} [get]
+func (c *Client) BatchGet(ctx context.Context, req *BatchGetRequest) (*BatchGetReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:BatchGet")
+	defer span.End()
+
+	batch, err := c.getBatch(ctx, req.BatchID, req.OrganizationID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	batch.Summary = map[model.TransactionState]int{}
+	for _, item := range batch.Items {
+		if item.State == "" {
+			status, err := c.transactionStatus(ctx, item.TransactionID)
+			switch {
+			case errors.Is(err, helpers.ErrTransactionNotFound):
+				item.State = model.TransactionStateQueued
+			case err != nil:
+				span.SetStatus(codes.Error, err.Error())
+				return nil, err
+			default:
+				item.State = status.State
+				item.Error = status.Error
+			}
+		}
+		batch.Summary[item.State]++
+	}
+
+	reply := &BatchGetReply{
+		Data: batch,
+	}
+
+	return reply, nil
+}
+
+// BatchZIPRequest is the request for the sealed documents of a batch
+type BatchZIPRequest struct {
+	BatchID        string `uri:"batch_id" binding:"required"`
+	OrganizationID string `json:"-"`
+}
+
+// BatchZIPReply is written as a zip file, not as json
+type BatchZIPReply struct {
+	FileName string
+	// WriteZIP streams every sealed document that may be handed out and the manifest
+	WriteZIP func(w io.Writer) error
+}
+
+// BatchZIP is the request to download every sealed document of a batch as one zip
+//
+//	@Summary		batch zip download
+//	@ID				batch-zip
+//	@Description	every sealed document of a batch, as one zip, with a manifest.json that lists each document and why a document was left out, e.g. document_is_revoked
+//	@Tags			eduseal
+//	@Produce		application/zip
+//	@Success		200			{file}		binary					"Success"
+//	@Failure		400			{object}	helpers.ErrorResponse	"Bad Request"
+//	@Failure		404			{object}	helpers.ErrorResponse	"Not Found"
+//	@Param			batch_id	path		string					true	"batch_id"
+//	@Router			/batch/{batch_id}/zip [get]
+func (c *Client) BatchZIP(ctx context.Context, req *BatchZIPRequest) (*BatchZIPReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:BatchZIP")
+	defer span.End()
+
+	batch, err := c.getBatch(ctx, req.BatchID, req.OrganizationID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply := &BatchZIPReply{
+		FileName: batch.BatchID + ".zip",
+		WriteZIP: func(w io.Writer) error {
+			return c.writeBatchZIP(ctx, batch, w)
+		},
+	}
+
+	return reply, nil
+}
+
+// batchManifestName is the zip entry that lists every document of the batch, the client names always end in .pdf
+const batchManifestName = "manifest.json"
+
+// batchManifestEntry is one document in the manifest of a batch zip
+type batchManifestEntry struct {
+	TransactionID string `json:"transaction_id"`
+	Name          string `json:"name,omitempty"`
+	// File is the name of the document in the zip, it is empty when the document was left out
+	File string `json:"file,omitempty"`
+	// Error is why the document was left out, e.g. document_is_revoked
+	Error string `json:"error,omitempty"`
+}
+
+// writeBatchZIP writes the sealed documents of the batch and a manifest, the documents are fetched like a single download,
+// so a revoked, suspended or expired document is left out and listed with the reason in the manifest
+func (c *Client) writeBatchZIP(ctx context.Context, batch *model.Batch, w io.Writer) error {
+	ctx, span := c.tp.Start(ctx, "apiv1:writeBatchZIP")
+	defer span.End()
+
+	zw := zip.NewWriter(w)
+	names := map[string]bool{}
+	manifest := make([]*batchManifestEntry, 0, len(batch.Items))
+
+	for _, item := range batch.Items {
+		entry := &batchManifestEntry{
+			TransactionID: item.TransactionID,
+			Name:          item.Name,
+		}
+		manifest = append(manifest, entry)
+
+		// an item that failed before it was queued has no transaction to look up
+		if item.State == model.TransactionStateFailed {
+			entry.Error = item.Error
+			continue
+		}
+
+		doc, err := c.sealedDocument(ctx, item.TransactionID, batch.OrganizationID)
+		if err != nil {
+			c.log.Debug("batch document not available", "batch_id", batch.BatchID, "transaction_id", item.TransactionID, "error", err)
+			entry.Error = helpers.NewErrorFromError(err).Title
+			continue
+		}
+		if doc.Data == "" {
+			entry.Error = doc.Error
+			continue
+		}
+
+		pdf, err := base64.StdEncoding.DecodeString(doc.Data)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+
+		name := batchFileName(item)
+		if names[name] {
+			name = item.TransactionID + ".pdf"
+		}
+		names[name] = true
+		entry.File = name
+
+		f, err := zw.Create(name)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+		if _, err := f.Write(pdf); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+	}
+
+	f, err := zw.Create(batchManifestName)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(manifest); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return zw.Close()
+}
+
+// batchFileName returns a flat pdf file name for the item, the client name is used when it is set
+func batchFileName(item *model.BatchItem) string {
+	name := path.Base(strings.ReplaceAll(item.Name, "\\", "/"))
+	if item.Name == "" || name == "." || name == "/" || name == ".." {
+		return item.TransactionID + ".pdf"
+	}
+	if !strings.HasSuffix(strings.ToLower(name), ".pdf") {
+		name += ".pdf"
+	}
+	return name
+}
+
+// getBatch returns the batch if it belongs to organizationID
+func (c *Client) getBatch(ctx context.Context, batchID, organizationID string) (*model.Batch, error) {
+	batch, err := c.kv.Batch.Get(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.OrganizationID != organizationID {
+		return nil, helpers.ErrBatchNotFound
+	}
+	return batch, nil
+}
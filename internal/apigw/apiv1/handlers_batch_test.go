@@ -0,0 +1,31 @@
+package apiv1
+
+import (
+	"eduseal/pkg/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchFileName(t *testing.T) {
+	tts := []struct {
+		name string
+		item *model.BatchItem
+		want string
+	}{
+		{name: "name", item: &model.BatchItem{TransactionID: "tx", Name: "diploma.pdf"}, want: "diploma.pdf"},
+		{name: "upper case extension", item: &model.BatchItem{TransactionID: "tx", Name: "DIPLOMA.PDF"}, want: "DIPLOMA.PDF"},
+		{name: "no extension", item: &model.BatchItem{TransactionID: "tx", Name: "diploma"}, want: "diploma.pdf"},
+		{name: "no name", item: &model.BatchItem{TransactionID: "tx"}, want: "tx.pdf"},
+		{name: "path", item: &model.BatchItem{TransactionID: "tx", Name: "../../etc/diploma.pdf"}, want: "diploma.pdf"},
+		{name: "windows path", item: &model.BatchItem{TransactionID: "tx", Name: `C:\Users\diploma.pdf`}, want: "diploma.pdf"},
+		{name: "parent directory", item: &model.BatchItem{TransactionID: "tx", Name: ".."}, want: "tx.pdf"},
+		{name: "root", item: &model.BatchItem{TransactionID: "tx", Name: "/"}, want: "tx.pdf"},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, batchFileName(tt.item))
+		})
+	}
+}
@@ -2,39 +2,89 @@ package apiv1
 
 import (
 	"context"
+	"eduseal/internal/apigw/stream"
 	"eduseal/pkg/helpers"
 	"eduseal/pkg/model"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	otelmetric "go.opentelemetry.io/otel/metric"
 
 	"eduseal/internal/gen/sealer/v1_sealer"
 	"eduseal/internal/gen/validator/v1_validator"
 )
 
+// maxSignWait caps the synchronous sign mode, it has to stay below the http server write timeout
+const maxSignWait = 25 * time.Second
+
+var (
+	// SignStatusSealed is returned in synchronous sign mode when the sealed document is ready
+	SignStatusSealed = "sealed"
+	// SignStatusFailed is returned in synchronous sign mode when the sealer did not return a document
+	SignStatusFailed = "failed"
+	// SignStatusPending is returned in synchronous sign mode when the wait timed out before the document was sealed
+	SignStatusPending = "pending"
+)
+
 // PDFSignRequest is the request for sign pdf
 type PDFSignRequest struct {
 	PDF string `json:"pdf" validate:"required,base64"`
+
+	// Wait is an optional duration, for example "30s", to wait for the sealed document before replying
+	Wait string `json:"wait,omitempty" form:"wait"`
+
+	// CallbackURL is an optional url that is notified when the document is sealed, failed or revoked
+	CallbackURL string `json:"callback_url,omitempty"`
+
+	// Metadata is optional signature metadata, it has to match one of the organization's signature templates
+	Metadata *v1_sealer.SignatureMetadata `json:"metadata,omitempty"`
+
+	// Appearance is an optional visible seal stamp
+	Appearance *v1_sealer.SignatureAppearance `json:"appearance,omitempty"`
+
+	// ExternalReference is an optional client identifier of the document, e.g. a student uid
+	ExternalReference string `json:"external_reference,omitempty"`
+
+	// Labels are optional client key value pairs, transactions can be searched by them
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// OrganizationID is set from the caller's jwt
+	OrganizationID string `json:"-"`
+
+	// IdempotencyKey is set from the Idempotency-Key header, a retry with the same key gets the original transaction
+	IdempotencyKey string `json:"-"`
 }
 
 // PDFSignReply is the reply for sign pdf
 type PDFSignReply struct {
 	Data *v1_sealer.SealReply `json:"data"`
+
+	// Status is only set in synchronous sign mode
+	Status string `json:"status,omitempty"`
 }
 
 // PDFSign is the request to sign pdf
 //
 //	@Summary		Sign pdf
 //	@ID				pdf-sign
-//	@Description	sign base64 encoded PDF
+//	@Description	sign a PDF sent as base64 json, raw application/pdf or multipart/form-data field pdf, with wait set the reply holds the sealed document or status pending. A malformed, encrypted, too large or otherwise unsealable document is rejected with error invalid_pdf, the details list the failed checks
 //	@Tags			eduseal
-//	@Accept			json
+//	@Accept			json,application/pdf,mpfd
 //	@Produce		json
-//	@Success		200	{object}	PDFSignReply			"Success"
-//	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
-//	@Param			req	body		PDFSignRequest			true	" "
+//	@Success		200		{object}	PDFSignReply			"Success"
+//	@Failure		400		{object}	helpers.ErrorResponse	"Bad Request"
+//	@Failure		429		{object}	helpers.ErrorResponse	"Too Many Requests"
+//	@Param			req		body		PDFSignRequest			true	" "
+//	@Param			wait			query		string					false	"wait for the sealed document, e.g. 20s"
+//	@Param			Idempotency-Key	header		string					false	"retries with the same key return the original transaction instead of sealing again"
 //	@Router			/pdf/sign [post]
 func (c *Client) PDFSign(ctx context.Context, req *PDFSignRequest) (*PDFSignReply, error) {
 	ctx, span := c.tp.Start(ctx, "apiv1:PDFSign")
@@ -45,48 +95,310 @@ func (c *Client) PDFSign(ctx context.Context, req *PDFSignRequest) (*PDFSignRepl
 		return nil, helpers.ErrEmptyPDF
 	}
 
+	wait, err := parseSignWait(req.Wait)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if req.CallbackURL != "" {
+		if err := c.checkCallbackURL(ctx, req.CallbackURL, req.OrganizationID); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+	}
+
+	metadata, err := c.signatureMetadata(req.Metadata, req.OrganizationID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	doc, err := c.preflight(ctx, req.PDF, req.OrganizationID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if req.Appearance != nil {
+		if err := c.checkAppearance(req.Appearance, doc); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+	}
+
+	if err := checkLabels(req.ExternalReference, req.Labels); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
 	transactionID := uuid.NewString()
 
+	// the waiter is set up before the idempotency key and the quota are taken, so its failure leaves nothing to undo
+	var waiter *stream.SealedWaiter
+	if wait > 0 {
+		waiter, err = c.stream.NewSealedWaiter(ctx, transactionID)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		defer waiter.Close()
+	}
+
+	if req.IdempotencyKey != "" {
+		original, err := c.reserveIdempotencyKey(ctx, req, transactionID)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		if original != "" {
+			c.log.Debug("PDFSign replay", "transaction_id", original)
+			return c.replaySign(ctx, original, wait)
+		}
+	}
+
+	quotaKeys, err := c.kv.Quota.Consume(ctx, req.OrganizationID, 1, c.cfg.APIGW.JWTAuth.LimitsFor(req.OrganizationID))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if req.IdempotencyKey != "" {
+			if err := c.kv.Idempotency.Release(ctx, req.OrganizationID, req.IdempotencyKey); err != nil {
+				c.log.Error(err, "failed to release idempotency key")
+			}
+		}
+		return nil, err
+	}
+
 	reply := &PDFSignReply{
 		Data: &v1_sealer.SealReply{
 			TransactionId: transactionID,
 		},
 	}
 
+	c.log.Debug("PDFSign", "transaction_id", transactionID)
+
 	request := &v1_sealer.SealRequest{
 		Data:          req.PDF,
 		TransactionId: transactionID,
+		Metadata:      metadata,
+		Appearance:    req.Appearance,
 	}
 
-	requestJSON, err := json.Marshal(request)
-	if err != nil {
+	meta := &model.TransactionMeta{
+		OrganizationID:    req.OrganizationID,
+		CallbackURL:       req.CallbackURL,
+		ExternalReference: req.ExternalReference,
+		Labels:            req.Labels,
+		IdempotencyKey:    req.IdempotencyKey,
+	}
+
+	if err := c.publishSeal(ctx, request, meta); err != nil {
 		span.SetStatus(codes.Error, err.Error())
-		c.log.Error(err, "failed to marshal request")
+		if err := c.kv.Quota.Refund(ctx, quotaKeys, 1); err != nil {
+			c.log.Error(err, "failed to refund quota")
+		}
+		if req.IdempotencyKey != "" {
+			if err := c.kv.Idempotency.Release(ctx, req.OrganizationID, req.IdempotencyKey); err != nil {
+				c.log.Error(err, "failed to release idempotency key")
+			}
+		}
 		return nil, err
 	}
 
-	c.log.Debug("PDFSign", "transaction_id", transactionID)
+	if waiter == nil {
+		return reply, nil
+	}
+
+	return c.waitForSealed(ctx, waiter, transactionID, wait)
+}
+
+// publishSeal stores the transaction meta and publishes the request to the SEAL stream.
+// The meta has to be stored first, the sealer might reply before Publish returns.
+func (c *Client) publishSeal(ctx context.Context, request *v1_sealer.SealRequest, meta *model.TransactionMeta) error {
+	ctx, span := c.tp.Start(ctx, "apiv1:publishSeal")
+	defer span.End()
+
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	if err := c.stream.Seal.Publish(ctx, requestJSON, transactionID); err != nil {
+	meta.QueuedAt = time.Now().UnixMilli()
+	if err := c.kv.Transaction.SetMeta(ctx, request.TransactionId, meta); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		c.log.Error(err, "failed to store transaction meta")
+		return err
+	}
+
+	if !c.cfg.Common.Mongo.Disable {
+		if err := c.db.EduSealSigningColl.Save(ctx, &model.Document{
+			TransactionID:     request.TransactionId,
+			OrganizationID:    meta.OrganizationID,
+			ExternalReference: meta.ExternalReference,
+			Labels:            meta.Labels,
+			CreatedAt:         time.Now().Unix(),
+		}); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			c.log.Error(err, "failed to save transaction")
+			return err
+		}
+	}
+
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		c.log.Error(err, "failed to marshal request")
+		return err
+	}
+
+	if err := c.stream.Seal.Publish(ctx, requestJSON, request.TransactionId); err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		c.log.Error(err, "failed to publish to stream")
-		return nil, err
+		return err
 	}
 
+	c.metrics.SignRequests.Add(ctx, 1, otelmetric.WithAttributes(attribute.String("organization_id", meta.OrganizationID)))
+
 	if err := c.kv.MetricSigning.Inc(ctx); err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		c.log.Error(err, "failed to increment metric")
+		return err
+	}
+	c.countStats(ctx, meta.OrganizationID, model.StatsOperationSignings)
+
+	return nil
+}
+
+// checkCallbackURL makes sure the callback is an absolute url to a public address and that the organization has a webhook secret to sign with.
+// The webhook worker refuses internal addresses again when it connects, since the name may resolve differently by then.
+func (c *Client) checkCallbackURL(ctx context.Context, callbackURL, organizationID string) error {
+	u, err := url.Parse(callbackURL)
+	if err != nil || u.Host == "" {
+		return helpers.NewErrorDetails("invalid_callback_url", "callback_url should be an absolute url")
+	}
+	if u.Scheme != "https" && (c.cfg.Common.Production || u.Scheme != "http") {
+		return helpers.NewErrorDetails("invalid_callback_url", "callback_url should use https")
+	}
+	if _, ok := c.cfg.APIGW.Webhook.Secrets[organizationID]; !ok {
+		return helpers.ErrWebhookNotConfigured
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return helpers.NewErrorDetails("invalid_callback_url", "callback_url host does not resolve")
+	}
+	for _, addr := range addrs {
+		if !helpers.PublicAddr(addr) {
+			return helpers.NewErrorDetails("invalid_callback_url", "callback_url should not point to a loopback, private or link local address")
+		}
+	}
+
+	return nil
+}
+
+// signatureMetadata returns the organization's signature template that metadata matches, nil metadata keeps the sealer's configured values.
+// A template matches when every field set in metadata is equal to the template's field, the template is then used as a whole.
+func (c *Client) signatureMetadata(metadata *v1_sealer.SignatureMetadata, organizationID string) (*v1_sealer.SignatureMetadata, error) {
+	if metadata == nil {
+		return nil, nil
+	}
+
+	match := func(value, allowed string) bool {
+		return value == "" || value == allowed
+	}
+
+	for _, template := range c.cfg.APIGW.SignatureTemplates[organizationID] {
+		if match(metadata.Reason, template.Reason) &&
+			match(metadata.Location, template.Location) &&
+			match(metadata.Name, template.Name) &&
+			match(metadata.ContactInfo, template.ContactInfo) &&
+			match(metadata.FieldName, template.FieldName) {
+			return &v1_sealer.SignatureMetadata{
+				Reason:      template.Reason,
+				Location:    template.Location,
+				Name:        template.Name,
+				ContactInfo: template.ContactInfo,
+				FieldName:   template.FieldName,
+			}, nil
+		}
+	}
+
+	return nil, helpers.ErrSignatureMetadataNotAllowed
+}
+
+// parseSignWait parses the optional wait duration of a sign request, zero means asynchronous mode
+func parseSignWait(wait string) (time.Duration, error) {
+	if wait == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(wait)
+	if err != nil || d < 0 {
+		return 0, helpers.NewErrorDetails("invalid_wait", "wait should be a positive duration, e.g. \"20s\"")
+	}
+	if d > maxSignWait {
+		d = maxSignWait
+	}
+	return d, nil
+}
+
+// waitForSealed holds the request until the cache consumer, on any replica, has stored the sealed document
+func (c *Client) waitForSealed(ctx context.Context, waiter *stream.SealedWaiter, transactionID string, wait time.Duration) (*PDFSignReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:waitForSealed")
+	defer span.End()
+
+	reply := &PDFSignReply{
+		Data: &v1_sealer.SealReply{
+			TransactionId: transactionID,
+		},
+		Status: SignStatusPending,
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+
+	if err := waiter.Wait(waitCtx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			c.log.Debug("wait for sealed document timed out", "transaction_id", transactionID)
+			return reply, nil
+		}
+		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
 
+	return c.sealedReply(ctx, transactionID)
+}
+
+// sealedReply is the synchronous sign reply for a transaction that has been cached
+func (c *Client) sealedReply(ctx context.Context, transactionID string) (*PDFSignReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:sealedReply")
+	defer span.End()
+
+	doc, err := c.kv.Doc.GetSigned(ctx, transactionID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		c.log.Error(err, "failed to get signed document")
+		return nil, err
+	}
+
+	reply := &PDFSignReply{
+		Data: &v1_sealer.SealReply{
+			TransactionId: transactionID,
+			Data:          doc.Data,
+			SealerBackend: doc.SealerBackend,
+			Error:         doc.Error,
+		},
+		Status: SignStatusSealed,
+	}
+	if doc.Data == "" {
+		reply.Status = SignStatusFailed
+	}
+
 	return reply, nil
 }
 
 // PDFGetSignedRequest is the request for get signed pdf
 type PDFGetSignedRequest struct {
 	TransactionID string `uri:"transaction_id" binding:"required"`
+
+	// OrganizationID is set from the caller's jwt
+	OrganizationID string `json:"-"`
 }
 
 // PDFGetSignedReply is the reply for the signed pdf
@@ -98,29 +410,22 @@ type PDFGetSignedReply struct {
 //
 //	@Summary		fetch singed pdf
 //	@ID				pdf-fetch
-//	@Description	fetch a singed pdf
+//	@Description	fetch a singed pdf, as json or as raw pdf with Accept: application/pdf, from the cache or the archive, only the organization that created the transaction can fetch it and a revoked or suspended document is rejected
 //	@Tags			eduseal
 //	@Accept			json
-//	@Produce		json
+//	@Produce		json,application/pdf
 //	@Success		200				{object}	PDFGetSignedReply		"Success"
 //	@Failure		400				{object}	helpers.ErrorResponse	"Bad Request"
+//	@Failure		404				{object}	helpers.ErrorResponse	"Not Found"
 //	@Param			transaction_id	path		string					true	"transaction_id"
 //	@Router			/pdf/{transaction_id} [get]
 func (c *Client) PDFGetSigned(ctx context.Context, req *PDFGetSignedRequest) (*PDFGetSignedReply, error) {
 	ctx, span := c.tp.Start(ctx, "apiv1:PDFGetSigned")
 	defer span.End()
 
-	if !c.cfg.Common.Mongo.Disable {
-		if c.db.EduSealSigningColl.IsRevoked(ctx, req.TransactionID) {
-			span.SetStatus(codes.Error, helpers.ErrDocumentIsRevoked.Error())
-			return nil, helpers.ErrDocumentIsRevoked
-		}
-	}
-
-	signedDoc, err := c.kv.Doc.GetSigned(ctx, req.TransactionID)
+	signedDoc, err := c.sealedDocument(ctx, req.TransactionID, req.OrganizationID)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
-		c.log.Error(err, "failed to get signed document")
 		return nil, err
 	}
 
@@ -133,15 +438,149 @@ func (c *Client) PDFGetSigned(ctx context.Context, req *PDFGetSignedRequest) (*P
 		c.log.Error(err, "failed to increment metric")
 		return nil, err
 	}
+	c.countStats(ctx, req.OrganizationID, model.StatsOperationFetches)
 
 	return resp, nil
 }
 
+// PDFStatusRequest is the request for the status of a transaction
+type PDFStatusRequest struct {
+	TransactionID  string `uri:"transaction_id" binding:"required"`
+	OrganizationID string `json:"-"`
+}
+
+// PDFStatusReply is the reply for the status of a transaction
+type PDFStatusReply struct {
+	Data *model.TransactionStatus `json:"data"`
+}
+
+// PDFStatus is the request to get the lifecycle status of a transaction
+//
+//	@Summary		transaction status
+//	@ID				pdf-status
+//	@Description	lifecycle state of a transaction, queued, sealing, sealed, failed, expired, suspended or revoked, only the organization that created the transaction can read it
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	PDFStatusReply			"Success"
+//	@Failure		400				{object}	helpers.ErrorResponse	"Bad Request"
+//	@Failure		404				{object}	helpers.ErrorResponse	"Not Found"
+//	@Param			transaction_id	path		string					true	"transaction_id"
+//	@Router			/pdf/{transaction_id}/status [get]
+func (c *Client) PDFStatus(ctx context.Context, req *PDFStatusRequest) (*PDFStatusReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:PDFStatus")
+	defer span.End()
+
+	meta, err := c.kv.Transaction.GetMeta(ctx, req.TransactionID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if meta.OrganizationID != req.OrganizationID {
+		span.SetStatus(codes.Error, helpers.ErrTransactionNotFound.Error())
+		return nil, helpers.ErrTransactionNotFound
+	}
+
+	status, err := c.transactionStatus(ctx, req.TransactionID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply := &PDFStatusReply{
+		Data: status,
+	}
+
+	return reply, nil
+}
+
+// transactionStatus returns the lifecycle status of a transaction.
+// The signed document expires from the cache without any event, so expiry is detected here.
+func (c *Client) transactionStatus(ctx context.Context, transactionID string) (*model.TransactionStatus, error) {
+	status, err := c.kv.Transaction.Get(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if status.State == model.TransactionStateSealed && !c.kv.Doc.ExistsSigned(ctx, transactionID) {
+		if err := c.kv.Transaction.Transition(ctx, transactionID, model.TransactionStateExpired, "", ""); err != nil {
+			c.log.Error(err, "failed to record expired state")
+			return nil, err
+		}
+		return c.kv.Transaction.Get(ctx, transactionID)
+	}
+
+	return status, nil
+}
+
+// PDFWebhooksRequest is the request for the webhook delivery attempts of a transaction
+type PDFWebhooksRequest struct {
+	TransactionID  string `uri:"transaction_id" binding:"required"`
+	OrganizationID string `json:"-"`
+}
+
+// PDFWebhooksReply is the reply for the webhook delivery attempts of a transaction
+type PDFWebhooksReply struct {
+	Data []*model.WebhookAttempt `json:"data"`
+}
+
+// PDFWebhooks is the request to list the webhook delivery attempts of a transaction
+//
+//	@Summary		webhook delivery attempts
+//	@ID				pdf-webhooks
+//	@Description	list webhook delivery attempts for a transaction, oldest first
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	PDFWebhooksReply		"Success"
+//	@Failure		400				{object}	helpers.ErrorResponse	"Bad Request"
+//	@Param			transaction_id	path		string					true	"transaction_id"
+//	@Router			/pdf/{transaction_id}/webhooks [get]
+func (c *Client) PDFWebhooks(ctx context.Context, req *PDFWebhooksRequest) (*PDFWebhooksReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:PDFWebhooks")
+	defer span.End()
+
+	meta, err := c.kv.Transaction.GetMeta(ctx, req.TransactionID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if meta.OrganizationID != req.OrganizationID {
+		span.SetStatus(codes.Error, helpers.ErrTransactionNotFound.Error())
+		return nil, helpers.ErrTransactionNotFound
+	}
+
+	attempts, err := c.kv.Webhook.Attempts(ctx, req.TransactionID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply := &PDFWebhooksReply{
+		Data: attempts,
+	}
+
+	return reply, nil
+}
+
 // PDFValidateRequest is the request for verify pdf
 type PDFValidateRequest struct {
 	PDF string `json:"pdf"`
+
+	// Detail is "full" for the per signature report, the default is the summary only
+	Detail string `json:"detail,omitempty" form:"detail"`
+
+	// OrganizationID is set from the caller's jwt
+	OrganizationID string `json:"-"`
 }
 
+const (
+	// ValidateDetailSummary replies with the outcome of the first signature only
+	ValidateDetailSummary = "summary"
+	// ValidateDetailFull adds a report for every signature
+	ValidateDetailFull = "full"
+)
+
 // PDFValidateReply is the reply for verify pdf
 type PDFValidateReply struct {
 	Data *v1_validator.ValidateReply `json:"data"`
@@ -151,41 +590,198 @@ type PDFValidateReply struct {
 //
 //	@Summary		Validate pdf
 //	@ID				pdf-validate
-//	@Description	validate a signed base64 encoded PDF
+//	@Description	validate a signed PDF sent as base64 json, raw application/pdf or multipart/form-data field pdf
 //	@Tags			eduseal
-//	@Accept			json
+//	@Accept			json,application/pdf,mpfd
 //	@Produce		json
 //	@Success		200	{object}	PDFValidateReply		"Success"
 //	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
-//	@Param			req	body		PDFValidateRequest		true	" "
+//	@Param			req		body		PDFValidateRequest		true	" "
+//	@Param			detail	query		string					false	"summary or full, full adds a report for every signature"
 //	@Router			/pdf/validate [post]
 func (c *Client) PDFValidate(ctx context.Context, req *PDFValidateRequest) (*PDFValidateReply, error) {
 	ctx, span := c.tp.Start(ctx, "apiv1:PDFValidate")
 	defer span.End()
 
+	switch req.Detail {
+	case "", ValidateDetailSummary, ValidateDetailFull:
+	default:
+		return nil, helpers.NewErrorDetails("invalid_detail", "detail should be \"summary\" or \"full\"")
+	}
+
 	validation, err := c.grpcClient.Validator.Validate(ctx, uuid.NewString(), req.PDF)
 	if err != nil {
 		return nil, err
 	}
 
+	if req.Detail != ValidateDetailFull {
+		validation.Signatures = nil
+	}
+
 	c.log.Debug("PDFValidate", "validation", validation)
 
 	reply := &PDFValidateReply{
 		Data: validation,
 	}
 
+	outcome := "invalid"
+	switch {
+	case validation.Error != "":
+		outcome = "error"
+	case validation.IntactSignature && validation.ValidSignature:
+		outcome = "valid"
+	}
+	c.metrics.Validations.Add(ctx, 1, otelmetric.WithAttributes(
+		attribute.String("validation_backend", validation.ValidationBackend),
+		attribute.String("outcome", outcome),
+	))
+
 	if err := c.kv.MetricValidations.Inc(ctx); err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		c.log.Error(err, "failed to increment metric")
 		return nil, err
 	}
+	c.countStats(ctx, req.OrganizationID, model.StatsOperationValidations)
 
 	return reply, nil
 }
 
+// PDFValidateTransactionRequest is the request for validating the sealed document of a transaction
+type PDFValidateTransactionRequest struct {
+	TransactionID string `uri:"transaction_id" binding:"required"`
+
+	// Detail is "full" for the per signature report, the default is the summary only
+	Detail string `json:"detail,omitempty" form:"detail"`
+
+	// OrganizationID is set from the caller's jwt
+	OrganizationID string `json:"-"`
+}
+
+// PDFValidateTransaction is the handler for validating an already sealed document again
+//
+//	@Summary		Validate sealed pdf
+//	@ID				pdf-validate-transaction
+//	@Description	validate the sealed document of a transaction, from the cache or the archive, and store the outcome with the transaction, a revoked or suspended document is rejected
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	PDFValidateReply		"Success"
+//	@Failure		400				{object}	helpers.ErrorResponse	"Bad Request"
+//	@Param			transaction_id	path		string					true	"transaction_id"
+//	@Param			detail			query		string					false	"summary or full, full adds a report for every signature"
+//	@Router			/pdf/{transaction_id}/validate [post]
+func (c *Client) PDFValidateTransaction(ctx context.Context, req *PDFValidateTransactionRequest) (*PDFValidateReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:PDFValidateTransaction")
+	defer span.End()
+
+	doc, err := c.sealedDocument(ctx, req.TransactionID, req.OrganizationID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if doc.Data == "" {
+		span.SetStatus(codes.Error, helpers.ErrNoDocumentData.Error())
+		return nil, helpers.ErrNoDocumentData
+	}
+
+	reply, err := c.PDFValidate(ctx, &PDFValidateRequest{
+		PDF:            doc.Data,
+		Detail:         req.Detail,
+		OrganizationID: req.OrganizationID,
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	outcome := &model.ValidationOutcome{
+		ValidatedAt:       time.Now().Unix(),
+		ValidationBackend: reply.Data.ValidationBackend,
+		IntactSignature:   reply.Data.IntactSignature,
+		ValidSignature:    reply.Data.ValidSignature,
+		Error:             reply.Data.Error,
+	}
+
+	if err := c.kv.Transaction.SetValidation(ctx, req.TransactionID, outcome); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		c.log.Error(err, "failed to store validation outcome")
+		return nil, err
+	}
+	if !c.cfg.Common.Mongo.Disable {
+		if err := c.db.EduSealSigningColl.SetValidation(ctx, req.TransactionID, outcome); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			c.log.Error(err, "failed to store validation outcome")
+			return nil, err
+		}
+	}
+
+	return reply, nil
+}
+
+// sealedDocument returns the sealed document of a transaction that belongs to organizationID, from the cache or else the archive.
+// An erased, revoked or suspended document is not returned, a cached document that failed sealing is returned with its error and no data.
+func (c *Client) sealedDocument(ctx context.Context, transactionID, organizationID string) (*model.Document, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:sealedDocument")
+	defer span.End()
+
+	meta, err := c.kv.Transaction.GetMeta(ctx, transactionID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	doc, err := c.kv.Doc.GetSigned(ctx, transactionID)
+	if err == nil && (doc.Data != "" || doc.Error != "") {
+		if meta.OrganizationID != organizationID {
+			return nil, helpers.ErrTransactionNotFound
+		}
+		if !c.cfg.Common.Mongo.Disable {
+			if err := c.db.EduSealSigningColl.CheckStanding(ctx, transactionID); err != nil {
+				span.SetStatus(codes.Error, err.Error())
+				return nil, err
+			}
+		}
+		return doc, nil
+	}
+
+	if c.cfg.Common.Mongo.Disable {
+		return nil, helpers.ErrNoDocumentFound
+	}
+
+	// the kv meta expires before the archive, the archived organization decides then
+	archived, err := c.db.EduSealSigningColl.Get(ctx, transactionID)
+	if err != nil {
+		return nil, helpers.ErrNoDocumentFound
+	}
+	if archived.OrganizationID != organizationID {
+		return nil, helpers.ErrTransactionNotFound
+	}
+	switch {
+	case archived.ErasedAt != 0:
+		return nil, helpers.ErrDocumentIsErased
+	case archived.RevokedAt != 0:
+		return nil, helpers.ErrDocumentIsRevoked
+	case archived.SuspendedAt != 0:
+		return nil, helpers.ErrDocumentIsSuspended
+	}
+	if archived.Data == "" {
+		return nil, helpers.ErrNoDocumentFound
+	}
+
+	return archived, nil
+}
+
 // PDFRevokeRequest is the request for revoke pdf
 type PDFRevokeRequest struct {
 	TransactionID string `uri:"transaction_id" binding:"required"`
+	// ReasonCode defaults to unspecified
+	ReasonCode model.RevocationReason `json:"reason_code" enums:"unspecified,issued_in_error,superseded,withdrawn,fraud"`
+	// Reason is free text kept with the document, it is not published in the revocation feed
+	Reason string `json:"reason"`
+
+	// OrganizationID and Principal are set from the caller's jwt
+	OrganizationID string `json:"-"`
+	Principal      string `json:"-"`
 }
 
 // PDFRevokeReply is the reply for revoke pdf
@@ -199,13 +795,15 @@ type PDFRevokeReply struct {
 //
 //	@Summary		revoke signed pdf
 //	@ID				pdf-revoke
-//	@Description	revoke a singed pdf
+//	@Description	revoke a singed pdf, with a reason code and free text
 //	@Tags			eduseal
 //	@Accept			json
 //	@Produce		json
 //	@Success		200				{object}	PDFRevokeReply			"Success"
 //	@Failure		400				{object}	helpers.ErrorResponse	"Bad Request"
+//	@Failure		404				{object}	helpers.ErrorResponse	"Not Found"
 //	@Param			transaction_id	path		string					true	"transaction_id"
+//	@Param			req				body		PDFRevokeRequest		false	"reason"
 //	@Router			/pdf/revoke/{transaction_id} [put]
 func (c *Client) PDFRevoke(ctx context.Context, req *PDFRevokeRequest) (*PDFRevokeReply, error) {
 	ctx, span := c.tp.Start(ctx, "apiv1:PDFRevoke")
@@ -222,10 +820,36 @@ func (c *Client) PDFRevoke(ctx context.Context, req *PDFRevokeRequest) (*PDFRevo
 		return reply, nil
 	}
 
-	if err := c.db.EduSealSigningColl.Revoke(ctx, req.TransactionID); err != nil {
+	if req.ReasonCode == "" {
+		req.ReasonCode = model.RevocationReasonUnspecified
+	}
+	if !req.ReasonCode.Valid() {
+		return nil, helpers.NewErrorDetails("invalid_reason_code", fmt.Sprintf("reason_code should be one of %v", model.RevocationReasons))
+	}
+	if utf8.RuneCountInString(req.Reason) > maxReasonLength {
+		return nil, helpers.NewErrorDetails("invalid_reason", fmt.Sprintf("reason should be at most %d characters", maxReasonLength))
+	}
+
+	if err := c.db.EduSealSigningColl.Revoke(ctx, &model.Document{
+		TransactionID:  req.TransactionID,
+		OrganizationID: req.OrganizationID,
+		RevokedAt:      time.Now().Unix(),
+		ReasonCode:     req.ReasonCode,
+		Reason:         req.Reason,
+		RevokedBy:      req.Principal,
+	}); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if err := c.kv.Transaction.Transition(ctx, req.TransactionID, model.TransactionStateRevoked, "", ""); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		c.log.Error(err, "failed to record revoked state")
 		return nil, err
 	}
 
+	c.enqueueStateEvent(ctx, req.TransactionID, model.TransactionStateRevoked)
+
 	reply := &PDFRevokeReply{
 		Data: struct {
 			Status bool `json:"status"`
@@ -236,3 +860,14 @@ func (c *Client) PDFRevoke(ctx context.Context, req *PDFRevokeRequest) (*PDFRevo
 
 	return reply, nil
 }
+
+// enqueueStateEvent notifies the transaction's callback_url, if any, that the transaction entered state
+func (c *Client) enqueueStateEvent(ctx context.Context, transactionID string, state model.TransactionState) {
+	if err := c.stream.Webhook.Enqueue(ctx, &model.WebhookEvent{
+		TransactionID: transactionID,
+		Event:         state,
+		TS:            time.Now().Unix(),
+	}); err != nil {
+		c.log.Error(err, "failed to enqueue webhook")
+	}
+}
@@ -0,0 +1,37 @@
+package apiv1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSignWait(t *testing.T) {
+	tts := []struct {
+		name    string
+		wait    string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "no wait", wait: "", want: 0},
+		{name: "seconds", wait: "20s", want: 20 * time.Second},
+		{name: "zero", wait: "0s", want: 0},
+		{name: "capped", wait: "5m", want: maxSignWait},
+		{name: "negative", wait: "-1s", wantErr: true},
+		{name: "no unit", wait: "20", wantErr: true},
+		{name: "not a duration", wait: "soon", wantErr: true},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSignWait(tt.wait)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "invalid_wait")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
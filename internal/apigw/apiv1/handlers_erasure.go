@@ -0,0 +1,241 @@
+package apiv1
+
+import (
+	"context"
+	"crypto/sha256"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// PDFEraseRequest is the request for erasing a transaction
+type PDFEraseRequest struct {
+	TransactionID string `uri:"transaction_id" binding:"required"`
+	// Reason is kept in the tombstone, it can also be given as a query parameter
+	Reason string `json:"reason" form:"reason" binding:"required"`
+
+	// OrganizationID and Principal are set from the caller's jwt
+	OrganizationID string `json:"-"`
+	Principal      string `json:"-"`
+}
+
+// PDFEraseReply is the reply for erasing a transaction
+type PDFEraseReply struct {
+	Data *model.Document `json:"data"`
+}
+
+// PDFErase is the request to erase a transaction everywhere, e.g. when a student exercises the right to erasure
+//
+//	@Summary		erase transaction
+//	@ID				pdf-erase
+//	@Description	remove the document from the kv, the database, pending stream messages, its batch and its idempotency key, a tombstone with the hash, timestamps and reason is kept
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	PDFEraseReply			"Success"
+//	@Failure		400				{object}	helpers.ErrorResponse	"Bad Request"
+//	@Failure		404				{object}	helpers.ErrorResponse	"Not Found"
+//	@Param			transaction_id	path		string					true	"transaction_id"
+//	@Param			req				body		PDFEraseRequest			true	"reason"
+//	@Router			/pdf/{transaction_id} [delete]
+func (c *Client) PDFErase(ctx context.Context, req *PDFEraseRequest) (*PDFEraseReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:PDFErase")
+	defer span.End()
+
+	if err := checkReason(req.Reason); err != nil {
+		return nil, err
+	}
+
+	tombstone, err := c.erasureTombstone(ctx, req.TransactionID, req.OrganizationID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	tombstone.ErasedAt = time.Now().Unix()
+	tombstone.ErasureReason = req.Reason
+	tombstone.ErasedBy = req.Principal
+
+	// the legal hold is checked and the tombstone written in one update, before anything is removed,
+	// so a hold placed after erasureTombstone leaves every copy in place
+	if !c.cfg.Common.Mongo.Disable {
+		if err := c.db.EduSealSigningColl.Erase(ctx, tombstone); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			c.log.Error(err, "failed to erase document", "transaction_id", req.TransactionID)
+			return nil, err
+		}
+	}
+
+	// sealer replies that arrive from now on are dropped
+	if err := c.kv.Erasure.Mark(ctx, req.TransactionID, tombstone.ErasedAt); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if _, err := c.stream.Erase(ctx, req.TransactionID); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		c.log.Error(err, "failed to erase stream messages", "transaction_id", req.TransactionID)
+		return nil, err
+	}
+
+	// the meta names the batch and the idempotency key, it is read before the purge removes it
+	meta, err := c.kv.Transaction.GetMeta(ctx, req.TransactionID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if meta.BatchID != "" {
+		if err := c.kv.Batch.RemoveItem(ctx, meta.BatchID, req.TransactionID); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			c.log.Error(err, "failed to erase batch item", "transaction_id", req.TransactionID)
+			return nil, err
+		}
+	}
+	if meta.IdempotencyKey != "" {
+		if err := c.kv.Idempotency.Forget(ctx, meta.OrganizationID, meta.IdempotencyKey, req.TransactionID); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			c.log.Error(err, "failed to erase idempotency key", "transaction_id", req.TransactionID)
+			return nil, err
+		}
+	}
+
+	if err := c.kv.Erasure.Purge(ctx, req.TransactionID); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		c.log.Error(err, "failed to erase kv", "transaction_id", req.TransactionID)
+		return nil, err
+	}
+
+	c.log.Info("erased transaction", "transaction_id", req.TransactionID, "organization_id", tombstone.OrganizationID, "erased_by", tombstone.ErasedBy)
+
+	reply := &PDFEraseReply{
+		Data: tombstone,
+	}
+
+	return reply, nil
+}
+
+// erasureTombstone checks that the caller may erase the transaction and returns what is kept after the erasure
+func (c *Client) erasureTombstone(ctx context.Context, transactionID, organizationID string) (*model.Document, error) {
+	tombstone := &model.Document{
+		TransactionID: transactionID,
+	}
+	found := false
+
+	if _, err := c.kv.Transaction.Get(ctx, transactionID); err == nil {
+		meta, err := c.kv.Transaction.GetMeta(ctx, transactionID)
+		if err != nil {
+			return nil, err
+		}
+		tombstone.OrganizationID = meta.OrganizationID
+		found = true
+	} else if !errors.Is(err, helpers.ErrTransactionNotFound) {
+		return nil, err
+	}
+
+	if !c.cfg.Common.Mongo.Disable {
+		doc, err := c.db.EduSealSigningColl.Get(ctx, transactionID)
+		if err == nil {
+			if doc.ErasedAt != 0 {
+				return nil, helpers.ErrDocumentIsErased
+			}
+			if doc.LegalHold {
+				return nil, helpers.ErrDocumentUnderLegalHold
+			}
+			tombstone.OrganizationID = doc.OrganizationID
+			tombstone.SHA256 = doc.SHA256
+			tombstone.CreatedAt = doc.CreatedAt
+			tombstone.SealedAt = doc.SealedAt
+			tombstone.RevokedAt = doc.RevokedAt
+			tombstone.ReasonCode = doc.ReasonCode
+			tombstone.RevocationSeq = doc.RevocationSeq
+			found = true
+		}
+	}
+
+	if !found || tombstone.OrganizationID != organizationID {
+		return nil, helpers.ErrTransactionNotFound
+	}
+
+	if tombstone.SHA256 == "" {
+		if signed, err := c.kv.Doc.GetSigned(ctx, transactionID); err == nil && signed.Data != "" {
+			if data, err := decodePDF(signed.Data); err == nil {
+				sum := sha256.Sum256(data)
+				tombstone.SHA256 = hex.EncodeToString(sum[:])
+			}
+		}
+	}
+
+	return tombstone, nil
+}
+
+// PDFLegalHoldRequest is the request for placing or releasing a legal hold
+type PDFLegalHoldRequest struct {
+	TransactionID string `uri:"transaction_id" binding:"required"`
+	Hold          *bool  `json:"hold" binding:"required"`
+	Reason        string `json:"reason" binding:"required"`
+
+	// OrganizationID and Principal are set from the caller's jwt
+	OrganizationID string `json:"-"`
+	Principal      string `json:"-"`
+}
+
+// PDFLegalHoldReply is the reply for placing or releasing a legal hold
+type PDFLegalHoldReply struct {
+	Data struct {
+		Status bool `json:"status"`
+	} `json:"data"`
+}
+
+// PDFLegalHold is the request to place or release a legal hold, a document under legal hold can not be erased
+//
+//	@Summary		legal hold
+//	@ID				pdf-legal-hold
+//	@Description	place, hold=true, or release, hold=false, a legal hold on a signed pdf
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	PDFLegalHoldReply		"Success"
+//	@Failure		400				{object}	helpers.ErrorResponse	"Bad Request"
+//	@Failure		404				{object}	helpers.ErrorResponse	"Not Found"
+//	@Param			transaction_id	path		string					true	"transaction_id"
+//	@Param			req				body		PDFLegalHoldRequest		true	"hold and reason"
+//	@Router			/pdf/legal_hold/{transaction_id} [put]
+func (c *Client) PDFLegalHold(ctx context.Context, req *PDFLegalHoldRequest) (*PDFLegalHoldReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:PDFLegalHold")
+	defer span.End()
+
+	if c.cfg.Common.Mongo.Disable {
+		span.SetStatus(codes.Error, helpers.ErrDatabaseDisabled.Error())
+		return nil, helpers.ErrDatabaseDisabled
+	}
+
+	if req.Hold == nil {
+		return nil, helpers.NewErrorDetails("invalid_hold", "hold is required")
+	}
+	if err := checkReason(req.Reason); err != nil {
+		return nil, err
+	}
+
+	action := model.DocumentActionLegalHoldReleased
+	if *req.Hold {
+		action = model.DocumentActionLegalHoldPlaced
+	}
+
+	if err := c.db.EduSealSigningColl.SetLegalHold(ctx, req.TransactionID, req.OrganizationID, *req.Hold, &model.DocumentEvent{
+		Action: action,
+		Reason: req.Reason,
+		By:     req.Principal,
+		TS:     time.Now().Unix(),
+	}); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply := &PDFLegalHoldReply{}
+	reply.Data.Status = true
+
+	return reply, nil
+}
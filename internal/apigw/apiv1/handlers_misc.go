@@ -11,6 +11,31 @@ func (c *Client) Health(ctx context.Context) (*v1_status.StatusReply, error) {
 	ctx, span := c.tp.Start(ctx, "apiv1:Health")
 	defer span.End()
 
+	status := c.Probes(ctx).Check("apigw")
+
+	return status, nil
+}
+
+// Livez reports that the process is up, it does not depend on any other service
+func (c *Client) Livez(ctx context.Context) (*v1_status.StatusReply, error) {
+	_, span := c.tp.Start(ctx, "apiv1:Livez")
+	defer span.End()
+
+	return model.Probes{}.Check("apigw"), nil
+}
+
+// Readyz return the health of this service and dependencies, it is ready while every critical probe passes
+func (c *Client) Readyz(ctx context.Context) (*v1_status.StatusReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:Readyz")
+	defer span.End()
+
+	return c.Probes(ctx).Check("apigw"), nil
+}
+
+// Probes runs the probes of the services apigw depends on.
+// NATS, the kv and mongo are critical. Without sealers documents wait in the SEAL stream and without validators only
+// validation fails, so those fleets only degrade the service.
+func (c *Client) Probes(ctx context.Context) model.Probes {
 	natsStatus := c.stream.Status(ctx)
 	kvStatus := c.kv.Status(ctx)
 
@@ -23,9 +48,9 @@ func (c *Client) Health(ctx context.Context) (*v1_status.StatusReply, error) {
 		probes = append(probes, c.db.Status(ctx))
 	}
 
-	status := probes.Check("apigw")
+	probes = append(probes, c.stream.SealerProbe(ctx), c.grpcClient.Validator.Probe(ctx))
 
-	return status, nil
+	return probes
 }
 
 // MetricReply is the reply for metrics
@@ -0,0 +1,97 @@
+package apiv1
+
+import (
+	"context"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// maxReasonLength limits the free text reason of a revocation, suspension or reinstatement
+	maxReasonLength = 1024
+	// defaultRevocationsLimit is used when the revocations request has no limit
+	defaultRevocationsLimit = 100
+	// maxRevocationsLimit caps the revocations limit
+	maxRevocationsLimit = 1000
+)
+
+// RevocationsRequest is the request for the revocation feed
+type RevocationsRequest struct {
+	Since  int64  `form:"since"`
+	Cursor string `form:"cursor"`
+	Limit  int64  `form:"limit"`
+}
+
+// RevocationsReply is the reply for the revocation feed
+type RevocationsReply struct {
+	Data []*model.Revocation `json:"data"`
+	// NextCursor continues the feed after the last revocation in data, it is the request cursor when data is empty
+	NextCursor string `json:"next_cursor,omitempty"`
+	// HasMore is true when data is a full page and the next page should be fetched right away
+	HasMore bool `json:"has_more"`
+}
+
+// Revocations is the request for the revocation feed
+//
+//	@Summary		revocation feed
+//	@ID				revocations
+//	@Description	revocations in the order they were recorded, each has an increasing seq, relying parties poll it with the next_cursor of the previous reply
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	RevocationsReply		"Success"
+//	@Failure		400		{object}	helpers.ErrorResponse	"Bad Request"
+//	@Param			since	query		int						false	"unix time, earlier revocations are skipped"
+//	@Param			cursor	query		string					false	"next_cursor of the previous reply"
+//	@Param			limit	query		int						false	"max number of revocations, default 100"
+//	@Router			/revocations [get]
+func (c *Client) Revocations(ctx context.Context, req *RevocationsRequest) (*RevocationsReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:Revocations")
+	defer span.End()
+
+	if c.cfg.Common.Mongo.Disable {
+		span.SetStatus(codes.Error, helpers.ErrDatabaseDisabled.Error())
+		return nil, helpers.ErrDatabaseDisabled
+	}
+
+	if req.Since < 0 {
+		return nil, helpers.NewErrorDetails("invalid_since", "since should be a unix time")
+	}
+
+	after := seqCursor(0)
+	if req.Cursor != "" {
+		var err error
+		after, err = parseSeqCursor(req.Cursor)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultRevocationsLimit
+	}
+	if limit > maxRevocationsLimit {
+		limit = maxRevocationsLimit
+	}
+
+	revocations, err := c.db.EduSealSigningColl.Revocations(ctx, req.Since, int64(after), limit)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply := &RevocationsReply{
+		Data:       revocations,
+		NextCursor: req.Cursor,
+		HasMore:    int64(len(revocations)) == limit,
+	}
+	if n := len(revocations); n > 0 {
+		reply.NextCursor = seqCursor(revocations[n-1].Seq).String()
+	}
+
+	return reply, nil
+}
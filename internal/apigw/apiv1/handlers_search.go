@@ -0,0 +1,111 @@
+package apiv1
+
+import (
+	"context"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// maxLabels is the most labels a transaction can have
+	maxLabels = 32
+	// maxLabelValueLength limits label values and external_reference
+	maxLabelValueLength = 256
+	// defaultSearchLimit is used when the search request has no limit
+	defaultSearchLimit = 100
+	// maxSearchLimit caps the search limit
+	maxSearchLimit = 1000
+)
+
+// labelKey restricts label keys, they become part of database field names
+var labelKey = regexp.MustCompile(`^[A-Za-z0-9_:-]{1,64}$`)
+
+// checkLabels validates the client supplied references of a transaction
+func checkLabels(externalReference string, labels map[string]string) error {
+	if len(externalReference) > maxLabelValueLength {
+		return helpers.NewErrorDetails("invalid_external_reference", fmt.Sprintf("external_reference should be at most %d characters", maxLabelValueLength))
+	}
+	if len(labels) > maxLabels {
+		return helpers.NewErrorDetails("invalid_labels", fmt.Sprintf("at most %d labels are allowed", maxLabels))
+	}
+	for key, value := range labels {
+		if !labelKey.MatchString(key) {
+			return helpers.NewErrorDetails("invalid_labels", fmt.Sprintf("label key %q should be 1-64 characters of a-z, A-Z, 0-9, '_', '-' and ':'", key))
+		}
+		if len(value) > maxLabelValueLength {
+			return helpers.NewErrorDetails("invalid_labels", fmt.Sprintf("label %q should be at most %d characters", key, maxLabelValueLength))
+		}
+	}
+	return nil
+}
+
+// PDFSearchRequest is the request for searching transactions by their client supplied references
+type PDFSearchRequest struct {
+	ExternalReference string            `form:"external_reference"`
+	Labels            map[string]string `form:"labels"`
+	Limit             int64             `form:"limit"`
+
+	// OrganizationID is set from the caller's jwt
+	OrganizationID string `json:"-"`
+}
+
+// PDFSearchReply is the reply for searching transactions
+type PDFSearchReply struct {
+	Data []*model.Document `json:"data"`
+}
+
+// PDFSearch is the request to find transactions by external_reference and labels
+//
+//	@Summary		search transactions
+//	@ID				pdf-search
+//	@Description	find the caller's transactions by external_reference and labels, newest first, e.g. ?labels[course]=TDA123
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200					{object}	PDFSearchReply			"Success"
+//	@Failure		400					{object}	helpers.ErrorResponse	"Bad Request"
+//	@Param			external_reference	query		string					false	"external_reference"
+//	@Param			labels				query		object					false	"labels[key]=value, every label has to match"
+//	@Param			limit				query		int						false	"max number of transactions, default 100"
+//	@Router			/pdf/search [get]
+func (c *Client) PDFSearch(ctx context.Context, req *PDFSearchRequest) (*PDFSearchReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:PDFSearch")
+	defer span.End()
+
+	if c.cfg.Common.Mongo.Disable {
+		span.SetStatus(codes.Error, helpers.ErrDatabaseDisabled.Error())
+		return nil, helpers.ErrDatabaseDisabled
+	}
+
+	if req.ExternalReference == "" && len(req.Labels) == 0 {
+		return nil, helpers.NewErrorDetails("missing_filter", "external_reference or at least one label is required")
+	}
+	if err := checkLabels(req.ExternalReference, req.Labels); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	docs, err := c.db.EduSealSigningColl.Search(ctx, req.OrganizationID, req.ExternalReference, req.Labels, limit)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply := &PDFSearchReply{
+		Data: docs,
+	}
+
+	return reply, nil
+}
@@ -0,0 +1,141 @@
+package apiv1
+
+import (
+	"context"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// maxStatsBuckets caps the number of buckets per organization in one stats reply
+const maxStatsBuckets = 1000
+
+// StatsRequest is the request for usage statistics
+type StatsRequest struct {
+	From        int64  `form:"from"`
+	To          int64  `form:"to"`
+	Granularity string `form:"granularity"`
+	// Organization limits an admin's reply to one organization
+	Organization string `form:"organization_id"`
+
+	// OrganizationID is set from the caller's jwt
+	OrganizationID string `json:"-"`
+}
+
+// StatsReply is the reply for usage statistics
+type StatsReply struct {
+	Data []*OrganizationStats `json:"data"`
+}
+
+// OrganizationStats is the usage of one organization
+type OrganizationStats struct {
+	OrganizationID string               `json:"organization_id"`
+	Granularity    string               `json:"granularity" enums:"hour,day"`
+	Buckets        []*model.StatsBucket `json:"buckets"`
+}
+
+// Stats is the request for usage statistics
+//
+//	@Summary		usage statistics
+//	@ID				stats
+//	@Description	signings, fetches and validations per hour or day, admins get every organization unless organization_id is given
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	StatsReply				"Success"
+//	@Failure		400				{object}	helpers.ErrorResponse	"Bad Request"
+//	@Param			from			query		int						false	"unix time, default 24 hours (hour) or 30 days (day) before to"
+//	@Param			to				query		int						false	"unix time, default now"
+//	@Param			granularity		query		string					false	"hour, the default, or day"
+//	@Param			organization_id	query		string					false	"admins only, one organization"
+//	@Router			/stats [get]
+func (c *Client) Stats(ctx context.Context, req *StatsRequest) (*StatsReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:Stats")
+	defer span.End()
+
+	granularity := model.StatsGranularity(req.Granularity)
+	if granularity == "" {
+		granularity = model.StatsGranularityHour
+	}
+	if !granularity.Valid() {
+		return nil, helpers.NewErrorDetails("invalid_granularity", fmt.Sprintf("granularity should be one of %v", model.StatsGranularities))
+	}
+
+	to := time.Now()
+	if req.To > 0 {
+		to = time.Unix(req.To, 0)
+	}
+	from := to.Add(-24 * time.Hour)
+	if granularity == model.StatsGranularityDay {
+		from = to.AddDate(0, 0, -30)
+	}
+	if req.From > 0 {
+		from = time.Unix(req.From, 0)
+	}
+	if !from.Before(to) {
+		return nil, helpers.NewErrorDetails("invalid_range", "from should be before to")
+	}
+	if n := granularity.BucketCount(from, to); n > maxStatsBuckets {
+		return nil, helpers.NewErrorDetails("invalid_range", fmt.Sprintf("the range spans %d buckets, the max is %d", n, maxStatsBuckets))
+	}
+
+	organizations, err := c.statsOrganizations(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply := &StatsReply{
+		Data: make([]*OrganizationStats, 0, len(organizations)),
+	}
+	for _, organizationID := range organizations {
+		buckets, err := c.kv.Stats.Buckets(ctx, organizationID, granularity, from, to)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		reply.Data = append(reply.Data, &OrganizationStats{
+			OrganizationID: organizationID,
+			Granularity:    string(granularity),
+			Buckets:        buckets,
+		})
+	}
+
+	return reply, nil
+}
+
+// countStats counts the operation in the organization's usage statistics, a failure is only logged since the operation itself succeeded
+func (c *Client) countStats(ctx context.Context, organizationID string, operation model.StatsOperation) {
+	if err := c.kv.Stats.Inc(ctx, organizationID, operation); err != nil {
+		c.log.Error(err, "failed to count stats", "organization_id", organizationID, "operation", operation)
+	}
+}
+
+// statsOrganizations returns the organizations the caller may see and asked for.
+// Without jwt auth there is no organization, every caller is then an admin.
+func (c *Client) statsOrganizations(ctx context.Context, req *StatsRequest) ([]string, error) {
+	admin := req.OrganizationID == "" || c.cfg.APIGW.JWTAuth.IsAdmin(req.OrganizationID)
+
+	if !admin {
+		if req.Organization != "" && req.Organization != req.OrganizationID {
+			return nil, helpers.NewErrorDetails("forbidden", "only admins can read the statistics of another organization")
+		}
+		return []string{req.OrganizationID}, nil
+	}
+
+	if req.Organization != "" {
+		return []string{req.Organization}, nil
+	}
+
+	organizations, err := c.kv.Stats.Organizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(organizations)
+
+	return organizations, nil
+}
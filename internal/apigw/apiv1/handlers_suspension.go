@@ -0,0 +1,160 @@
+package apiv1
+
+import (
+	"context"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// checkReason validates the mandatory free text reason of a suspension or reinstatement
+func checkReason(reason string) error {
+	if reason == "" {
+		return helpers.NewErrorDetails("invalid_reason", "reason is required")
+	}
+	if utf8.RuneCountInString(reason) > maxReasonLength {
+		return helpers.NewErrorDetails("invalid_reason", fmt.Sprintf("reason should be at most %d characters", maxReasonLength))
+	}
+	return nil
+}
+
+// PDFSuspendRequest is the request for suspending a signed pdf
+type PDFSuspendRequest struct {
+	TransactionID string `uri:"transaction_id" binding:"required"`
+	Reason        string `json:"reason" binding:"required"`
+
+	// OrganizationID and Principal are set from the caller's jwt
+	OrganizationID string `json:"-"`
+	Principal      string `json:"-"`
+}
+
+// PDFSuspendReply is the reply for suspending a signed pdf
+type PDFSuspendReply struct {
+	Data struct {
+		Status bool `json:"status"`
+	} `json:"data"`
+}
+
+// PDFSuspend is the request to suspend a signed pdf
+//
+//	@Summary		suspend signed pdf
+//	@ID				pdf-suspend
+//	@Description	hold a signed pdf, e.g. while an investigation runs, until it is reinstated or revoked, the transaction status is suspended meanwhile
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	PDFSuspendReply			"Success"
+//	@Failure		400				{object}	helpers.ErrorResponse	"Bad Request"
+//	@Failure		404				{object}	helpers.ErrorResponse	"Not Found"
+//	@Param			transaction_id	path		string					true	"transaction_id"
+//	@Param			req				body		PDFSuspendRequest		true	"reason"
+//	@Router			/pdf/suspend/{transaction_id} [put]
+func (c *Client) PDFSuspend(ctx context.Context, req *PDFSuspendRequest) (*PDFSuspendReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:PDFSuspend")
+	defer span.End()
+
+	if c.cfg.Common.Mongo.Disable {
+		span.SetStatus(codes.Error, helpers.ErrDatabaseDisabled.Error())
+		return nil, helpers.ErrDatabaseDisabled
+	}
+
+	if err := checkReason(req.Reason); err != nil {
+		return nil, err
+	}
+
+	if err := c.db.EduSealSigningColl.Suspend(ctx, req.TransactionID, req.OrganizationID, &model.DocumentEvent{
+		Action: model.DocumentActionSuspended,
+		Reason: req.Reason,
+		By:     req.Principal,
+		TS:     time.Now().Unix(),
+	}); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if err := c.kv.Transaction.Transition(ctx, req.TransactionID, model.TransactionStateSuspended, "", ""); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		c.log.Error(err, "failed to record suspended state")
+		return nil, err
+	}
+
+	c.enqueueStateEvent(ctx, req.TransactionID, model.TransactionStateSuspended)
+
+	reply := &PDFSuspendReply{}
+	reply.Data.Status = true
+
+	return reply, nil
+}
+
+// PDFReinstateRequest is the request for lifting the suspension of a signed pdf
+type PDFReinstateRequest struct {
+	TransactionID string `uri:"transaction_id" binding:"required"`
+	Reason        string `json:"reason" binding:"required"`
+
+	// OrganizationID and Principal are set from the caller's jwt
+	OrganizationID string `json:"-"`
+	Principal      string `json:"-"`
+}
+
+// PDFReinstateReply is the reply for lifting the suspension of a signed pdf
+type PDFReinstateReply struct {
+	Data struct {
+		Status bool `json:"status"`
+	} `json:"data"`
+}
+
+// PDFReinstate is the request to lift the suspension of a signed pdf
+//
+//	@Summary		reinstate signed pdf
+//	@ID				pdf-reinstate
+//	@Description	lift the suspension of a signed pdf, the transaction status is sealed again
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	PDFReinstateReply		"Success"
+//	@Failure		400				{object}	helpers.ErrorResponse	"Bad Request"
+//	@Failure		404				{object}	helpers.ErrorResponse	"Not Found"
+//	@Param			transaction_id	path		string					true	"transaction_id"
+//	@Param			req				body		PDFReinstateRequest		true	"reason"
+//	@Router			/pdf/reinstate/{transaction_id} [put]
+func (c *Client) PDFReinstate(ctx context.Context, req *PDFReinstateRequest) (*PDFReinstateReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:PDFReinstate")
+	defer span.End()
+
+	if c.cfg.Common.Mongo.Disable {
+		span.SetStatus(codes.Error, helpers.ErrDatabaseDisabled.Error())
+		return nil, helpers.ErrDatabaseDisabled
+	}
+
+	if err := checkReason(req.Reason); err != nil {
+		return nil, err
+	}
+
+	if err := c.db.EduSealSigningColl.Reinstate(ctx, req.TransactionID, req.OrganizationID, &model.DocumentEvent{
+		Action: model.DocumentActionReinstated,
+		Reason: req.Reason,
+		By:     req.Principal,
+		TS:     time.Now().Unix(),
+	}); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	// the rank of sealed is below suspended, so the move back is only made from suspended
+	if _, err := c.kv.Transaction.TransitionFrom(ctx, req.TransactionID, model.TransactionStateSuspended, model.TransactionStateSealed); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		c.log.Error(err, "failed to record reinstated state")
+		return nil, err
+	}
+
+	c.enqueueStateEvent(ctx, req.TransactionID, model.TransactionStateSealed)
+
+	reply := &PDFReinstateReply{}
+	reply.Data.Status = true
+
+	return reply, nil
+}
@@ -0,0 +1,139 @@
+package apiv1
+
+import (
+	"context"
+	"eduseal/internal/apigw/db"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+	"fmt"
+	"slices"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// defaultTransactionsLimit is used when the transactions request has no limit
+	defaultTransactionsLimit = 100
+	// maxTransactionsLimit caps the transactions limit
+	maxTransactionsLimit = 1000
+)
+
+// TransactionsRequest is the request for listing the caller's transactions
+type TransactionsRequest struct {
+	State       string            `form:"state"`
+	CreatedFrom int64             `form:"created_from"`
+	CreatedTo   int64             `form:"created_to"`
+	Labels      map[string]string `form:"labels"`
+	Sort        string            `form:"sort"`
+	Cursor      string            `form:"cursor"`
+	Limit       int64             `form:"limit"`
+
+	// OrganizationID is set from the caller's jwt
+	OrganizationID string `json:"-"`
+}
+
+// TransactionsReply is the reply for listing transactions
+type TransactionsReply struct {
+	Data []*TransactionListItem `json:"data"`
+	// NextCursor continues the listing after the last transaction in data, it is empty when there are no more transactions
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// TransactionListItem is a transaction without its document
+type TransactionListItem struct {
+	*model.Document
+	State string `json:"state" enums:"queued,sealed,failed,revoked,suspended,erased"`
+}
+
+// Transactions is the request to list the caller's transactions
+//
+//	@Summary		list transactions
+//	@ID				transactions
+//	@Description	the caller's transactions, newest first unless sort=created_at, follow next_cursor for the next page
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	TransactionsReply		"Success"
+//	@Failure		400				{object}	helpers.ErrorResponse	"Bad Request"
+//	@Param			state			query		string					false	"queued, sealed, failed, revoked, suspended or erased"
+//	@Param			created_from	query		int						false	"unix time, transactions created at or after it"
+//	@Param			created_to		query		int						false	"unix time, transactions created before it"
+//	@Param			labels			query		object					false	"labels[key]=value, every label has to match"
+//	@Param			sort			query		string					false	"-created_at, the default, or created_at"
+//	@Param			cursor			query		string					false	"next_cursor of the previous reply"
+//	@Param			limit			query		int						false	"max number of transactions, default 100"
+//	@Router			/transactions [get]
+func (c *Client) Transactions(ctx context.Context, req *TransactionsRequest) (*TransactionsReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:Transactions")
+	defer span.End()
+
+	if c.cfg.Common.Mongo.Disable {
+		span.SetStatus(codes.Error, helpers.ErrDatabaseDisabled.Error())
+		return nil, helpers.ErrDatabaseDisabled
+	}
+
+	filter := &db.TransactionFilter{
+		OrganizationID: req.OrganizationID,
+		State:          req.State,
+		CreatedFrom:    req.CreatedFrom,
+		CreatedTo:      req.CreatedTo,
+		Labels:         req.Labels,
+		Limit:          req.Limit,
+	}
+
+	if filter.State != "" && !slices.Contains(db.DocumentStates, filter.State) {
+		return nil, helpers.NewErrorDetails("invalid_state", fmt.Sprintf("state should be one of %v", db.DocumentStates))
+	}
+	if filter.CreatedFrom < 0 || filter.CreatedTo < 0 || (filter.CreatedTo > 0 && filter.CreatedTo <= filter.CreatedFrom) {
+		return nil, helpers.NewErrorDetails("invalid_date_range", "created_from and created_to should be unix times with created_from before created_to")
+	}
+	if err := checkLabels("", filter.Labels); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	switch req.Sort {
+	case "", "-created_at":
+	case "created_at":
+		filter.Ascending = true
+	default:
+		return nil, helpers.NewErrorDetails("invalid_sort", "sort should be created_at or -created_at")
+	}
+
+	if req.Cursor != "" {
+		after, err := parseKeysetCursor(req.Cursor)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		filter.AfterTS, filter.AfterID = after.ts, after.transactionID
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTransactionsLimit
+	}
+	if filter.Limit > maxTransactionsLimit {
+		filter.Limit = maxTransactionsLimit
+	}
+
+	docs, err := c.db.EduSealSigningColl.ListTransactions(ctx, filter)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply := &TransactionsReply{
+		Data: make([]*TransactionListItem, 0, len(docs)),
+	}
+	for _, doc := range docs {
+		reply.Data = append(reply.Data, &TransactionListItem{
+			Document: doc,
+			State:    db.DocumentState(doc),
+		})
+	}
+	if n := len(docs); int64(n) == filter.Limit {
+		reply.NextCursor = keysetCursor{ts: docs[n-1].CreatedAt, transactionID: docs[n-1].TransactionID}.String()
+	}
+
+	return reply, nil
+}
@@ -0,0 +1,76 @@
+package apiv1
+
+import (
+	"context"
+	"eduseal/pkg/kvclient"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// RequestRateBucket is the rate limit bucket of an organization's api requests
+const RequestRateBucket = "requests"
+
+// UsageRequest is the request for the caller's rate limit and quota usage
+type UsageRequest struct {
+	// OrganizationID is set from the caller's jwt
+	OrganizationID string `json:"-"`
+}
+
+// UsageReply is the reply for the caller's rate limit and quota usage
+type UsageReply struct {
+	Data *Usage `json:"data"`
+}
+
+// Usage is the current usage of an organization's limits, a zero limit is unlimited
+type Usage struct {
+	OrganizationID    string               `json:"organization_id"`
+	RequestsPerSecond *kvclient.QuotaUsage `json:"requests_per_second"`
+	DailySeals        *kvclient.QuotaUsage `json:"daily_seals"`
+	MonthlySeals      *kvclient.QuotaUsage `json:"monthly_seals"`
+}
+
+// Usage is the request for the caller's rate limit and quota usage
+//
+//	@Summary		usage
+//	@ID				usage
+//	@Description	current usage of the caller's request rate limit and sealing quotas
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	UsageReply				"Success"
+//	@Failure		400	{object}	helpers.ErrorResponse	"Bad Request"
+//	@Router			/usage [get]
+func (c *Client) Usage(ctx context.Context, req *UsageRequest) (*UsageReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:Usage")
+	defer span.End()
+
+	limits := c.cfg.APIGW.JWTAuth.LimitsFor(req.OrganizationID)
+
+	requests, err := c.kv.RateLimit.SlidingUsage(ctx, RequestRateBucket, req.OrganizationID, time.Second)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	quotas, err := c.kv.Quota.Usage(ctx, req.OrganizationID, limits)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply := &UsageReply{
+		Data: &Usage{
+			OrganizationID: req.OrganizationID,
+			RequestsPerSecond: &kvclient.QuotaUsage{
+				Limit:    limits.RequestsPerSecond,
+				Used:     requests,
+				ResetsAt: time.Now().Add(time.Second).Unix(),
+			},
+			DailySeals:   quotas["daily_seals"],
+			MonthlySeals: quotas["monthly_seals"],
+		},
+	}
+
+	return reply, nil
+}
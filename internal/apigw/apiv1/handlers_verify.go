@@ -0,0 +1,106 @@
+package apiv1
+
+import (
+	"context"
+	"eduseal/pkg/helpers"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+var (
+	// VerifyStatusSealed is returned when the document was sealed and is still valid
+	VerifyStatusSealed = "sealed"
+	// VerifyStatusRevoked is returned when the document was sealed but has been revoked
+	VerifyStatusRevoked = "revoked"
+	// VerifyStatusSuspended is returned when the document is held until it is reinstated or revoked
+	VerifyStatusSuspended = "suspended"
+	// VerifyStatusErased is returned when the document was sealed but has since been erased
+	VerifyStatusErased = "erased"
+)
+
+// sha256Hex matches a hex encoded sha256 hash
+var sha256Hex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// PDFVerifyRequest is the request for verifying a sealed pdf by its hash
+type PDFVerifyRequest struct {
+	SHA256 string `uri:"sha256" binding:"required"`
+}
+
+// PDFVerifyReply is the reply for verifying a sealed pdf by its hash
+type PDFVerifyReply struct {
+	Data *VerifyResult `json:"data"`
+}
+
+// VerifyResult is what is publicly known about a sealed pdf, the document itself is never returned
+type VerifyResult struct {
+	SHA256         string `json:"sha256"`
+	Status         string `json:"status" enums:"sealed,revoked,suspended,erased"`
+	SealedAt       int64  `json:"sealed_at"`
+	OrganizationID string `json:"organization_id"`
+	RevokedAt      int64  `json:"revoked_at,omitempty"`
+	SuspendedAt    int64  `json:"suspended_at,omitempty"`
+	ErasedAt       int64  `json:"erased_at,omitempty"`
+	ReasonCode     string `json:"reason_code,omitempty"`
+}
+
+// PDFVerify is the request to verify a sealed pdf by the sha256 of the file, without uploading it
+//
+//	@Summary		verify sealed pdf by hash
+//	@ID				pdf-verify
+//	@Description	public and rate limited check that a pdf was sealed here and is not revoked or suspended, by the hex encoded sha256 of the file
+//	@Tags			eduseal
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	PDFVerifyReply			"Success"
+//	@Failure		400		{object}	helpers.ErrorResponse	"Bad Request"
+//	@Failure		404		{object}	helpers.ErrorResponse	"Not Found"
+//	@Failure		429		{object}	helpers.ErrorResponse	"Too Many Requests"
+//	@Param			sha256	path		string					true	"sha256"
+//	@Router			/verify/{sha256} [get]
+func (c *Client) PDFVerify(ctx context.Context, req *PDFVerifyRequest) (*PDFVerifyReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:PDFVerify")
+	defer span.End()
+
+	if c.cfg.Common.Mongo.Disable {
+		span.SetStatus(codes.Error, helpers.ErrDatabaseDisabled.Error())
+		return nil, helpers.ErrDatabaseDisabled
+	}
+
+	hash := strings.ToLower(req.SHA256)
+	if !sha256Hex.MatchString(hash) {
+		return nil, helpers.NewErrorDetails("invalid_sha256", "sha256 should be 64 hex characters")
+	}
+
+	doc, err := c.db.EduSealSigningColl.GetBySHA256(ctx, hash)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	result := &VerifyResult{
+		SHA256:         hash,
+		Status:         VerifyStatusSealed,
+		SealedAt:       doc.SealedAt,
+		OrganizationID: doc.OrganizationID,
+	}
+	switch {
+	case doc.ErasedAt != 0:
+		result.Status = VerifyStatusErased
+		result.ErasedAt = doc.ErasedAt
+	case doc.RevokedAt != 0:
+		result.Status = VerifyStatusRevoked
+		result.RevokedAt = doc.RevokedAt
+		result.ReasonCode = string(doc.ReasonCode)
+	case doc.SuspendedAt != 0:
+		result.Status = VerifyStatusSuspended
+		result.SuspendedAt = doc.SuspendedAt
+	}
+
+	reply := &PDFVerifyReply{
+		Data: result,
+	}
+
+	return reply, nil
+}
@@ -0,0 +1,119 @@
+package apiv1
+
+import (
+	"context"
+	"crypto/sha256"
+	"eduseal/internal/gen/sealer/v1_sealer"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// maxIdempotencyKeyLength limits the Idempotency-Key header
+const maxIdempotencyKeyLength = 255
+
+// reserveIdempotencyKey maps the request's Idempotency-Key to transactionID.
+// It returns the transaction id of the original request when the key is already used, else an empty string.
+func (c *Client) reserveIdempotencyKey(ctx context.Context, req *PDFSignRequest, transactionID string) (string, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:reserveIdempotencyKey")
+	defer span.End()
+
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLength {
+		return "", helpers.NewErrorDetails("invalid_idempotency_key", "Idempotency-Key should be at most 255 characters")
+	}
+
+	hash, err := signRequestHash(req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+
+	original, err := c.kv.Idempotency.Reserve(ctx, req.OrganizationID, req.IdempotencyKey, &model.IdempotencyRecord{
+		TransactionID: transactionID,
+		RequestHash:   hash,
+		CreatedAt:     time.Now().Unix(),
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+	if original == nil {
+		return "", nil
+	}
+
+	if original.RequestHash != hash {
+		return "", helpers.ErrIdempotencyKeyReused
+	}
+
+	return original.TransactionID, nil
+}
+
+// signRequestHash is the hash of everything that affects the sealed document, wait is left out since a retry may wait differently
+func signRequestHash(req *PDFSignRequest) (string, error) {
+	b, err := json.Marshal(struct {
+		PDF         string                         `json:"pdf"`
+		CallbackURL string                         `json:"callback_url"`
+		Metadata    *v1_sealer.SignatureMetadata   `json:"metadata"`
+		Appearance  *v1_sealer.SignatureAppearance `json:"appearance"`
+		Reference   string                         `json:"external_reference"`
+		Labels      map[string]string              `json:"labels"`
+	}{
+		PDF:         req.PDF,
+		CallbackURL: req.CallbackURL,
+		Metadata:    req.Metadata,
+		Appearance:  req.Appearance,
+		Reference:   req.ExternalReference,
+		Labels:      req.Labels,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// replaySign returns the reply of the original request, in synchronous mode it waits for the original transaction
+func (c *Client) replaySign(ctx context.Context, transactionID string, wait time.Duration) (*PDFSignReply, error) {
+	ctx, span := c.tp.Start(ctx, "apiv1:replaySign")
+	defer span.End()
+
+	reply := &PDFSignReply{
+		Data: &v1_sealer.SealReply{
+			TransactionId: transactionID,
+		},
+	}
+
+	if wait == 0 {
+		return reply, nil
+	}
+
+	// subscribe before looking at the status, the document might be sealed in between
+	waiter, err := c.stream.NewSealedWaiter(ctx, transactionID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	defer waiter.Close()
+
+	status, err := c.transactionStatus(ctx, transactionID)
+	switch {
+	case errors.Is(err, helpers.ErrTransactionNotFound):
+		// the original request has not published yet
+	case err != nil:
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	case status.State == model.TransactionStateSealed || status.State == model.TransactionStateFailed:
+		return c.sealedReply(ctx, transactionID)
+	case status.State == model.TransactionStateExpired || status.State == model.TransactionStateSuspended || status.State == model.TransactionStateRevoked:
+		reply.Status = string(status.State)
+		return reply, nil
+	}
+
+	return c.waitForSealed(ctx, waiter, transactionID, wait)
+}
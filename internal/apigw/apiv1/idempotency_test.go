@@ -0,0 +1,174 @@
+package apiv1
+
+import (
+	"context"
+	"eduseal/internal/gen/sealer/v1_sealer"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/kvclient"
+	"eduseal/pkg/logger"
+	"eduseal/pkg/model"
+	"eduseal/pkg/trace"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// newTestClient returns a client with a kv on an in-memory redis
+func newTestClient(t *testing.T) *Client {
+	mr := miniredis.RunT(t)
+
+	rootCA := filepath.Join(t.TempDir(), "rootCA.pem")
+	require.NoError(t, os.WriteFile(rootCA, nil, 0600))
+
+	cfg := &model.Cfg{
+		Common: model.Common{Redict: model.Redict{Nodes: []string{mr.Addr()}}},
+		APIGW:  model.APIGW{ClientCert: model.TLS{RootCAPath: rootCA}},
+	}
+	tp := &trace.Tracer{Tracer: noop.NewTracerProvider().Tracer("")}
+	log := logger.NewSimple("test")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	kv, err := kvclient.New(ctx, cfg, tp, log)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, kv.Close(context.Background()))
+	})
+
+	return &Client{cfg: cfg, kv: kv, tp: tp, log: log}
+}
+
+func TestSignRequestHash(t *testing.T) {
+	req := func() *PDFSignRequest {
+		return &PDFSignRequest{
+			PDF:               "JVBERi0xLjcK",
+			CallbackURL:       "https://example.com/callback",
+			Metadata:          &v1_sealer.SignatureMetadata{Reason: "diploma"},
+			ExternalReference: "student-1",
+			Labels:            map[string]string{"course": "math"},
+		}
+	}
+
+	tts := []struct {
+		name   string
+		change func(*PDFSignRequest)
+		same   bool
+	}{
+		{
+			name:   "same request",
+			change: func(*PDFSignRequest) {},
+			same:   true,
+		},
+		{
+			name:   "other wait",
+			change: func(r *PDFSignRequest) { r.Wait = "20s" },
+			same:   true,
+		},
+		{
+			name:   "other idempotency key",
+			change: func(r *PDFSignRequest) { r.IdempotencyKey = "retry" },
+			same:   true,
+		},
+		{
+			name:   "other document",
+			change: func(r *PDFSignRequest) { r.PDF = "JVBERi0xLjQK" },
+		},
+		{
+			name:   "other callback",
+			change: func(r *PDFSignRequest) { r.CallbackURL = "https://example.org/callback" },
+		},
+		{
+			name:   "other metadata",
+			change: func(r *PDFSignRequest) { r.Metadata.Reason = "transcript" },
+		},
+		{
+			name:   "other label",
+			change: func(r *PDFSignRequest) { r.Labels["course"] = "physics" },
+		},
+	}
+
+	want, err := signRequestHash(req())
+	require.NoError(t, err)
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			r := req()
+			tt.change(r)
+
+			got, err := signRequestHash(r)
+			require.NoError(t, err)
+			if tt.same {
+				assert.Equal(t, want, got)
+			} else {
+				assert.NotEqual(t, want, got)
+			}
+		})
+	}
+}
+
+func TestReserveIdempotencyKey(t *testing.T) {
+	tts := []struct {
+		name          string
+		first         *PDFSignRequest
+		retry         *PDFSignRequest
+		wantOriginal  bool
+		wantErr       error
+		wantErrString string
+	}{
+		{
+			name:         "retry",
+			first:        &PDFSignRequest{PDF: "JVBERi0xLjcK", OrganizationID: "org-1", IdempotencyKey: "key"},
+			retry:        &PDFSignRequest{PDF: "JVBERi0xLjcK", OrganizationID: "org-1", IdempotencyKey: "key", Wait: "10s"},
+			wantOriginal: true,
+		},
+		{
+			name:    "key reused for another document",
+			first:   &PDFSignRequest{PDF: "JVBERi0xLjcK", OrganizationID: "org-1", IdempotencyKey: "key"},
+			retry:   &PDFSignRequest{PDF: "JVBERi0xLjQK", OrganizationID: "org-1", IdempotencyKey: "key"},
+			wantErr: helpers.ErrIdempotencyKeyReused,
+		},
+		{
+			name:  "same key in another organization",
+			first: &PDFSignRequest{PDF: "JVBERi0xLjcK", OrganizationID: "org-1", IdempotencyKey: "key"},
+			retry: &PDFSignRequest{PDF: "JVBERi0xLjcK", OrganizationID: "org-2", IdempotencyKey: "key"},
+		},
+		{
+			name:          "key too long",
+			retry:         &PDFSignRequest{PDF: "JVBERi0xLjcK", OrganizationID: "org-1", IdempotencyKey: strings.Repeat("k", maxIdempotencyKeyLength+1)},
+			wantErrString: "invalid_idempotency_key",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t)
+			ctx := context.Background()
+
+			if tt.first != nil {
+				original, err := c.reserveIdempotencyKey(ctx, tt.first, "transaction-1")
+				require.NoError(t, err)
+				require.Empty(t, original)
+			}
+
+			original, err := c.reserveIdempotencyKey(ctx, tt.retry, "transaction-2")
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantErrString != "":
+				assert.ErrorContains(t, err, tt.wantErrString)
+			case tt.wantOriginal:
+				require.NoError(t, err)
+				assert.Equal(t, "transaction-1", original)
+			default:
+				require.NoError(t, err)
+				assert.Empty(t, original)
+			}
+		})
+	}
+}
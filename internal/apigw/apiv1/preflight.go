@@ -0,0 +1,104 @@
+package apiv1
+
+import (
+	"context"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/pdfinspect"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// defaultPreflightMaxSize is used when apigw.preflight.max_size is not configured
+	defaultPreflightMaxSize = 32 << 20
+	// defaultPreflightMaxPages is used when apigw.preflight.max_pages is not configured
+	defaultPreflightMaxPages = 2000
+	// sealMessageOverhead is the room a stream message needs besides the base64 document,
+	// the other fields of the SEAL message and the signature the sealer adds to the CACHE message
+	sealMessageOverhead = 64 << 10
+)
+
+// preflight inspects a base64 pdf before it is queued, a document the sealer would fail on is rejected with the
+// failed checks as details, rather than failing through the cache long after the client got its reply.
+// The parsed document is returned for the checks that need it.
+func (c *Client) preflight(ctx context.Context, pdf, organizationID string) (*pdfinspect.Document, error) {
+	cfg := c.cfg.APIGW.Preflight
+	limits := pdfinspect.Limits{
+		MaxSize:         cfg.MaxSize,
+		MaxPages:        cfg.MaxPages,
+		AllowJavaScript: cfg.AllowJavaScript,
+		RejectSigned:    cfg.RejectSigned,
+		RequirePDFA:     cfg.RequirePDFA,
+	}
+	if limits.MaxSize <= 0 {
+		limits.MaxSize = defaultPreflightMaxSize
+	}
+	if limits.MaxPages <= 0 {
+		limits.MaxPages = defaultPreflightMaxPages
+	}
+	if maxEncoded := c.maxEncodedPDF(); maxEncoded > 0 {
+		limits.MaxSize = min(limits.MaxSize, maxEncoded/4*3)
+	}
+
+	var report *pdfinspect.Report
+	// the decoded size follows from the encoded length, so a huge document is rejected without decoding it
+	if size := int64(len(pdf))/4*3 - 2; size > limits.MaxSize {
+		report = &pdfinspect.Report{Size: size, Problems: []pdfinspect.Problem{{
+			Check:   pdfinspect.CheckSize,
+			Message: fmt.Sprintf("document is at least %d bytes, the limit is %d bytes", size, limits.MaxSize),
+		}}}
+	} else {
+		data, err := decodePDF(pdf)
+		if err != nil {
+			return nil, helpers.NewErrorDetails("invalid_pdf", []pdfinspect.Problem{{Check: "encoding", Message: "pdf is not base64 encoded"}})
+		}
+		report = pdfinspect.Preflight(data, limits)
+	}
+
+	if !report.OK() {
+		for _, problem := range report.Problems {
+			c.metrics.PreflightRejections.Add(ctx, 1, otelmetric.WithAttributes(
+				attribute.String("organization_id", organizationID),
+				attribute.String("check", problem.Check),
+			))
+		}
+		c.log.Debug("preflight rejected document", "organization_id", organizationID, "problems", report.Problems)
+		return nil, helpers.NewErrorDetails("invalid_pdf", report.Problems)
+	}
+
+	return report.Document(), nil
+}
+
+// maxEncodedPDF is the largest base64 document that fits in one stream message, zero when the limit is not known yet.
+// The document travels in a single nats message, so the nats server max_payload bounds the configured size limits.
+func (c *Client) maxEncodedPDF() int64 {
+	return max(c.stream.MaxPayload()-sealMessageOverhead, 0)
+}
+
+// preflightProblems returns the preflight error found in the chain of err and its failed checks
+func preflightProblems(err error) (*helpers.Error, []pdfinspect.Problem) {
+	var e *helpers.Error
+	if errors.As(err, &e) {
+		if problems, ok := e.Details.([]pdfinspect.Problem); ok {
+			return e, problems
+		}
+	}
+	return nil, nil
+}
+
+// preflightText is a preflight error on one line, e.g. for the error of a batch item
+func preflightText(err error) string {
+	e, problems := preflightProblems(err)
+	if e == nil {
+		return err.Error()
+	}
+	parts := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		parts = append(parts, fmt.Sprintf("%s: %s", problem.Check, problem.Message))
+	}
+	return fmt.Sprintf("%s, %s", e.Title, strings.Join(parts, ", "))
+}
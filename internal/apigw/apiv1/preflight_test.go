@@ -0,0 +1,56 @@
+package apiv1
+
+import (
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/pdfinspect"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreflightText(t *testing.T) {
+	problems := []pdfinspect.Problem{
+		{Check: pdfinspect.CheckSize, Message: "too large"},
+		{Check: "encoding", Message: "pdf is not base64 encoded"},
+	}
+
+	tts := []struct {
+		name         string
+		err          error
+		want         string
+		wantProblems []pdfinspect.Problem
+	}{
+		{
+			name:         "preflight error",
+			err:          helpers.NewErrorDetails("invalid_pdf", problems),
+			want:         "invalid_pdf, size: too large, encoding: pdf is not base64 encoded",
+			wantProblems: problems,
+		},
+		{
+			name:         "wrapped preflight error",
+			err:          fmt.Errorf("item 3: %w", helpers.NewErrorDetails("invalid_pdf", problems[:1])),
+			want:         "invalid_pdf, size: too large",
+			wantProblems: problems[:1],
+		},
+		{
+			name: "error without problems",
+			err:  helpers.NewError("invalid_pdf"),
+			want: "Error: [invalid_pdf]",
+		},
+		{
+			name: "other error",
+			err:  errors.New("stream is closed"),
+			want: "stream is closed",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, preflightText(tt.err))
+			_, got := preflightProblems(tt.err)
+			assert.Equal(t, tt.wantProblems, got)
+		})
+	}
+}
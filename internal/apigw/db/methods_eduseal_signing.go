@@ -2,12 +2,13 @@ package db
 
 import (
 	"context"
+	"eduseal/pkg/helpers"
 	"eduseal/pkg/model"
 	"errors"
-	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.opentelemetry.io/otel/codes"
 )
 
@@ -15,16 +16,38 @@ import (
 type EduSealSigningColl struct {
 	service *Service
 	coll    *mongo.Collection
+	// counters holds the sequences, e.g. of the revocation feed
+	counters *mongo.Collection
 }
 
+// revocationCounter is the counters document that numbers the revocations
+const revocationCounter = "revocations"
+
 func (c *EduSealSigningColl) createIndex(ctx context.Context) error {
 	ctx, span := c.service.tp.Start(ctx, "db:doc:createIndex")
 	defer span.End()
 
-	indexModel := mongo.IndexModel{
-		Keys: bson.M{"transaction_id": 1},
+	indexModels := []mongo.IndexModel{
+		{
+			Keys: bson.M{"transaction_id": 1},
+		},
+		{
+			Keys: bson.D{{Key: "organization_id", Value: 1}, {Key: "external_reference", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "organization_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "transaction_id", Value: -1}},
+		},
+		{
+			Keys: bson.M{"labels.$**": 1},
+		},
+		{
+			Keys: bson.M{"revocation_seq": 1},
+		},
+		{
+			Keys: bson.M{"sha256": 1},
+		},
 	}
-	_, err := c.coll.Indexes().CreateOne(ctx, indexModel)
+	_, err := c.coll.Indexes().CreateMany(ctx, indexModels)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		return err
@@ -47,9 +70,70 @@ func (c *EduSealSigningColl) Save(ctx context.Context, doc *model.Document) erro
 	return nil
 }
 
-// Revoke revokes a document
-func (c *EduSealSigningColl) Revoke(ctx context.Context, transactionID string) error {
-	ctx, span := c.service.tp.Start(ctx, "db:doc:revoke")
+// SaveSealed records the outcome of sealing the transaction, the transaction is created if it was not saved when it was queued.
+// The sealed document itself is only stored when doc.Data is set, that is when the archive is enabled.
+// An erasure tombstone is left as it is, a sealer reply can arrive after the erasure.
+func (c *EduSealSigningColl) SaveSealed(ctx context.Context, doc *model.Document) error {
+	ctx, span := c.service.tp.Start(ctx, "db:doc:saveSealed")
+	defer span.End()
+
+	filter := bson.M{
+		"transaction_id": bson.M{"$eq": doc.TransactionID},
+	}
+	set := bson.M{
+		"sealer_backend": doc.SealerBackend,
+		"error":          doc.Error,
+		"sealed_at":      doc.SealedAt,
+		"sha256":         doc.SHA256,
+	}
+	if doc.Data != "" {
+		set["base64_data"] = doc.Data
+	}
+	notErased := bson.M{
+		"transaction_id": bson.M{"$eq": doc.TransactionID},
+		"erased_at":      bson.M{"$exists": false},
+	}
+	res, err := c.coll.UpdateOne(ctx, notErased, bson.M{"$set": set})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	if res.MatchedCount == 1 {
+		return nil
+	}
+
+	// no document or a tombstone, $setOnInsert only writes when there is no document, the filter sets the transaction_id
+	if _, err := c.coll.UpdateOne(ctx, filter, bson.M{"$setOnInsert": set}, options.Update().SetUpsert(true)); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+// GetBySHA256 gets the sealed document with the hash, without the document data
+func (c *EduSealSigningColl) GetBySHA256(ctx context.Context, sha256 string) (*model.Document, error) {
+	ctx, span := c.service.tp.Start(ctx, "db:doc:getBySHA256")
+	defer span.End()
+
+	reply := &model.Document{}
+	filter := bson.M{
+		"sha256": bson.M{"$eq": sha256},
+	}
+	opts := options.FindOne().SetProjection(bson.M{"base64_data": 0})
+	if err := c.coll.FindOne(ctx, filter, opts).Decode(reply); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			span.SetStatus(codes.Ok, "document not found")
+			return nil, helpers.ErrNoDocumentFound
+		}
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+// SetValidation stores the outcome of the latest validation of the transaction's sealed document
+func (c *EduSealSigningColl) SetValidation(ctx context.Context, transactionID string, outcome *model.ValidationOutcome) error {
+	ctx, span := c.service.tp.Start(ctx, "db:doc:setValidation")
 	defer span.End()
 
 	filter := bson.M{
@@ -57,7 +141,7 @@ func (c *EduSealSigningColl) Revoke(ctx context.Context, transactionID string) e
 	}
 	update := bson.M{
 		"$set": bson.M{
-			"revoked_ts": time.Now().Unix(),
+			"validation": outcome,
 		},
 	}
 	_, err := c.coll.UpdateOne(ctx, filter, update)
@@ -68,21 +152,347 @@ func (c *EduSealSigningColl) Revoke(ctx context.Context, transactionID string) e
 	return nil
 }
 
+// nextSeq increments the counter and returns its new value
+func (c *EduSealSigningColl) nextSeq(ctx context.Context, counter string) (int64, error) {
+	filter := bson.M{
+		"_id": bson.M{"$eq": counter},
+	}
+	update := bson.M{
+		"$inc": bson.M{"seq": int64(1)},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	reply := struct {
+		Seq int64 `bson:"seq"`
+	}{}
+	if err := c.counters.FindOneAndUpdate(ctx, filter, update, opts).Decode(&reply); err != nil {
+		return 0, err
+	}
+	return reply.Seq, nil
+}
+
+// Revoke revokes the document of doc.TransactionID, restricted to doc.OrganizationID when set.
+// The revocation fields are taken from doc, a document is only revoked once, a suspended document can be revoked.
+// The revocation gets the next revocation sequence, a failed revocation leaves a gap in the sequence.
+func (c *EduSealSigningColl) Revoke(ctx context.Context, doc *model.Document) error {
+	ctx, span := c.service.tp.Start(ctx, "db:doc:revoke")
+	defer span.End()
+
+	seq, err := c.nextSeq(ctx, revocationCounter)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"revocation_seq": seq,
+			"revoked_at":     doc.RevokedAt,
+			"reason_code":    doc.ReasonCode,
+			"reason":         doc.Reason,
+			"revoked_by":     doc.RevokedBy,
+		},
+		"$unset": bson.M{
+			"suspended_at":   "",
+			"suspend_reason": "",
+		},
+		"$push": bson.M{
+			"history": &model.DocumentEvent{
+				Action:     model.DocumentActionRevoked,
+				ReasonCode: doc.ReasonCode,
+				Reason:     doc.Reason,
+				By:         doc.RevokedBy,
+				TS:         doc.RevokedAt,
+			},
+		},
+	}
+	if err := c.changeStanding(ctx, doc.TransactionID, doc.OrganizationID, bson.M{}, update, helpers.ErrDocumentIsRevoked); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+// Suspend holds a sealed document until it is reinstated or revoked
+func (c *EduSealSigningColl) Suspend(ctx context.Context, transactionID, organizationID string, event *model.DocumentEvent) error {
+	ctx, span := c.service.tp.Start(ctx, "db:doc:suspend")
+	defer span.End()
+
+	precondition := bson.M{
+		"suspended_at": bson.M{"$in": bson.A{0, nil}},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"suspended_at":   event.TS,
+			"suspend_reason": event.Reason,
+		},
+		"$push": bson.M{
+			"history": event,
+		},
+	}
+	if err := c.changeStanding(ctx, transactionID, organizationID, precondition, update, helpers.ErrDocumentIsSuspended); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+// Reinstate lifts the suspension of a sealed document
+func (c *EduSealSigningColl) Reinstate(ctx context.Context, transactionID, organizationID string, event *model.DocumentEvent) error {
+	ctx, span := c.service.tp.Start(ctx, "db:doc:reinstate")
+	defer span.End()
+
+	precondition := bson.M{
+		"suspended_at": bson.M{"$gt": 0},
+	}
+	update := bson.M{
+		"$unset": bson.M{
+			"suspended_at":   "",
+			"suspend_reason": "",
+		},
+		"$push": bson.M{
+			"history": event,
+		},
+	}
+	if err := c.changeStanding(ctx, transactionID, organizationID, precondition, update, helpers.ErrDocumentNotSuspended); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+// changeStanding applies update to a document that is neither revoked nor erased and matches precondition, restricted to organizationID when set.
+// It returns ErrTransactionNotFound for unknown documents, ErrDocumentIsErased or ErrDocumentIsRevoked,
+// and errPrecondition when precondition does not match.
+func (c *EduSealSigningColl) changeStanding(ctx context.Context, transactionID, organizationID string, precondition, update bson.M, errPrecondition error) error {
+	filter := bson.M{
+		"transaction_id": bson.M{"$eq": transactionID},
+	}
+	if organizationID != "" {
+		filter["organization_id"] = bson.M{"$eq": organizationID}
+	}
+
+	guarded := bson.M{
+		"revoked_at": bson.M{"$in": bson.A{0, nil}},
+		"erased_at":  bson.M{"$exists": false},
+	}
+	for k, v := range filter {
+		guarded[k] = v
+	}
+	for k, v := range precondition {
+		guarded[k] = v
+	}
+
+	res, err := c.coll.UpdateOne(ctx, guarded, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 1 {
+		return nil
+	}
+
+	doc := &model.Document{}
+	opts := options.FindOne().SetProjection(bson.M{"revoked_at": 1, "erased_at": 1})
+	if err := c.coll.FindOne(ctx, filter, opts).Decode(doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return helpers.ErrTransactionNotFound
+		}
+		return err
+	}
+	if doc.ErasedAt != 0 {
+		return helpers.ErrDocumentIsErased
+	}
+	if doc.RevokedAt != 0 {
+		return helpers.ErrDocumentIsRevoked
+	}
+
+	return errPrecondition
+}
+
+// Revocations returns revocations in the order they were revoked, starting at since.
+// afterSeq is the sequence of the last revocation already returned, the sequence is taken when a document is revoked,
+// so a revocation with an earlier revoked_at or a smaller transaction id is not skipped.
+func (c *EduSealSigningColl) Revocations(ctx context.Context, since, afterSeq int64, limit int64) ([]*model.Revocation, error) {
+	ctx, span := c.service.tp.Start(ctx, "db:doc:revocations")
+	defer span.End()
+
+	filter := bson.M{
+		"revoked_at":     bson.M{"$gte": max(since, 1)},
+		"revocation_seq": bson.M{"$gt": afterSeq},
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "revocation_seq", Value: 1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"revocation_seq": 1, "transaction_id": 1, "revoked_at": 1, "reason_code": 1})
+
+	cursor, err := c.coll.Find(ctx, filter, opts)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply := []*model.Revocation{}
+	if err := cursor.All(ctx, &reply); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// SetLegalHold places or releases a legal hold, a document under legal hold can not be erased
+func (c *EduSealSigningColl) SetLegalHold(ctx context.Context, transactionID, organizationID string, hold bool, event *model.DocumentEvent) error {
+	ctx, span := c.service.tp.Start(ctx, "db:doc:setLegalHold")
+	defer span.End()
+
+	filter := bson.M{
+		"transaction_id": bson.M{"$eq": transactionID},
+		"erased_at":      bson.M{"$exists": false},
+	}
+	if organizationID != "" {
+		filter["organization_id"] = bson.M{"$eq": organizationID}
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"legal_hold": hold,
+		},
+		"$push": bson.M{
+			"history": event,
+		},
+	}
+	res, err := c.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	if res.MatchedCount == 0 {
+		span.SetStatus(codes.Error, helpers.ErrTransactionNotFound.Error())
+		return helpers.ErrTransactionNotFound
+	}
+	return nil
+}
+
+// Erase replaces the document with the tombstone, unless the document is under legal hold
+func (c *EduSealSigningColl) Erase(ctx context.Context, tombstone *model.Document) error {
+	ctx, span := c.service.tp.Start(ctx, "db:doc:erase")
+	defer span.End()
+
+	filter := bson.M{
+		"transaction_id": bson.M{"$eq": tombstone.TransactionID},
+		"legal_hold":     bson.M{"$ne": true},
+	}
+	// only what is needed to account for the erasure is kept, revocations stay in the revocation feed
+	replacement := bson.M{
+		"transaction_id":  tombstone.TransactionID,
+		"organization_id": tombstone.OrganizationID,
+		"sha256":          tombstone.SHA256,
+		"created_at":      tombstone.CreatedAt,
+		"sealed_at":       tombstone.SealedAt,
+		"revoked_at":      tombstone.RevokedAt,
+		"reason_code":     tombstone.ReasonCode,
+		"revocation_seq":  tombstone.RevocationSeq,
+		"erased_at":       tombstone.ErasedAt,
+		"erasure_reason":  tombstone.ErasureReason,
+		"erased_by":       tombstone.ErasedBy,
+	}
+	res, err := c.coll.ReplaceOne(ctx, filter, replacement, options.Replace().SetUpsert(false))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	if res.MatchedCount == 0 {
+		// the hold was placed after the caller checked it, or the document is only known to the kv
+		if c.IsUnderLegalHold(ctx, tombstone.TransactionID) {
+			span.SetStatus(codes.Error, helpers.ErrDocumentUnderLegalHold.Error())
+			return helpers.ErrDocumentUnderLegalHold
+		}
+		if _, err := c.coll.InsertOne(ctx, replacement); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// standing returns the revocation, suspension, legal hold and erasure fields of a document, without its data
+func (c *EduSealSigningColl) standing(ctx context.Context, transactionID string) (*model.Document, error) {
+	reply := &model.Document{}
+	filter := bson.M{
+		"transaction_id": bson.M{"$eq": transactionID},
+	}
+	opts := options.FindOne().SetProjection(bson.M{"revoked_at": 1, "suspended_at": 1, "legal_hold": 1, "erased_at": 1})
+	if err := c.coll.FindOne(ctx, filter, opts).Decode(reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// IsUnderLegalHold checks if a document is under legal hold
+func (c *EduSealSigningColl) IsUnderLegalHold(ctx context.Context, transactionID string) bool {
+	ctx, span := c.service.tp.Start(ctx, "db:doc:isUnderLegalHold")
+	defer span.End()
+
+	doc, err := c.standing(ctx, transactionID)
+	if err != nil {
+		span.SetStatus(codes.Ok, "document not found")
+		return false
+	}
+
+	return doc.LegalHold
+}
+
 // IsRevoked checks if a document is revoked
 func (c *EduSealSigningColl) IsRevoked(ctx context.Context, transactionID string) bool {
 	ctx, span := c.service.tp.Start(ctx, "db:doc:isRevoked")
 	defer span.End()
 
-	doc, err := c.Get(ctx, transactionID)
+	doc, err := c.standing(ctx, transactionID)
 	if err != nil {
 		span.SetStatus(codes.Ok, "document not found")
 		return false
 	}
 
-	if doc.RevokedAt != 0 {
-		return true
+	return doc.RevokedAt != 0
+}
+
+// IsSuspended checks if a document is suspended
+func (c *EduSealSigningColl) IsSuspended(ctx context.Context, transactionID string) bool {
+	ctx, span := c.service.tp.Start(ctx, "db:doc:isSuspended")
+	defer span.End()
+
+	doc, err := c.standing(ctx, transactionID)
+	if err != nil {
+		span.SetStatus(codes.Ok, "document not found")
+		return false
+	}
+
+	return doc.SuspendedAt != 0
+}
+
+// CheckStanding returns ErrDocumentIsErased, ErrDocumentIsRevoked or ErrDocumentIsSuspended when the document may not be handed out, unknown documents are fine
+func (c *EduSealSigningColl) CheckStanding(ctx context.Context, transactionID string) error {
+	ctx, span := c.service.tp.Start(ctx, "db:doc:checkStanding")
+	defer span.End()
+
+	doc, err := c.standing(ctx, transactionID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		span.SetStatus(codes.Error, err.Error())
+		return err
 	}
-	return false
+
+	switch {
+	case doc.ErasedAt != 0:
+		return helpers.ErrDocumentIsErased
+	case doc.RevokedAt != 0:
+		return helpers.ErrDocumentIsRevoked
+	case doc.SuspendedAt != 0:
+		return helpers.ErrDocumentIsSuspended
+	}
+	return nil
 }
 
 // Get gets one document
@@ -104,3 +514,161 @@ func (c *EduSealSigningColl) Get(ctx context.Context, transactionID string) (*mo
 	}
 	return reply, nil
 }
+
+// Search returns the documents of organizationID that have externalReference, if set, and every label in labels, newest first
+func (c *EduSealSigningColl) Search(ctx context.Context, organizationID, externalReference string, labels map[string]string, limit int64) ([]*model.Document, error) {
+	ctx, span := c.service.tp.Start(ctx, "db:doc:search")
+	defer span.End()
+
+	filter := bson.M{
+		"organization_id": bson.M{"$eq": organizationID},
+	}
+	if externalReference != "" {
+		filter["external_reference"] = bson.M{"$eq": externalReference}
+	}
+	for key, value := range labels {
+		filter["labels."+key] = bson.M{"$eq": value}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"base64_data": 0})
+
+	cursor, err := c.coll.Find(ctx, filter, opts)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply := []*model.Document{}
+	if err := cursor.All(ctx, &reply); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// TransactionFilter selects the transactions of one organization for ListTransactions
+type TransactionFilter struct {
+	OrganizationID string
+	// State is one of the states of DocumentState, empty means any
+	State string
+	// CreatedFrom and CreatedTo limit created_at to [CreatedFrom, CreatedTo), zero means unbounded
+	CreatedFrom int64
+	CreatedTo   int64
+	Labels      map[string]string
+	// Ascending sorts oldest first, the default is newest first
+	Ascending bool
+	// AfterTS and AfterID is the position of the last transaction already returned
+	AfterTS int64
+	AfterID string
+	Limit   int64
+}
+
+// stateConditions are the document states in the order DocumentState decides them, with the condition that
+// gives the state and the one that rules it out, a document is queued when none of them is given
+var stateConditions = []struct {
+	state      string
+	field      string
+	set, unset bson.M
+}{
+	{"erased", "erased_at", bson.M{"$exists": true}, bson.M{"$exists": false}},
+	{"revoked", "revoked_at", bson.M{"$gt": 0}, bson.M{"$in": bson.A{0, nil}}},
+	{"suspended", "suspended_at", bson.M{"$gt": 0}, bson.M{"$in": bson.A{0, nil}}},
+	{"failed", "error", bson.M{"$nin": bson.A{"", nil}}, bson.M{"$in": bson.A{"", nil}}},
+	{"sealed", "sealed_at", bson.M{"$gt": 0}, bson.M{"$in": bson.A{0, nil}}},
+}
+
+// stateFilter matches the documents DocumentState puts in state, the states that take precedence are ruled out
+func stateFilter(state string) bson.M {
+	filter := bson.M{}
+	for _, condition := range stateConditions {
+		if condition.state == state {
+			filter[condition.field] = condition.set
+			return filter
+		}
+		filter[condition.field] = condition.unset
+	}
+	return filter
+}
+
+// DocumentStates are the states ListTransactions can filter on
+var DocumentStates = []string{"queued", "sealed", "failed", "revoked", "suspended", "erased"}
+
+// DocumentState is the state of a stored document, erased, revoked and suspended take precedence over how sealing went
+func DocumentState(doc *model.Document) string {
+	switch {
+	case doc.ErasedAt != 0:
+		return "erased"
+	case doc.RevokedAt != 0:
+		return "revoked"
+	case doc.SuspendedAt != 0:
+		return "suspended"
+	case doc.Error != "":
+		return "failed"
+	case doc.SealedAt != 0:
+		return "sealed"
+	default:
+		return "queued"
+	}
+}
+
+// ListTransactions returns one page of an organization's transactions, without the document data, sorted on created_at and transaction id
+func (c *EduSealSigningColl) ListTransactions(ctx context.Context, f *TransactionFilter) ([]*model.Document, error) {
+	ctx, span := c.service.tp.Start(ctx, "db:doc:listTransactions")
+	defer span.End()
+
+	filter := bson.M{
+		"organization_id": bson.M{"$eq": f.OrganizationID},
+	}
+	if f.State != "" {
+		for k, v := range stateFilter(f.State) {
+			filter[k] = v
+		}
+	}
+	created := bson.M{}
+	if f.CreatedFrom > 0 {
+		created["$gte"] = f.CreatedFrom
+	}
+	if f.CreatedTo > 0 {
+		created["$lt"] = f.CreatedTo
+	}
+	if len(created) > 0 {
+		filter["created_at"] = created
+	}
+	for key, value := range f.Labels {
+		filter["labels."+key] = bson.M{"$eq": value}
+	}
+
+	order, next := -1, "$lt"
+	if f.Ascending {
+		order, next = 1, "$gt"
+	}
+	if f.AfterID != "" {
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{next: f.AfterTS}},
+			bson.M{"created_at": bson.M{"$eq": f.AfterTS}, "transaction_id": bson.M{next: f.AfterID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: order}, {Key: "transaction_id", Value: order}}).
+		SetLimit(f.Limit).
+		SetProjection(bson.M{"base64_data": 0, "history": 0})
+
+	cursor, err := c.coll.Find(ctx, filter, opts)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply := []*model.Document{}
+	if err := cursor.All(ctx, &reply); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return reply, nil
+}
@@ -56,8 +56,9 @@ func New(ctx context.Context, cfg *model.Cfg, tp *trace.Tracer, log *logger.Log)
 		}
 
 		service.EduSealSigningColl = &EduSealSigningColl{
-			service: service,
-			coll:    service.dbClient.Database("eduseal").Collection("documents"),
+			service:  service,
+			coll:     service.dbClient.Database("eduseal").Collection("documents"),
+			counters: service.dbClient.Database("eduseal").Collection("counters"),
 		}
 		if err := service.EduSealSigningColl.createIndex(ctx); err != nil {
 			return nil, err
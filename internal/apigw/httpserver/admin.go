@@ -0,0 +1,269 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"eduseal/internal/apigw/apiv1"
+	"eduseal/pkg/helpers"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// minAdminTokenLength is the shortest operator token accepted, e.g. 32 hex characters from openssl rand -hex 16
+const minAdminTokenLength = 32
+
+// placeholderAdminTokens are parts of sample tokens, a token holding one is refused whatever its length
+var placeholderAdminTokens = []string{"change-me", "changeme", "replace-me", "example"}
+
+// startAdmin serves the operator api and the prometheus metrics on its own address, they are only reachable with an operator token.
+// The metrics are labelled per organization, so they are not served on the public address.
+func (s *Service) startAdmin(ctx context.Context) error {
+	if len(s.config.APIGW.Admin.Tokens) == 0 {
+		return errors.New("apigw.admin.tokens is empty, the admin api would be unreachable")
+	}
+	for name, token := range s.config.APIGW.Admin.Tokens {
+		placeholder := slices.ContainsFunc(placeholderAdminTokens, func(part string) bool {
+			return strings.Contains(strings.ToLower(token), part)
+		})
+		if placeholder || len(token) < minAdminTokenLength {
+			return fmt.Errorf("apigw.admin.tokens %s is a placeholder or shorter than %d characters", name, minAdminTokenLength)
+		}
+	}
+
+	engine := gin.New()
+	if err := engine.SetTrustedProxies(nil); err != nil {
+		return err
+	}
+	engine.Use(s.middlewareRequestID(ctx))
+	engine.Use(s.middlewareLogger(ctx))
+	engine.Use(s.middlewareCrash(ctx))
+	engine.Use(s.middlewareAdminAuth(ctx))
+	problem404, err := helpers.Problem404()
+	if err != nil {
+		return err
+	}
+	engine.NoRoute(func(c *gin.Context) { c.JSON(http.StatusNotFound, problem404) })
+
+	rgAdmin := engine.Group("/admin/v1")
+	s.regEndpoint(ctx, rgAdmin, http.MethodGet, "/streams", s.endpointAdminStreams)
+	s.regEndpoint(ctx, rgAdmin, http.MethodPut, "/sealer/pause", s.endpointAdminPauseSealer)
+	s.regEndpoint(ctx, rgAdmin, http.MethodPut, "/sealer/resume", s.endpointAdminResumeSealer)
+	s.regEndpoint(ctx, rgAdmin, http.MethodDelete, "/transactions/:transaction_id/messages", s.endpointAdminPurge)
+	s.regEndpoint(ctx, rgAdmin, http.MethodPost, "/transactions/:transaction_id/republish", s.endpointAdminRepublish)
+	s.regEndpoint(ctx, rgAdmin, http.MethodGet, "/dlq", s.endpointAdminDLQList)
+	s.regEndpoint(ctx, rgAdmin, http.MethodGet, "/dlq/:seq", s.endpointAdminDLQGet)
+	s.regEndpoint(ctx, rgAdmin, http.MethodPost, "/dlq/:seq/replay", s.endpointAdminDLQReplay)
+	s.regEndpoint(ctx, rgAdmin, http.MethodDelete, "/dlq/:seq", s.endpointAdminDLQDiscard)
+
+	prometheusPath := s.config.APIGW.Prometheus.Path
+	if prometheusPath == "" {
+		prometheusPath = defaultPrometheusPath
+	}
+	engine.GET(prometheusPath, s.endpointPrometheus(ctx))
+
+	s.adminServer = &http.Server{
+		Addr:              s.config.APIGW.Admin.Addr,
+		Handler:           engine,
+		ReadHeaderTimeout: 2 * time.Second,
+		ReadTimeout:       5 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       90 * time.Second,
+	}
+
+	go func() {
+		s.logger.Info("Admin ListenAndServe", "addr", s.config.APIGW.Admin.Addr, "tls", s.config.APIGW.Admin.TLS.Enabled)
+		var err error
+		if s.config.APIGW.Admin.TLS.Enabled {
+			s.adminServer.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+			err = s.adminServer.ListenAndServeTLS(s.config.APIGW.Admin.TLS.CertFilePath, s.config.APIGW.Admin.TLS.KeyFilePath)
+		} else {
+			err = s.adminServer.ListenAndServe()
+		}
+		if err != nil {
+			s.logger.Error(err, "admin_listen_and_serve")
+		}
+	}()
+
+	return nil
+}
+
+// middlewareAdminAuth requires one of the configured operator tokens, the operator name becomes the principal
+func (s *Service) middlewareAdminAuth(ctx context.Context) gin.HandlerFunc {
+	log := s.logger.New("middlewareAdminAuth")
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if found && token != "" {
+			for name, operatorToken := range s.config.APIGW.Admin.Tokens {
+				if subtle.ConstantTimeCompare([]byte(token), []byte(operatorToken)) == 1 {
+					c.Set("principal", name)
+					c.Next()
+					return
+				}
+			}
+		}
+
+		log.Info("rejected admin request", "url", c.Request.URL.Path, "client_ip", c.ClientIP())
+		renderContent(c, 401, gin.H{"data": nil, "error": helpers.Error{
+			Title:   "unauthorized",
+			Details: "a valid operator token is required",
+		}})
+		c.Abort()
+	}
+}
+
+func (s *Service) endpointAdminStreams(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointAdminStreams")
+	defer span.End()
+
+	reply, err := s.apiv1.AdminStreams(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointAdminPauseSealer(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointAdminPauseSealer")
+	defer span.End()
+
+	request := &apiv1.AdminPauseSealerRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.Principal = c.GetString("principal")
+	reply, err := s.apiv1.AdminPauseSealer(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointAdminResumeSealer(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointAdminResumeSealer")
+	defer span.End()
+
+	request := &apiv1.AdminResumeSealerRequest{Principal: c.GetString("principal")}
+	reply, err := s.apiv1.AdminResumeSealer(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointAdminPurge(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointAdminPurge")
+	defer span.End()
+
+	request := &apiv1.AdminTransactionRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.Principal = c.GetString("principal")
+	reply, err := s.apiv1.AdminPurge(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointAdminRepublish(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointAdminRepublish")
+	defer span.End()
+
+	request := &apiv1.AdminTransactionRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.Principal = c.GetString("principal")
+	reply, err := s.apiv1.AdminRepublish(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointAdminDLQList(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointAdminDLQList")
+	defer span.End()
+
+	request := &apiv1.AdminDLQListRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.AdminDLQList(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointAdminDLQGet(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointAdminDLQGet")
+	defer span.End()
+
+	request := &apiv1.AdminDLQRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.AdminDLQGet(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointAdminDLQReplay(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointAdminDLQReplay")
+	defer span.End()
+
+	request := &apiv1.AdminDLQRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.Principal = c.GetString("principal")
+	reply, err := s.apiv1.AdminDLQReplay(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *Service) endpointAdminDLQDiscard(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointAdminDLQDiscard")
+	defer span.End()
+
+	request := &apiv1.AdminDLQRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.Principal = c.GetString("principal")
+	reply, err := s.apiv1.AdminDLQDiscard(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
@@ -11,10 +11,39 @@ type Apiv1 interface {
 	// eduSeal endpoints
 	PDFSign(ctx context.Context, req *apiv1.PDFSignRequest) (*apiv1.PDFSignReply, error)
 	PDFValidate(ctx context.Context, req *apiv1.PDFValidateRequest) (*apiv1.PDFValidateReply, error)
+	PDFValidateTransaction(ctx context.Context, req *apiv1.PDFValidateTransactionRequest) (*apiv1.PDFValidateReply, error)
+	PDFSignBatch(ctx context.Context, req *apiv1.PDFSignBatchRequest) (*apiv1.PDFSignBatchReply, error)
+	BatchGet(ctx context.Context, req *apiv1.BatchGetRequest) (*apiv1.BatchGetReply, error)
+	BatchZIP(ctx context.Context, req *apiv1.BatchZIPRequest) (*apiv1.BatchZIPReply, error)
 	PDFGetSigned(ctx context.Context, req *apiv1.PDFGetSignedRequest) (*apiv1.PDFGetSignedReply, error)
+	PDFStatus(ctx context.Context, req *apiv1.PDFStatusRequest) (*apiv1.PDFStatusReply, error)
+	PDFWebhooks(ctx context.Context, req *apiv1.PDFWebhooksRequest) (*apiv1.PDFWebhooksReply, error)
 	PDFRevoke(ctx context.Context, req *apiv1.PDFRevokeRequest) (*apiv1.PDFRevokeReply, error)
+	PDFSuspend(ctx context.Context, req *apiv1.PDFSuspendRequest) (*apiv1.PDFSuspendReply, error)
+	PDFReinstate(ctx context.Context, req *apiv1.PDFReinstateRequest) (*apiv1.PDFReinstateReply, error)
+	PDFErase(ctx context.Context, req *apiv1.PDFEraseRequest) (*apiv1.PDFEraseReply, error)
+	PDFLegalHold(ctx context.Context, req *apiv1.PDFLegalHoldRequest) (*apiv1.PDFLegalHoldReply, error)
+	PDFSearch(ctx context.Context, req *apiv1.PDFSearchRequest) (*apiv1.PDFSearchReply, error)
+	PDFVerify(ctx context.Context, req *apiv1.PDFVerifyRequest) (*apiv1.PDFVerifyReply, error)
+	Transactions(ctx context.Context, req *apiv1.TransactionsRequest) (*apiv1.TransactionsReply, error)
+	Usage(ctx context.Context, req *apiv1.UsageRequest) (*apiv1.UsageReply, error)
+	Stats(ctx context.Context, req *apiv1.StatsRequest) (*apiv1.StatsReply, error)
+	Revocations(ctx context.Context, req *apiv1.RevocationsRequest) (*apiv1.RevocationsReply, error)
+
+	// admin endpoints
+	AdminStreams(ctx context.Context) (*apiv1.AdminStreamsReply, error)
+	AdminPauseSealer(ctx context.Context, req *apiv1.AdminPauseSealerRequest) (*apiv1.AdminSealerReply, error)
+	AdminResumeSealer(ctx context.Context, req *apiv1.AdminResumeSealerRequest) (*apiv1.AdminSealerReply, error)
+	AdminPurge(ctx context.Context, req *apiv1.AdminTransactionRequest) (*apiv1.AdminPurgeReply, error)
+	AdminRepublish(ctx context.Context, req *apiv1.AdminTransactionRequest) (*apiv1.AdminRepublishReply, error)
+	AdminDLQList(ctx context.Context, req *apiv1.AdminDLQListRequest) (*apiv1.AdminDLQListReply, error)
+	AdminDLQGet(ctx context.Context, req *apiv1.AdminDLQRequest) (*apiv1.AdminDLQReply, error)
+	AdminDLQReplay(ctx context.Context, req *apiv1.AdminDLQRequest) (*apiv1.AdminDLQReply, error)
+	AdminDLQDiscard(ctx context.Context, req *apiv1.AdminDLQRequest) (*apiv1.AdminDLQReply, error)
 
 	// misc endpoints
 	Health(ctx context.Context) (*v1_status.StatusReply, error)
+	Livez(ctx context.Context) (*v1_status.StatusReply, error)
+	Readyz(ctx context.Context) (*v1_status.StatusReply, error)
 	Metrics(ctx context.Context) (*apiv1.MetricReply, error)
 }
@@ -2,7 +2,11 @@ package httpserver
 
 import (
 	"context"
+	"eduseal/pkg/helpers"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"net/http"
 	"reflect"
 
 	"github.com/bytedance/sonic"
@@ -76,3 +80,60 @@ func (s *Service) bindRequestQuery(ctx context.Context, c *gin.Context, v any) e
 	}
 	return nil
 }
+
+const (
+	// mimePDF is the content type of raw pdf bodies and downloads
+	mimePDF = "application/pdf"
+	// maxPDFBodySize limits raw and multipart pdf uploads
+	maxPDFBodySize = 64 << 20
+	// maxSignBodySize limits json sign and validate requests, the largest pdf base64 encoded and room for the other fields
+	maxSignBodySize = maxPDFBodySize/3*4 + 1<<20
+	// pdfFormField is the multipart form field that holds the pdf file
+	pdfFormField = "pdf"
+)
+
+// isPDFBody reports if the request carries the pdf as raw bytes or as a multipart file instead of base64 json
+func isPDFBody(c *gin.Context) bool {
+	switch c.ContentType() {
+	case mimePDF, gin.MIMEMultipartPOSTForm:
+		return true
+	}
+	return false
+}
+
+// bindPDF reads a raw or multipart pdf body and returns it base64 encoded, as the sealer and validator expects it
+func (s *Service) bindPDF(ctx context.Context, c *gin.Context) (string, error) {
+	_, span := s.tp.Start(ctx, "httpserver:bindPDF")
+	defer span.End()
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPDFBodySize)
+
+	var body io.Reader
+	switch c.ContentType() {
+	case mimePDF:
+		body = c.Request.Body
+	case gin.MIMEMultipartPOSTForm:
+		file, err := c.FormFile(pdfFormField)
+		if err != nil {
+			return "", helpers.NewErrorDetails("missing_pdf", fmt.Sprintf("multipart field %q is missing", pdfFormField))
+		}
+		f, err := file.Open()
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		body = f
+	default:
+		return "", helpers.NewErrorDetails("unsupported_content_type", fmt.Sprintf("content type %q is not supported", c.ContentType()))
+	}
+
+	pdf, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	if len(pdf) == 0 {
+		return "", helpers.NewErrorDetails("empty_pdf", "the pdf is empty")
+	}
+
+	return base64.StdEncoding.EncodeToString(pdf), nil
+}
@@ -1,8 +1,15 @@
 package httpserver
 
 import (
+	"bytes"
 	"context"
 	"eduseal/internal/apigw/apiv1"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/metric"
+	"eduseal/pkg/model"
+	"encoding/base64"
+	"io"
+	"net/http"
 
 	"go.opentelemetry.io/otel/codes"
 
@@ -20,6 +27,37 @@ func (s *Service) endpointHealth(ctx context.Context, c *gin.Context) (any, erro
 	return reply, nil
 }
 
+func (s *Service) endpointLivez(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointLivez")
+	defer span.End()
+
+	reply, err := s.apiv1.Livez(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// endpointReadyz replies 503 while a critical probe fails, so load balancers stop sending traffic
+func (s *Service) endpointReadyz(ctx context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span := s.tp.Start(ctx, "httpserver:endpointReadyz")
+		defer span.End()
+
+		reply, err := s.apiv1.Readyz(ctx)
+		if err != nil {
+			renderContent(c, http.StatusServiceUnavailable, gin.H{"error": helpers.NewErrorFromError(err)})
+			return
+		}
+
+		code := http.StatusOK
+		if !model.Probes(reply.GetData().GetProbes()).Ready() {
+			code = http.StatusServiceUnavailable
+		}
+		renderContent(c, code, reply)
+	}
+}
+
 func (s *Service) endpointMetrics(ctx context.Context, c *gin.Context) (any, error) {
 	ctx, span := s.tp.Start(ctx, "httpserver:endpointMetrics")
 	defer span.End()
@@ -31,16 +69,51 @@ func (s *Service) endpointMetrics(ctx context.Context, c *gin.Context) (any, err
 	return reply, nil
 }
 
+// endpointPrometheus serves the apigw instruments in the prometheus text format
+func (s *Service) endpointPrometheus(ctx context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span := s.tp.Start(ctx, "httpserver:endpointPrometheus")
+		defer span.End()
+
+		var buf bytes.Buffer
+		if err := s.metrics.WritePrometheus(ctx, &buf); err != nil {
+			s.logger.Error(err, "failed to collect metrics")
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		c.Data(http.StatusOK, metric.PrometheusContentType, buf.Bytes())
+	}
+}
+
 // endpointSignPDF signs a PDF EduSeal
 func (s *Service) endpointSignPDF(ctx context.Context, c *gin.Context) (interface{}, error) {
 	ctx, span := s.tp.Start(ctx, "httpserver:endpointSignPDF")
 	defer span.End()
 
 	request := &apiv1.PDFSignRequest{}
-	if err := s.bindV2(ctx, c, request); err != nil {
+	if isPDFBody(c) {
+		pdf, err := s.bindPDF(ctx, c)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		request.PDF = pdf
+		request.Wait = c.PostForm("wait")
+		request.CallbackURL = c.DefaultPostForm("callback_url", c.Query("callback_url"))
+		request.ExternalReference = c.DefaultPostForm("external_reference", c.Query("external_reference"))
+		request.Labels = c.PostFormMap("labels")
+		if len(request.Labels) == 0 {
+			request.Labels = c.QueryMap("labels")
+		}
+	} else if err := s.bindV2(ctx, c, request); err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
+	if request.Wait == "" {
+		request.Wait = c.Query("wait")
+	}
+	request.OrganizationID = c.GetString("organization_id")
+	request.IdempotencyKey = c.GetHeader("Idempotency-Key")
 	reply, err := s.apiv1.PDFSign(ctx, request)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
@@ -49,16 +122,89 @@ func (s *Service) endpointSignPDF(ctx context.Context, c *gin.Context) (interfac
 	return reply, nil
 }
 
+// endpointSignPDFBatch signs many PDFs EduSeal
+func (s *Service) endpointSignPDFBatch(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointSignPDFBatch")
+	defer span.End()
+
+	request := &apiv1.PDFSignBatchRequest{}
+	if err := s.bindV2(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.OrganizationID = c.GetString("organization_id")
+	reply, err := s.apiv1.PDFSignBatch(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+// endpointBatchGet returns the state of every document in a batch
+func (s *Service) endpointBatchGet(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointBatchGet")
+	defer span.End()
+
+	request := &apiv1.BatchGetRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.OrganizationID = c.GetString("organization_id")
+	reply, err := s.apiv1.BatchGet(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+// endpointBatchZIP returns the sealed documents of a batch as one zip
+func (s *Service) endpointBatchZIP(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointBatchZIP")
+	defer span.End()
+
+	request := &apiv1.BatchZIPRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.OrganizationID = c.GetString("organization_id")
+	reply, err := s.apiv1.BatchZIP(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return &fileReply{
+		contentType: "application/zip",
+		fileName:    reply.FileName,
+		write:       reply.WriteZIP,
+	}, nil
+}
+
 // endpointValidatePDF validates a signed PDF EduSeal
 func (s *Service) endpointValidatePDF(ctx context.Context, c *gin.Context) (interface{}, error) {
 	ctx, span := s.tp.Start(ctx, "httpserver:endpointValidatePDF")
 	defer span.End()
 
 	request := &apiv1.PDFValidateRequest{}
-	if err := s.bindV2(ctx, c, request); err != nil {
+	if isPDFBody(c) {
+		pdf, err := s.bindPDF(ctx, c)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		request.PDF = pdf
+		request.Detail = c.PostForm("detail")
+	} else if err := s.bindV2(ctx, c, request); err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
+	if request.Detail == "" {
+		request.Detail = c.Query("detail")
+	}
+	request.OrganizationID = c.GetString("organization_id")
 	reply, err := s.apiv1.PDFValidate(ctx, request)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
@@ -67,6 +213,25 @@ func (s *Service) endpointValidatePDF(ctx context.Context, c *gin.Context) (inte
 	return reply, nil
 }
 
+// endpointValidateTransaction validates the sealed PDF of a transaction
+func (s *Service) endpointValidateTransaction(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointValidateTransaction")
+	defer span.End()
+
+	request := &apiv1.PDFValidateTransactionRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.OrganizationID = c.GetString("organization_id")
+	reply, err := s.apiv1.PDFValidateTransaction(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
 // endpointGetSignedPDF returns a signed PDF EduSeal
 func (s *Service) endpointGetSignedPDF(ctx context.Context, c *gin.Context) (interface{}, error) {
 	ctx, span := s.tp.Start(ctx, "httpserver:endpointGetSignedPDF")
@@ -77,11 +242,67 @@ func (s *Service) endpointGetSignedPDF(ctx context.Context, c *gin.Context) (int
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
+	request.OrganizationID = c.GetString("organization_id")
 	reply, err := s.apiv1.PDFGetSigned(ctx, request)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
+
+	if c.NegotiateFormat(gin.MIMEJSON, mimePDF) == mimePDF && reply.Data != nil && reply.Data.Data != "" {
+		pdf, err := base64.StdEncoding.DecodeString(reply.Data.Data)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		return &fileReply{
+			contentType: mimePDF,
+			fileName:    request.TransactionID + ".pdf",
+			write: func(w io.Writer) error {
+				_, err := w.Write(pdf)
+				return err
+			},
+		}, nil
+	}
+
+	return reply, nil
+}
+
+// endpointPDFStatus returns the lifecycle status of a transaction
+func (s *Service) endpointPDFStatus(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointPDFStatus")
+	defer span.End()
+
+	request := &apiv1.PDFStatusRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.OrganizationID = c.GetString("organization_id")
+	reply, err := s.apiv1.PDFStatus(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+// endpointPDFWebhooks returns the webhook delivery attempts of a transaction
+func (s *Service) endpointPDFWebhooks(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointPDFWebhooks")
+	defer span.End()
+
+	request := &apiv1.PDFWebhooksRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.OrganizationID = c.GetString("organization_id")
+	reply, err := s.apiv1.PDFWebhooks(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
 	return reply, nil
 }
 
@@ -95,6 +316,8 @@ func (s *Service) endpointPDFRevoke(ctx context.Context, c *gin.Context) (any, e
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
+	request.OrganizationID = c.GetString("organization_id")
+	request.Principal = c.GetString("principal")
 	reply, err := s.apiv1.PDFRevoke(ctx, request)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
@@ -102,3 +325,192 @@ func (s *Service) endpointPDFRevoke(ctx context.Context, c *gin.Context) (any, e
 	}
 	return reply, nil
 }
+
+// endpointPDFSuspend holds a signed PDF EduSeal
+func (s *Service) endpointPDFSuspend(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointPDFSuspend")
+	defer span.End()
+
+	request := &apiv1.PDFSuspendRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.OrganizationID = c.GetString("organization_id")
+	request.Principal = c.GetString("principal")
+	reply, err := s.apiv1.PDFSuspend(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+// endpointPDFReinstate lifts the suspension of a signed PDF EduSeal
+func (s *Service) endpointPDFReinstate(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointPDFReinstate")
+	defer span.End()
+
+	request := &apiv1.PDFReinstateRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.OrganizationID = c.GetString("organization_id")
+	request.Principal = c.GetString("principal")
+	reply, err := s.apiv1.PDFReinstate(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+// endpointPDFErase erases a transaction everywhere and keeps a tombstone
+func (s *Service) endpointPDFErase(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointPDFErase")
+	defer span.End()
+
+	request := &apiv1.PDFEraseRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.OrganizationID = c.GetString("organization_id")
+	request.Principal = c.GetString("principal")
+	reply, err := s.apiv1.PDFErase(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+// endpointPDFLegalHold places or releases a legal hold
+func (s *Service) endpointPDFLegalHold(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointPDFLegalHold")
+	defer span.End()
+
+	request := &apiv1.PDFLegalHoldRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.OrganizationID = c.GetString("organization_id")
+	request.Principal = c.GetString("principal")
+	reply, err := s.apiv1.PDFLegalHold(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+// endpointTransactions lists the caller's transactions
+func (s *Service) endpointTransactions(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointTransactions")
+	defer span.End()
+
+	request := &apiv1.TransactionsRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.OrganizationID = c.GetString("organization_id")
+	reply, err := s.apiv1.Transactions(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+// endpointUsage returns the caller's rate limit and quota usage
+func (s *Service) endpointUsage(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointUsage")
+	defer span.End()
+
+	request := &apiv1.UsageRequest{
+		OrganizationID: c.GetString("organization_id"),
+	}
+	reply, err := s.apiv1.Usage(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+// endpointStats returns the usage statistics
+func (s *Service) endpointStats(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointStats")
+	defer span.End()
+
+	request := &apiv1.StatsRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.OrganizationID = c.GetString("organization_id")
+	reply, err := s.apiv1.Stats(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+// endpointRevocations returns the revocation feed
+func (s *Service) endpointRevocations(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointRevocations")
+	defer span.End()
+
+	request := &apiv1.RevocationsRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.Revocations(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+// endpointPDFVerify looks up a sealed PDF by its hash
+func (s *Service) endpointPDFVerify(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointPDFVerify")
+	defer span.End()
+
+	request := &apiv1.PDFVerifyRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	reply, err := s.apiv1.PDFVerify(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
+
+// endpointPDFSearch finds transactions by their client supplied references
+func (s *Service) endpointPDFSearch(ctx context.Context, c *gin.Context) (any, error) {
+	ctx, span := s.tp.Start(ctx, "httpserver:endpointPDFSearch")
+	defer span.End()
+
+	request := &apiv1.PDFSearchRequest{}
+	if err := s.bindRequest(ctx, c, request); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	request.OrganizationID = c.GetString("organization_id")
+	reply, err := s.apiv1.PDFSearch(ctx, request)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return reply, nil
+}
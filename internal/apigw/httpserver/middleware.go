@@ -2,8 +2,11 @@ package httpserver
 
 import (
 	"context"
+	"eduseal/internal/apigw/apiv1"
 	"eduseal/pkg/helpers"
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,6 +14,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/lithammer/shortuuid/v4"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
 )
 
 func (s *Service) middlewareRequestID(ctx context.Context) gin.HandlerFunc {
@@ -49,6 +54,101 @@ func (s *Service) middlewareCrash(ctx context.Context) gin.HandlerFunc {
 	}
 }
 
+// middlewareDuration records the latency of every request by route template, unmatched paths share one route to bound the cardinality
+func (s *Service) middlewareDuration(ctx context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		s.metrics.HTTPDuration.Record(c.Request.Context(), time.Since(start).Seconds(), otelmetric.WithAttributes(
+			attribute.String("route", route),
+			attribute.String("method", c.Request.Method),
+			attribute.Int("status", c.Writer.Status()),
+		))
+	}
+}
+
+// middlewareUpload lets an upload route read the body and reply for longer than the server timeouts, and caps the body at maxBodySize bytes
+func (s *Service) middlewareUpload(ctx context.Context, maxBodySize int64) gin.HandlerFunc {
+	timeout := time.Duration(s.config.APIGW.APIServer.UploadTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultUploadTimeout
+	}
+	return func(c *gin.Context) {
+		deadline := time.Now().Add(timeout)
+		rc := http.NewResponseController(c.Writer)
+		if err := rc.SetReadDeadline(deadline); err != nil {
+			s.logger.Debug("failed to extend read deadline", "error", err)
+		}
+		if err := rc.SetWriteDeadline(deadline); err != nil {
+			s.logger.Debug("failed to extend write deadline", "error", err)
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize)
+		c.Next()
+	}
+}
+
+// middlewareRateLimit allows each client address limit requests per window in bucket, the limit is shared by every apigw instance
+func (s *Service) middlewareRateLimit(ctx context.Context, bucket string, limit int64, window time.Duration) gin.HandlerFunc {
+	log := s.logger.New("http")
+	return func(c *gin.Context) {
+		allowed, retryAfter, err := s.kv.RateLimit.Allow(ctx, bucket, c.ClientIP(), limit, window)
+		if err != nil {
+			// fail open, an unavailable kv should not take the endpoint down with it
+			log.Error(err, "rate limit", "bucket", bucket)
+			c.Next()
+			return
+		}
+		if !allowed {
+			abortRetry(c, &helpers.RetryError{
+				Err:        helpers.NewErrorDetails("rate_limited", fmt.Sprintf("at most %d requests per %s", limit, window)),
+				RetryAfter: retryAfter,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// middlewareTenantRateLimit applies the organization's requests per second limit, it has to run after middlewareJWTAuth
+func (s *Service) middlewareTenantRateLimit(ctx context.Context) gin.HandlerFunc {
+	log := s.logger.New("http")
+	return func(c *gin.Context) {
+		organizationID := c.GetString("organization_id")
+		limit := s.config.APIGW.JWTAuth.LimitsFor(organizationID).RequestsPerSecond
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		allowed, retryAfter, err := s.kv.RateLimit.AllowSliding(ctx, apiv1.RequestRateBucket, organizationID, limit, time.Second)
+		if err != nil {
+			log.Error(err, "tenant rate limit", "organization_id", organizationID)
+			c.Next()
+			return
+		}
+		if !allowed {
+			abortRetry(c, &helpers.RetryError{
+				Err:        helpers.NewErrorDetails("rate_limited", fmt.Sprintf("at most %d requests per second", limit)),
+				RetryAfter: retryAfter,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// abortRetry renders 429 with a Retry-After header and stops the request
+func abortRetry(c *gin.Context, err *helpers.RetryError) {
+	c.Header("Retry-After", strconv.FormatInt(err.RetryAfterSeconds(), 10))
+	renderContent(c, http.StatusTooManyRequests, gin.H{"data": nil, "error": err.Err})
+	c.Abort()
+}
+
 func (s *Service) middlewareClientCertAuth(ctx context.Context) gin.HandlerFunc {
 	_, span := s.tp.Start(ctx, "httpserver:middlewareClientCertAuth")
 	defer span.End()
@@ -210,6 +310,13 @@ func (s *Service) middlewareJWTAuth(ctx context.Context) gin.HandlerFunc {
 			return
 		}
 
+		c.Set("organization_id", organizationIDStr)
+
+		// principal is who acts on behalf of the organization, recorded on actions like revocation
+		if principal, ok := claims["sub"].(string); ok {
+			c.Set("principal", principal)
+		}
+
 		c.Next()
 	}
 }
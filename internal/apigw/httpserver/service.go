@@ -3,11 +3,15 @@ package httpserver
 import (
 	"context"
 	"crypto/tls"
-	"eduseal/internal/apigw/apiv1"
+	"eduseal/internal/apigw/metrics"
 	"eduseal/pkg/helpers"
+	"eduseal/pkg/kvclient"
 	"eduseal/pkg/logger"
 	"eduseal/pkg/model"
 	"eduseal/pkg/trace"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -19,24 +23,42 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+const (
+	// defaultVerifyRateLimit is used when apigw.verify.rate_limit is not configured
+	defaultVerifyRateLimit = 60
+	// defaultPrometheusPath is used when apigw.prometheus.path is not configured
+	defaultPrometheusPath = "/metrics/prometheus"
+	// defaultUploadTimeout is used when apigw.api_server.upload_timeout is not configured
+	defaultUploadTimeout = 2 * time.Minute
+	// defaultBatchMaxBodySize is used when apigw.batch.max_body_size is not configured
+	defaultBatchMaxBodySize = 256 << 20
+)
+
 // Service is the service object for httpserver
 type Service struct {
 	config    *model.Cfg
 	logger    *logger.Log
 	server    *http.Server
 	apiv1     Apiv1
+	kv        *kvclient.Client
 	gin       *gin.Engine
 	tlsConfig *tls.Config
 	tp        *trace.Tracer
+	metrics   *metrics.Metrics
+
+	// adminServer serves the operator api, nil unless apigw.admin is enabled
+	adminServer *http.Server
 }
 
 // New creates a new httpserver service
-func New(ctx context.Context, config *model.Cfg, api *apiv1.Client, tp *trace.Tracer, log *logger.Log) (*Service, error) {
+func New(ctx context.Context, config *model.Cfg, api Apiv1, kv *kvclient.Client, metrics *metrics.Metrics, tp *trace.Tracer, log *logger.Log) (*Service, error) {
 	s := &Service{
-		config: config,
-		logger: log,
-		apiv1:  api,
-		tp:     tp,
+		config:  config,
+		logger:  log,
+		apiv1:   api,
+		kv:      kv,
+		tp:      tp,
+		metrics: metrics,
 		server: &http.Server{
 			ReadHeaderTimeout: 2 * time.Second,
 		},
@@ -58,6 +80,10 @@ func New(ctx context.Context, config *model.Cfg, api *apiv1.Client, tp *trace.Tr
 	//}
 
 	s.gin = gin.New()
+	// the client address keys the public rate limits, so only a configured proxy may set it through X-Forwarded-For
+	if err := s.gin.SetTrustedProxies(config.APIGW.APIServer.TrustedProxies); err != nil {
+		return nil, err
+	}
 	s.server.Handler = s.gin
 	s.server.Addr = config.APIGW.APIServer.Addr
 	s.server.ReadTimeout = 5 * time.Second
@@ -68,6 +94,7 @@ func New(ctx context.Context, config *model.Cfg, api *apiv1.Client, tp *trace.Tr
 	s.gin.Use(s.middlewareRequestID(ctx))
 	s.gin.Use(s.middlewareLogger(ctx))
 	s.gin.Use(s.middlewareCrash(ctx))
+	s.gin.Use(s.middlewareDuration(ctx))
 	problem404, err := helpers.Problem404()
 	if err != nil {
 		return nil, err
@@ -76,6 +103,8 @@ func New(ctx context.Context, config *model.Cfg, api *apiv1.Client, tp *trace.Tr
 
 	rgRoot := s.gin.Group("/")
 	s.regEndpoint(ctx, rgRoot, http.MethodGet, "health", s.endpointHealth)
+	s.regEndpoint(ctx, rgRoot, http.MethodGet, "livez", s.endpointLivez)
+	s.gin.GET("/readyz", s.endpointReadyz(ctx))
 	s.regEndpoint(ctx, rgRoot, http.MethodGet, "metrics", s.endpointMetrics)
 
 	rgDocs := rgRoot.Group("/swagger")
@@ -85,12 +114,73 @@ func New(ctx context.Context, config *model.Cfg, api *apiv1.Client, tp *trace.Tr
 
 	rgPDF := rgAPIv1.Group("/pdf")
 	if s.config.APIGW.JWTAuth.Enabled {
-		rgPDF.Use(s.middlewareJWTAuth(ctx))
+		rgPDF.Use(s.middlewareJWTAuth(ctx), s.middlewareTenantRateLimit(ctx))
+	}
+	batchMaxBodySize := s.config.APIGW.Batch.MaxBodySize
+	if batchMaxBodySize <= 0 {
+		batchMaxBodySize = defaultBatchMaxBodySize
 	}
-	s.regEndpoint(ctx, rgPDF, http.MethodPost, "/sign", s.endpointSignPDF)
+	rgUpload := rgPDF.Group("", s.middlewareUpload(ctx, maxSignBodySize))
+	rgBatchUpload := rgPDF.Group("", s.middlewareUpload(ctx, batchMaxBodySize))
+	s.regEndpoint(ctx, rgUpload, http.MethodPost, "/sign", s.endpointSignPDF)
+	s.regEndpoint(ctx, rgBatchUpload, http.MethodPost, "/sign/batch", s.endpointSignPDFBatch)
+	s.regEndpoint(ctx, rgPDF, http.MethodGet, "/search", s.endpointPDFSearch)
 	s.regEndpoint(ctx, rgPDF, http.MethodGet, "/:transaction_id", s.endpointGetSignedPDF)
-	s.regEndpoint(ctx, rgPDF, http.MethodPost, "/validate", s.endpointValidatePDF)
+	s.regEndpoint(ctx, rgPDF, http.MethodGet, "/:transaction_id/status", s.endpointPDFStatus)
+	s.regEndpoint(ctx, rgPDF, http.MethodGet, "/:transaction_id/webhooks", s.endpointPDFWebhooks)
+	s.regEndpoint(ctx, rgUpload, http.MethodPost, "/validate", s.endpointValidatePDF)
+	s.regEndpoint(ctx, rgPDF, http.MethodPost, "/:transaction_id/validate", s.endpointValidateTransaction)
 	s.regEndpoint(ctx, rgPDF, http.MethodPut, "/revoke/:transaction_id", s.endpointPDFRevoke)
+	s.regEndpoint(ctx, rgPDF, http.MethodPut, "/suspend/:transaction_id", s.endpointPDFSuspend)
+	s.regEndpoint(ctx, rgPDF, http.MethodPut, "/reinstate/:transaction_id", s.endpointPDFReinstate)
+	s.regEndpoint(ctx, rgPDF, http.MethodPut, "/legal_hold/:transaction_id", s.endpointPDFLegalHold)
+	s.regEndpoint(ctx, rgPDF, http.MethodDelete, "/:transaction_id", s.endpointPDFErase)
+
+	rgBatch := rgAPIv1.Group("/batch")
+	if s.config.APIGW.JWTAuth.Enabled {
+		rgBatch.Use(s.middlewareJWTAuth(ctx), s.middlewareTenantRateLimit(ctx))
+	}
+	s.regEndpoint(ctx, rgBatch, http.MethodGet, "/:batch_id", s.endpointBatchGet)
+	s.regEndpoint(ctx, rgBatch, http.MethodGet, "/:batch_id/zip", s.endpointBatchZIP)
+
+	rgTransactions := rgAPIv1.Group("/transactions")
+	if s.config.APIGW.JWTAuth.Enabled {
+		rgTransactions.Use(s.middlewareJWTAuth(ctx), s.middlewareTenantRateLimit(ctx))
+	}
+	s.regEndpoint(ctx, rgTransactions, http.MethodGet, "", s.endpointTransactions)
+
+	rgUsage := rgAPIv1.Group("/usage")
+	if s.config.APIGW.JWTAuth.Enabled {
+		rgUsage.Use(s.middlewareJWTAuth(ctx), s.middlewareTenantRateLimit(ctx))
+	}
+	s.regEndpoint(ctx, rgUsage, http.MethodGet, "", s.endpointUsage)
+
+	rgStats := rgAPIv1.Group("/stats")
+	if s.config.APIGW.JWTAuth.Enabled {
+		rgStats.Use(s.middlewareJWTAuth(ctx), s.middlewareTenantRateLimit(ctx))
+	}
+	s.regEndpoint(ctx, rgStats, http.MethodGet, "", s.endpointStats)
+
+	rgRevocations := rgAPIv1.Group("/revocations")
+	if s.config.APIGW.JWTAuth.Enabled {
+		rgRevocations.Use(s.middlewareJWTAuth(ctx), s.middlewareTenantRateLimit(ctx))
+	}
+	s.regEndpoint(ctx, rgRevocations, http.MethodGet, "", s.endpointRevocations)
+
+	// verify is public, anyone holding a sealed pdf can check it
+	verifyRateLimit := s.config.APIGW.Verify.RateLimit
+	if verifyRateLimit <= 0 {
+		verifyRateLimit = defaultVerifyRateLimit
+	}
+	rgVerify := rgAPIv1.Group("/verify")
+	rgVerify.Use(s.middlewareRateLimit(ctx, "verify", verifyRateLimit, time.Minute))
+	s.regEndpoint(ctx, rgVerify, http.MethodGet, "/:sha256", s.endpointPDFVerify)
+
+	if s.config.APIGW.Admin.Enabled {
+		if err := s.startAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
 
 	// Run http server
 	go func() {
@@ -122,7 +212,17 @@ func (s *Service) regEndpoint(ctx context.Context, rg *gin.RouterGroup, method,
 	rg.Handle(method, path, func(c *gin.Context) {
 		res, err := handler(ctx, c)
 		if err != nil {
-			renderContent(c, 400, gin.H{"error": helpers.NewErrorFromError(err)})
+			var retryErr *helpers.RetryError
+			if errors.As(err, &retryErr) {
+				abortRetry(c, retryErr)
+				return
+			}
+			renderContent(c, errorStatus(err), gin.H{"error": helpers.NewErrorFromError(err)})
+			return
+		}
+
+		if file, ok := res.(*fileReply); ok {
+			renderFile(c, file)
 			return
 		}
 
@@ -130,14 +230,47 @@ func (s *Service) regEndpoint(ctx context.Context, rg *gin.RouterGroup, method,
 	})
 }
 
+// errorStatus is the http status of a failed request, unknown resources are 404, a too large upload 413 and everything else a bad request
+func errorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	if errors.Is(err, helpers.ErrTransactionNotFound) || errors.Is(err, helpers.ErrBatchNotFound) || errors.Is(err, helpers.ErrNoDocumentFound) || errors.Is(err, helpers.ErrNoPendingMessage) || errors.Is(err, helpers.ErrDLQEntryNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+// fileReply is rendered as a file download instead of json
+type fileReply struct {
+	contentType string
+	fileName    string
+	write       func(w io.Writer) error
+}
+
+func renderFile(c *gin.Context, file *fileReply) {
+	c.Header("Content-Type", file.contentType)
+	if file.fileName != "" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.fileName))
+	}
+	c.Status(200)
+	if err := file.write(c.Writer); err != nil {
+		// the status is already sent, the client notices the truncated body
+		_ = c.Error(err)
+	}
+}
+
 func renderContent(c *gin.Context, code int, data any) {
-	switch c.NegotiateFormat(gin.MIMEJSON, "*/*") {
+	switch c.NegotiateFormat(gin.MIMEJSON, "*/*", mimePDF) {
 	case gin.MIMEJSON:
 		c.JSON(code, data)
 	case "*/*": // curl
 		c.JSON(code, data)
+	case mimePDF: // pdf downloads are rendered by renderFile, errors and other replies are still json
+		c.JSON(code, data)
 	default:
-		c.JSON(406, gin.H{"error": helpers.NewErrorDetails("not_acceptable", "Accept header is invalid. It should be \"application/json\".")})
+		c.JSON(406, gin.H{"error": helpers.NewErrorDetails("not_acceptable", "Accept header is invalid. It should be \"application/json\" or \"application/pdf\".")})
 	}
 }
 
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"context"
+	"eduseal/pkg/metric"
+	"eduseal/pkg/model"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+var (
+	// httpBuckets are the request latency buckets in seconds
+	httpBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+	// sealBuckets are the end to end sealing latency buckets in seconds, from published to the SEAL stream until cached
+	sealBuckets = []float64{0.1, 0.25, 0.5, 1, 2, 5, 10, 20, 30, 60, 120, 300}
+)
+
+// ConsumerStats is the state of one JetStream consumer
+type ConsumerStats struct {
+	Stream      string
+	Consumer    string
+	Pending     uint64
+	AckPending  int
+	Redelivered int
+}
+
+// Metrics are the apigw instruments, they are scraped in the prometheus text format
+type Metrics struct {
+	*metric.Metric
+
+	HTTPDuration otelmetric.Float64Histogram
+	SealDuration otelmetric.Float64Histogram
+	SignRequests otelmetric.Int64Counter
+	Seals        otelmetric.Int64Counter
+	Validations  otelmetric.Int64Counter
+	DeadLetters  otelmetric.Int64Counter
+
+	PreflightRejections otelmetric.Int64Counter
+}
+
+// New creates the apigw instruments
+func New(serviceName string) (*Metrics, error) {
+	m := &Metrics{
+		Metric: metric.NewPrometheus(serviceName),
+	}
+
+	var err error
+	m.HTTPDuration, err = m.Float64Histogram("http_server_request_duration_seconds",
+		otelmetric.WithDescription("http request latency by route, method and status"),
+		otelmetric.WithExplicitBucketBoundaries(httpBuckets...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.SealDuration, err = m.Float64Histogram("eduseal_seal_duration_seconds",
+		otelmetric.WithDescription("time from queueing a document until the sealed document is cached, by sealer backend and outcome"),
+		otelmetric.WithExplicitBucketBoundaries(sealBuckets...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.SignRequests, err = m.Int64Counter("eduseal_sign_requests",
+		otelmetric.WithDescription("documents queued for sealing by organization"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Seals, err = m.Int64Counter("eduseal_seals",
+		otelmetric.WithDescription("sealer replies by organization, sealer backend and outcome"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Validations, err = m.Int64Counter("eduseal_validations",
+		otelmetric.WithDescription("validations by validation backend and outcome"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.DeadLetters, err = m.Int64Counter("eduseal_dead_letters",
+		otelmetric.WithDescription("messages moved to the DLQ stream by source stream and reason"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.PreflightRejections, err = m.Int64Counter("eduseal_preflight_rejections",
+		otelmetric.WithDescription("documents rejected before queueing by organization and failed check"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// ObserveConsumers reports the pending and redelivered messages of the consumers returned by stats on every scrape
+func (m *Metrics) ObserveConsumers(stats func(ctx context.Context) []ConsumerStats) error {
+	pending, err := m.Int64ObservableGauge("jetstream_consumer_pending_messages",
+		otelmetric.WithDescription("messages in the stream not yet delivered to the consumer"),
+	)
+	if err != nil {
+		return err
+	}
+	ackPending, err := m.Int64ObservableGauge("jetstream_consumer_ack_pending_messages",
+		otelmetric.WithDescription("messages delivered to the consumer but not yet acknowledged"),
+	)
+	if err != nil {
+		return err
+	}
+	redelivered, err := m.Int64ObservableGauge("jetstream_consumer_redelivered_messages",
+		otelmetric.WithDescription("messages delivered to the consumer more than once and not yet acknowledged"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.RegisterCallback(func(ctx context.Context, o otelmetric.Observer) error {
+		for _, s := range stats(ctx) {
+			attributes := otelmetric.WithAttributes(attribute.String("stream", s.Stream), attribute.String("consumer", s.Consumer))
+			o.ObserveInt64(pending, int64(s.Pending), attributes)
+			o.ObserveInt64(ackPending, int64(s.AckPending), attributes)
+			o.ObserveInt64(redelivered, int64(s.Redelivered), attributes)
+		}
+		return nil
+	}, pending, ackPending, redelivered)
+
+	return err
+}
+
+// ObserveProbes reports the health of the probes returned by probes on every scrape, 1 is healthy
+func (m *Metrics) ObserveProbes(probes func(ctx context.Context) model.Probes) error {
+	_, err := m.Int64ObservableGauge("eduseal_probe_healthy",
+		otelmetric.WithDescription("health of the apigw dependencies, 1 is healthy"),
+		otelmetric.WithInt64Callback(func(ctx context.Context, o otelmetric.Int64Observer) error {
+			for _, probe := range probes(ctx) {
+				healthy := int64(0)
+				if probe.Healthy {
+					healthy = 1
+				}
+				o.Observe(healthy, otelmetric.WithAttributes(attribute.String("probe", probe.Name)))
+			}
+			return nil
+		}),
+	)
+	return err
+}
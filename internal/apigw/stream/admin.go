@@ -0,0 +1,295 @@
+package stream
+
+import (
+	"context"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// jsAPIPrefix is the subject prefix of the JetStream api, used for the requests the jetstream package has no method for
+const jsAPIPrefix = "$JS.API."
+
+// StreamInfo is the state of a stream and its consumers
+type StreamInfo struct {
+	Name      string          `json:"name"`
+	Subjects  []string        `json:"subjects"`
+	Messages  uint64          `json:"messages"`
+	Bytes     uint64          `json:"bytes"`
+	FirstSeq  uint64          `json:"first_seq"`
+	LastSeq   uint64          `json:"last_seq"`
+	Consumers []*ConsumerInfo `json:"consumers"`
+}
+
+// ConsumerInfo is the state of a consumer, pending messages are not delivered yet and ack pending ones are delivered but not acknowledged
+type ConsumerInfo struct {
+	Name        string `json:"name"`
+	Pending     uint64 `json:"pending"`
+	AckPending  int    `json:"ack_pending"`
+	Redelivered int    `json:"redelivered"`
+	Waiting     int    `json:"waiting"`
+	Paused      bool   `json:"paused"`
+	// PausedUntil is when a paused consumer resumes by itself
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+}
+
+// jsAPIError is the error of a JetStream api reply
+type jsAPIError struct {
+	Code        int    `json:"code"`
+	ErrCode     int    `json:"err_code"`
+	Description string `json:"description"`
+}
+
+func (e *jsAPIError) Error() string {
+	return fmt.Sprintf("jetstream api: %s (%d)", e.Description, e.ErrCode)
+}
+
+// consumerPauseState is the pause part of a JetStream consumer info or pause reply
+type consumerPauseState struct {
+	Error  *jsAPIError `json:"error,omitempty"`
+	Paused bool        `json:"paused"`
+	// PauseUntil is set in a pause reply, the consumer info keeps it in the config
+	PauseUntil *time.Time `json:"pause_until,omitempty"`
+	Config     struct {
+		PauseUntil *time.Time `json:"pause_until,omitempty"`
+	} `json:"config"`
+}
+
+// Streams returns the state of the seal, cache and webhook streams and of every consumer on them
+func (s *Service) Streams(ctx context.Context) ([]*StreamInfo, error) {
+	ctx, span := s.tp.Start(ctx, "stream:Streams")
+	defer span.End()
+
+	streams := []jetstream.Stream{s.Seal.stream, s.Cache.stream, s.Webhook.stream}
+
+	infos := make([]*StreamInfo, 0, len(streams))
+	for _, stream := range streams {
+		info, err := stream.Info(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+
+		streamInfo := &StreamInfo{
+			Name:      info.Config.Name,
+			Subjects:  info.Config.Subjects,
+			Messages:  info.State.Msgs,
+			Bytes:     info.State.Bytes,
+			FirstSeq:  info.State.FirstSeq,
+			LastSeq:   info.State.LastSeq,
+			Consumers: []*ConsumerInfo{},
+		}
+
+		consumers := stream.ListConsumers(ctx)
+		for consumer := range consumers.Info() {
+			consumerInfo := &ConsumerInfo{
+				Name:        consumer.Name,
+				Pending:     consumer.NumPending,
+				AckPending:  consumer.NumAckPending,
+				Redelivered: consumer.NumRedelivered,
+				Waiting:     consumer.NumWaiting,
+			}
+			pause, err := s.consumerPause(ctx, "CONSUMER.INFO", consumer.Stream, consumer.Name, nil)
+			if err != nil {
+				s.log.Error(err, "Failed to get consumer pause state", "stream", consumer.Stream, "consumer", consumer.Name)
+			} else {
+				consumerInfo.Paused = pause.Paused
+				consumerInfo.PausedUntil = pause.PauseUntil
+			}
+			streamInfo.Consumers = append(streamInfo.Consumers, consumerInfo)
+		}
+		if err := consumers.Err(); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+
+		infos = append(infos, streamInfo)
+	}
+
+	return infos, nil
+}
+
+// PauseSealer stops the delivery of SEAL messages to every sealer until resumed or until the given time.
+// Messages keep queueing, and the ones already delivered can still be acknowledged, so sealer nodes drain.
+func (s *Service) PauseSealer(ctx context.Context, until time.Time) (*ConsumerInfo, error) {
+	ctx, span := s.tp.Start(ctx, "stream:PauseSealer")
+	defer span.End()
+
+	info, err := s.pauseSealer(ctx, &until)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	s.log.Info("Paused sealer consumer", "until", until)
+
+	return info, nil
+}
+
+// ResumeSealer resumes the delivery of SEAL messages
+func (s *Service) ResumeSealer(ctx context.Context) (*ConsumerInfo, error) {
+	ctx, span := s.tp.Start(ctx, "stream:ResumeSealer")
+	defer span.End()
+
+	info, err := s.pauseSealer(ctx, nil)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	s.log.Info("Resumed sealer consumer")
+
+	return info, nil
+}
+
+// pauseSealer pauses the sealer consumer until the given time, nil resumes it
+func (s *Service) pauseSealer(ctx context.Context, until *time.Time) (*ConsumerInfo, error) {
+	consumer := s.Seal.consumer.CachedInfo()
+
+	pause, err := s.consumerPause(ctx, "CONSUMER.PAUSE", consumer.Stream, consumer.Name, map[string]*time.Time{"pause_until": until})
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.Seal.consumer.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConsumerInfo{
+		Name:        info.Name,
+		Pending:     info.NumPending,
+		AckPending:  info.NumAckPending,
+		Redelivered: info.NumRedelivered,
+		Waiting:     info.NumWaiting,
+		Paused:      pause.Paused,
+		PausedUntil: pause.PauseUntil,
+	}, nil
+}
+
+// consumerPause sends a consumer request to the JetStream api and decodes the pause state of the reply.
+// The vendored jetstream package predates consumer pausing, which needs nats-server 2.11.
+func (s *Service) consumerPause(ctx context.Context, api, stream, consumer string, request any) (*consumerPauseState, error) {
+	var payload []byte
+	if request != nil {
+		var err error
+		payload, err = json.Marshal(request)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	msg, err := s.natsClient.RequestWithContext(ctx, fmt.Sprintf("%s%s.%s.%s", jsAPIPrefix, api, stream, consumer), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &consumerPauseState{}
+	if err := json.Unmarshal(msg.Data, reply); err != nil {
+		return nil, err
+	}
+	if reply.Error != nil {
+		return nil, reply.Error
+	}
+	if reply.PauseUntil == nil {
+		reply.PauseUntil = reply.Config.PauseUntil
+	}
+	if !reply.Paused {
+		reply.PauseUntil = nil
+	}
+
+	return reply, nil
+}
+
+// Purge removes the pending SEAL and CACHE messages of the transaction, e.g. a document every sealer fails on.
+// It returns how many messages were removed.
+func (s *Service) Purge(ctx context.Context, transactionID string) (int, error) {
+	ctx, span := s.tp.Start(ctx, "stream:Purge")
+	defer span.End()
+
+	deleted, err := s.deletePending(ctx, transactionID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return deleted, err
+	}
+
+	s.log.Info("Purged stream messages", "transaction_id", transactionID, "deleted", deleted)
+
+	return deleted, nil
+}
+
+// Republish publishes the pending SEAL message of the transaction again and removes the original, so a message
+// stuck with a sealer is delivered again right away. The document is only kept in the stream, so a transaction
+// without a pending SEAL message can not be republished.
+func (s *Service) Republish(ctx context.Context, transactionID string) error {
+	ctx, span := s.tp.Start(ctx, "stream:Republish")
+	defer span.End()
+
+	msg, err := findTransactionMsg(ctx, s.Seal.stream, transactionID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// the copy is published before the original is deleted, a copy dropped as duplicate must not lose the document
+	ack, err := s.Seal.js.PublishMsg(ctx, &nats.Msg{
+		Subject: msg.Subject,
+		Header:  msg.Header,
+		Data:    msg.Data,
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	if ack.Duplicate {
+		info, err := s.Seal.stream.Info(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+		return &helpers.RetryError{
+			Err:        helpers.NewErrorDetails("republish_too_soon", "the message is within the duplicate window of the stream"),
+			RetryAfter: time.Until(msg.Time.Add(info.Config.Duplicates)).Round(time.Second),
+		}
+	}
+
+	if err := s.Seal.stream.DeleteMsg(ctx, msg.Sequence); err != nil && !errors.Is(err, jetstream.ErrMsgNotFound) {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	// a message stuck with a sealer is in sealing, its rank is above queued so the move back is only made from sealing
+	requeued, err := s.kv.Transaction.TransitionFrom(ctx, transactionID, model.TransactionStateSealing, model.TransactionStateQueued)
+	if err != nil {
+		s.log.Error(err, "Failed to record queued state", "transaction_id", transactionID)
+	}
+
+	s.log.Info("Republished", "transaction_id", transactionID, "seq", msg.Sequence, "new_seq", ack.Sequence, "requeued", requeued)
+
+	return nil
+}
+
+// findTransactionMsg returns the last message of the transaction in the seal stream
+func findTransactionMsg(ctx context.Context, stream jetstream.Stream, transactionID string) (*jetstream.RawStreamMsg, error) {
+	msg, err := stream.GetLastMsgForSubject(ctx, transactionSubject(sealSubject, transactionID))
+	if err != nil {
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			return nil, helpers.ErrNoPendingMessage
+		}
+		return nil, err
+	}
+	return msg, nil
+}
@@ -2,15 +2,23 @@ package stream
 
 import (
 	"context"
+	"crypto/sha256"
 	"eduseal/pkg/logger"
 	"eduseal/pkg/model"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"time"
 
 	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
 )
 
+// cacheSubject is where a sealer publishes the sealed document, as "CACHE.<transaction_id>"
+const cacheSubject = "CACHE"
+
 type cacheStream struct {
 	service         *Service
 	log             *logger.Log
@@ -72,7 +80,7 @@ func (s *cacheStream) createStream(ctx context.Context) error {
 	var err error
 	s.stream, err = s.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
 		Name:      "cache_stream",
-		Subjects:  []string{"CACHE"},
+		Subjects:  streamSubjects(cacheSubject),
 		Retention: jetstream.WorkQueuePolicy,
 		NoAck:     false,
 	})
@@ -82,10 +90,12 @@ func (s *cacheStream) createStream(ctx context.Context) error {
 	}
 
 	s.consumer, err = s.stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
-		Name:          "cacher",
-		Durable:       "cacher",
-		AckPolicy:     jetstream.AckExplicitPolicy,
-		FilterSubject: "CACHE",
+		Name:           "cacher",
+		Durable:        "cacher",
+		AckPolicy:      jetstream.AckExplicitPolicy,
+		FilterSubjects: streamSubjects(cacheSubject),
+		MaxDeliver:     s.service.maxDeliver,
+		BackOff:        s.service.backoff,
 	})
 	if err != nil {
 		s.log.Error(err, "Failed to create cache_stream consumer")
@@ -110,17 +120,75 @@ func (s *cacheStream) Consume(ctx context.Context) error {
 		document := &model.Document{}
 		if err := json.Unmarshal(m.Data(), document); err != nil {
 			s.log.Error(err, "Failed to unmarshal")
-			m.Nak()
+			s.service.DLQ.reject(ctx, m, err)
+			return
+		}
+		if s.service.kv.Erasure.IsErased(ctx, document.TransactionID) {
+			s.log.Info("Dropping sealed document of erased transaction", "transaction_id", document.TransactionID)
+			m.Ack()
+			return
 		}
 		if err := s.service.kv.Doc.SaveSigned(ctx, &model.Document{
 			TransactionID: document.TransactionID,
 			Data:          document.Data,
 			SealerBackend: document.SealerBackend,
+			Error:         document.Error,
 		}); err != nil {
 			s.log.Error(err, "Failed to cache signed document")
-			m.Nak()
+			s.service.DLQ.retry(ctx, m, err)
+			return
+		}
+
+		if !s.service.cfg.Common.Mongo.Disable {
+			sealed := &model.Document{
+				TransactionID: document.TransactionID,
+				SealerBackend: document.SealerBackend,
+				Error:         document.Error,
+				SealedAt:      time.Now().Unix(),
+				SHA256:        sealedHash(document),
+			}
+			if s.service.cfg.APIGW.Archive.Enabled {
+				sealed.Data = document.Data
+			}
+			if err := s.service.db.EduSealSigningColl.SaveSealed(ctx, sealed); err != nil {
+				s.log.Error(err, "Failed to save sealed document", "transaction_id", document.TransactionID)
+				s.service.DLQ.retry(ctx, m, err)
+				return
+			}
 		}
 		m.Ack()
+
+		meta, err := s.service.kv.Transaction.GetMeta(ctx, document.TransactionID)
+		if err != nil {
+			s.log.Error(err, "Failed to get transaction meta", "transaction_id", document.TransactionID)
+		} else if meta.KeepSigned > 0 {
+			if err := s.service.kv.Doc.KeepSigned(ctx, document.TransactionID, time.Duration(meta.KeepSigned)*time.Second); err != nil {
+				s.log.Error(err, "Failed to extend signed document cache", "transaction_id", document.TransactionID)
+			}
+		}
+
+		state := model.TransactionStateSealed
+		if document.Error != "" || document.Data == "" {
+			state = model.TransactionStateFailed
+		}
+		s.observeSeal(ctx, document, meta, state)
+		if err := s.service.kv.Transaction.Transition(ctx, document.TransactionID, state, document.SealerBackend, document.Error); err != nil {
+			s.log.Error(err, "Failed to record transaction state", "transaction_id", document.TransactionID)
+		}
+
+		if err := s.service.Webhook.Enqueue(ctx, &model.WebhookEvent{
+			TransactionID: document.TransactionID,
+			Event:         state,
+			SealerBackend: document.SealerBackend,
+			Error:         document.Error,
+			TS:            time.Now().Unix(),
+		}); err != nil {
+			s.log.Error(err, "Failed to enqueue webhook", "transaction_id", document.TransactionID)
+		}
+
+		if err := s.service.notifySealed(document.TransactionID); err != nil {
+			s.log.Error(err, "Failed to notify sealed", "transaction_id", document.TransactionID)
+		}
 	})
 	if err != nil {
 		s.log.Error(err, "Failed to consume")
@@ -130,6 +198,44 @@ func (s *cacheStream) Consume(ctx context.Context) error {
 	return nil
 }
 
+// observeSeal records the sealer reply, the latency is only known when the transaction meta carries the time it was queued
+func (s *cacheStream) observeSeal(ctx context.Context, document *model.Document, meta *model.TransactionMeta, state model.TransactionState) {
+	organizationID := ""
+	if meta != nil {
+		organizationID = meta.OrganizationID
+	}
+	s.service.metrics.Seals.Add(ctx, 1, otelmetric.WithAttributes(
+		attribute.String("organization_id", organizationID),
+		attribute.String("sealer_backend", document.SealerBackend),
+		attribute.String("outcome", string(state)),
+	))
+
+	if meta == nil || meta.QueuedAt == 0 {
+		return
+	}
+	elapsed := time.Since(time.UnixMilli(meta.QueuedAt)).Seconds()
+	s.service.metrics.SealDuration.Record(ctx, elapsed, otelmetric.WithAttributes(
+		attribute.String("sealer_backend", document.SealerBackend),
+		attribute.String("outcome", string(state)),
+	))
+}
+
+// sealedHash is the hex encoded sha256 of the sealed pdf, it is empty when sealing failed
+func sealedHash(document *model.Document) string {
+	if document.Error != "" || document.Data == "" {
+		return ""
+	}
+	data, err := base64.StdEncoding.DecodeString(document.Data)
+	if err != nil {
+		data, err = base64.URLEncoding.DecodeString(document.Data)
+		if err != nil {
+			return ""
+		}
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
 func (s *cacheStream) close(ctx context.Context) error {
 	s.consumerContext.Stop()
 	s.log.Debug("Closing")
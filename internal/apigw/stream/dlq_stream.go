@@ -0,0 +1,566 @@
+package stream
+
+import (
+	"context"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/logger"
+	"eduseal/pkg/model"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// dlqSubjectPrefix is put in front of the original subject, "DLQ.SEAL.<transaction_id>" and "DLQ.CACHE.<transaction_id>"
+	dlqSubjectPrefix = "DLQ."
+
+	// maxDeliveriesSubject is the advisory JetStream sends when a message is delivered MaxDeliver times without an ack
+	maxDeliveriesSubject = "$JS.EVENT.ADVISORY.CONSUMER.MAX_DELIVERIES.*.*"
+
+	// dlqSweepInterval is how often the seal and cache streams are swept for messages a missed advisory left behind
+	dlqSweepInterval = time.Minute
+
+	// the headers of a DLQ entry, the Nats-Msg-Id of the entry is the stream and sequence it failed at,
+	// so the advisory and the sweep moving the same message add one entry
+	dlqHeaderMsgID      = "Dlq-Msg-Id"
+	dlqHeaderReason     = "Dlq-Reason"
+	dlqHeaderError      = "Dlq-Error"
+	dlqHeaderStream     = "Dlq-Stream"
+	dlqHeaderConsumer   = "Dlq-Consumer"
+	dlqHeaderStreamSeq  = "Dlq-Stream-Seq"
+	dlqHeaderDeliveries = "Dlq-Deliveries"
+	dlqHeaderFailedAt   = "Dlq-Failed-At"
+)
+
+const (
+	// dlqReasonMaxDeliveries the consumer delivered the message MaxDeliver times without an ack
+	dlqReasonMaxDeliveries = "max_deliveries"
+	// dlqReasonInvalid the message can never be processed, e.g. it is not valid json
+	dlqReasonInvalid = "invalid_message"
+	// dlqReasonFailed processing failed on the last attempt
+	dlqReasonFailed = "failed"
+)
+
+// DLQEntry is a message moved to the DLQ stream with the reason it failed
+type DLQEntry struct {
+	Seq uint64 `json:"seq"`
+	// Subject is the subject the message is replayed to
+	Subject       string `json:"subject"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	Reason        string `json:"reason"`
+	Error         string `json:"error,omitempty"`
+	Stream        string `json:"stream"`
+	Consumer      string `json:"consumer"`
+	StreamSeq     uint64 `json:"stream_seq"`
+	Deliveries    int    `json:"deliveries"`
+	FailedAt      int64  `json:"failed_at"`
+	Size          int    `json:"size"`
+	// Payload is the message body, it is only set when a single entry is inspected
+	Payload string `json:"payload,omitempty"`
+}
+
+// dlqFailure is why and where a message failed
+type dlqFailure struct {
+	reason     string
+	err        string
+	stream     string
+	consumer   string
+	streamSeq  uint64
+	deliveries int
+}
+
+// maxDeliveriesAdvisory is the part of the JetStream max deliveries advisory used to find the message
+type maxDeliveriesAdvisory struct {
+	Stream     string `json:"stream"`
+	Consumer   string `json:"consumer"`
+	StreamSeq  uint64 `json:"stream_seq"`
+	Deliveries int    `json:"deliveries"`
+}
+
+type dlqStream struct {
+	service          *Service
+	log              *logger.Log
+	stream           jetstream.Stream
+	js               jetstream.JetStream
+	maxDeliveriesSub *nats.Subscription
+	sweepTick        *time.Ticker
+}
+
+func newDLQStream(ctx context.Context, service *Service) (*dlqStream, error) {
+	s := &dlqStream{
+		service: service,
+		log:     service.log.New("dlq"),
+	}
+
+	if err := s.createStream(ctx); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Started")
+
+	return s, nil
+}
+
+func (s *dlqStream) createStream(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var err error
+	s.js, err = jetstream.New(s.service.natsClient)
+	if err != nil {
+		s.log.Error(err, "Failed to connect to JetStream")
+		return err
+	}
+
+	// limits retention, entries stay until replayed, discarded or too old, and can be read by sequence
+	s.stream, err = s.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:      "dlq_stream",
+		Subjects:  []string{dlqSubjectPrefix + ">"},
+		Retention: jetstream.LimitsPolicy,
+		MaxAge:    s.service.cfg.APIGW.Redelivery.Retention(),
+	})
+	if err != nil {
+		s.log.Error(err, "Failed to create stream")
+		return err
+	}
+
+	return nil
+}
+
+// subscribeMaxDeliveries moves the messages the seal and cache consumers gave up on to the DLQ stream.
+// The queue group makes sure only one apigw replica moves a message.
+func (s *dlqStream) subscribeMaxDeliveries(ctx context.Context) error {
+	streams := map[string]jetstream.Stream{
+		"seal_stream":  s.service.Seal.stream,
+		"cache_stream": s.service.Cache.stream,
+	}
+
+	var err error
+	s.maxDeliveriesSub, err = s.service.natsClient.QueueSubscribe(maxDeliveriesSubject, "apigw", func(m *nats.Msg) {
+		advisory := &maxDeliveriesAdvisory{}
+		if err := json.Unmarshal(m.Data, advisory); err != nil {
+			s.log.Error(err, "Failed to unmarshal max deliveries advisory")
+			return
+		}
+		stream, ok := streams[advisory.Stream]
+		if !ok {
+			return
+		}
+
+		msg, err := stream.GetMsg(ctx, advisory.StreamSeq)
+		if err != nil {
+			if !errors.Is(err, jetstream.ErrMsgNotFound) {
+				s.log.Error(err, "Failed to get message", "stream", advisory.Stream, "seq", advisory.StreamSeq)
+			}
+			return
+		}
+
+		if err := s.deadLetter(ctx, msg.Subject, msg.Header, msg.Data, &dlqFailure{
+			reason:     dlqReasonMaxDeliveries,
+			stream:     advisory.Stream,
+			consumer:   advisory.Consumer,
+			streamSeq:  advisory.StreamSeq,
+			deliveries: advisory.Deliveries,
+		}); err != nil {
+			s.log.Error(err, "Failed to move message to the DLQ", "stream", advisory.Stream, "seq", advisory.StreamSeq)
+			return
+		}
+
+		if err := stream.DeleteMsg(ctx, advisory.StreamSeq); err != nil && !errors.Is(err, jetstream.ErrMsgNotFound) {
+			s.log.Error(err, "Failed to delete message moved to the DLQ", "stream", advisory.Stream, "seq", advisory.StreamSeq)
+		}
+	})
+	if err != nil {
+		s.log.Error(err, "Failed to subscribe to max deliveries advisories")
+		return err
+	}
+
+	return nil
+}
+
+// startSweep moves the messages the seal and cache consumers gave up on to the DLQ stream every dlqSweepInterval.
+// The max deliveries advisory is core NATS and at most once, a message whose advisory nobody handled would
+// otherwise stay in its stream with the transaction queued.
+func (s *dlqStream) startSweep(ctx context.Context) {
+	s.sweepTick = time.NewTicker(dlqSweepInterval)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.sweepTick.C:
+				s.sweep(ctx)
+			}
+		}
+	}()
+}
+
+// sweep moves the messages below the ack floor of the seal and cache consumers to the DLQ stream.
+// Below the ack floor every message was acknowledged or delivered MaxDeliver times, and the seal and cache streams
+// are work queues that remove acknowledged messages, so the messages left there are the ones the consumer gave up on.
+func (s *dlqStream) sweep(ctx context.Context) {
+	for _, pending := range []struct {
+		stream   jetstream.Stream
+		consumer jetstream.Consumer
+	}{
+		{s.service.Seal.stream, s.service.Seal.consumer},
+		{s.service.Cache.stream, s.service.Cache.consumer},
+	} {
+		for {
+			consumerInfo, err := pending.consumer.Info(ctx)
+			if err != nil {
+				s.log.Error(err, "Failed to get consumer info")
+				break
+			}
+			streamInfo, err := pending.stream.Info(ctx)
+			if err != nil {
+				s.log.Error(err, "Failed to get stream info")
+				break
+			}
+			seq := streamInfo.State.FirstSeq
+			if streamInfo.State.Msgs == 0 || seq > consumerInfo.AckFloor.Stream {
+				break
+			}
+
+			msg, err := pending.stream.GetMsg(ctx, seq)
+			if err != nil && !errors.Is(err, jetstream.ErrMsgNotFound) {
+				s.log.Error(err, "Failed to get message", "stream", consumerInfo.Stream, "seq", seq)
+				break
+			}
+			if msg != nil {
+				if err := s.deadLetter(ctx, msg.Subject, msg.Header, msg.Data, &dlqFailure{
+					reason:     dlqReasonMaxDeliveries,
+					stream:     consumerInfo.Stream,
+					consumer:   consumerInfo.Name,
+					streamSeq:  seq,
+					deliveries: s.service.maxDeliver,
+				}); err != nil {
+					s.log.Error(err, "Failed to move message to the DLQ", "stream", consumerInfo.Stream, "seq", seq)
+					break
+				}
+			}
+			if err := pending.stream.DeleteMsg(ctx, seq); err != nil && !errors.Is(err, jetstream.ErrMsgNotFound) {
+				s.log.Error(err, "Failed to delete message moved to the DLQ", "stream", consumerInfo.Stream, "seq", seq)
+				break
+			}
+		}
+	}
+}
+
+func (s *dlqStream) close(ctx context.Context) error {
+	s.log.Debug("Closing")
+
+	if s.sweepTick != nil {
+		s.sweepTick.Stop()
+	}
+	if s.maxDeliveriesSub == nil {
+		return nil
+	}
+	return s.maxDeliveriesSub.Unsubscribe()
+}
+
+// retry redelivers m after the backoff of its attempt, after the last attempt it moves to the DLQ stream instead
+func (s *dlqStream) retry(ctx context.Context, m jetstream.Msg, cause error) {
+	meta, err := m.Metadata()
+	if err != nil {
+		s.log.Error(err, "Failed to get message metadata")
+		m.Nak()
+		return
+	}
+
+	if int(meta.NumDelivered) < s.service.maxDeliver {
+		m.NakWithDelay(s.service.backoffDelay(int(meta.NumDelivered)))
+		return
+	}
+
+	s.move(ctx, m, meta, dlqReasonFailed, cause)
+}
+
+// reject moves m to the DLQ stream right away, for messages that would fail the same way on every attempt
+func (s *dlqStream) reject(ctx context.Context, m jetstream.Msg, cause error) {
+	meta, err := m.Metadata()
+	if err != nil {
+		s.log.Error(err, "Failed to get message metadata")
+		m.Nak()
+		return
+	}
+
+	s.move(ctx, m, meta, dlqReasonInvalid, cause)
+}
+
+// move copies m to the DLQ stream and acknowledges it, m is redelivered when the copy fails
+func (s *dlqStream) move(ctx context.Context, m jetstream.Msg, meta *jetstream.MsgMetadata, reason string, cause error) {
+	if err := s.deadLetter(ctx, m.Subject(), m.Headers(), m.Data(), &dlqFailure{
+		reason:     reason,
+		err:        cause.Error(),
+		stream:     meta.Stream,
+		consumer:   meta.Consumer,
+		streamSeq:  meta.Sequence.Stream,
+		deliveries: int(meta.NumDelivered),
+	}); err != nil {
+		s.log.Error(err, "Failed to move message to the DLQ", "stream", meta.Stream, "seq", meta.Sequence.Stream)
+		m.NakWithDelay(s.service.backoffDelay(int(meta.NumDelivered)))
+		return
+	}
+
+	m.Ack()
+}
+
+// deadLetter publishes a failed message to the DLQ stream and records its transaction as failed
+func (s *dlqStream) deadLetter(ctx context.Context, subject string, header nats.Header, data []byte, failure *dlqFailure) error {
+	ctx, span := s.service.tp.Start(ctx, "stream:dlq:deadLetter")
+	defer span.End()
+
+	transactionID := header.Get(jetstream.MsgIDHeader)
+
+	dlqHeader := nats.Header{}
+	for key, values := range header {
+		if key != jetstream.MsgIDHeader {
+			dlqHeader[key] = values
+		}
+	}
+	dlqHeader.Set(jetstream.MsgIDHeader, fmt.Sprintf("%s.%d", failure.stream, failure.streamSeq))
+	dlqHeader.Set(dlqHeaderMsgID, transactionID)
+	dlqHeader.Set(dlqHeaderReason, failure.reason)
+	if failure.err != "" {
+		dlqHeader.Set(dlqHeaderError, failure.err)
+	}
+	dlqHeader.Set(dlqHeaderStream, failure.stream)
+	dlqHeader.Set(dlqHeaderConsumer, failure.consumer)
+	dlqHeader.Set(dlqHeaderStreamSeq, strconv.FormatUint(failure.streamSeq, 10))
+	dlqHeader.Set(dlqHeaderDeliveries, strconv.Itoa(failure.deliveries))
+	dlqHeader.Set(dlqHeaderFailedAt, strconv.FormatInt(time.Now().Unix(), 10))
+
+	publishCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ack, err := s.js.PublishMsg(publishCtx, &nats.Msg{
+		Subject: dlqSubjectPrefix + subject,
+		Header:  dlqHeader,
+		Data:    data,
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	if ack.Duplicate {
+		// moved already, by the advisory or the sweep of another replica
+		return nil
+	}
+
+	s.log.Info("Moved message to the DLQ", "transaction_id", transactionID, "stream", failure.stream, "seq", failure.streamSeq, "reason", failure.reason, "error", failure.err)
+	s.service.metrics.DeadLetters.Add(ctx, 1, otelmetric.WithAttributes(
+		attribute.String("stream", failure.stream),
+		attribute.String("reason", failure.reason),
+	))
+
+	if transactionID == "" || s.service.kv.Erasure.IsErased(ctx, transactionID) {
+		return nil
+	}
+	errMsg := fmt.Sprintf("moved to the dead letter queue: %s", failure.reason)
+	if err := s.service.kv.Transaction.Transition(ctx, transactionID, model.TransactionStateFailed, "", errMsg); err != nil {
+		s.log.Error(err, "Failed to record failed state", "transaction_id", transactionID)
+	}
+	if err := s.service.Webhook.Enqueue(ctx, &model.WebhookEvent{
+		TransactionID: transactionID,
+		Event:         model.TransactionStateFailed,
+		Error:         errMsg,
+		TS:            time.Now().Unix(),
+	}); err != nil {
+		s.log.Error(err, "Failed to enqueue webhook", "transaction_id", transactionID)
+	}
+
+	return nil
+}
+
+// List returns up to limit DLQ entries after the sequence after, oldest first, without their payload.
+// An ordered consumer reads the headers from after+1 on, so the sequences of removed entries are not looked up one by one.
+func (s *dlqStream) List(ctx context.Context, after uint64, limit int) ([]*DLQEntry, error) {
+	ctx, span := s.service.tp.Start(ctx, "stream:dlq:List")
+	defer span.End()
+
+	consumer, err := s.stream.OrderedConsumer(ctx, jetstream.OrderedConsumerConfig{
+		DeliverPolicy:     jetstream.DeliverByStartSequencePolicy,
+		OptStartSeq:       after + 1,
+		HeadersOnly:       true,
+		InactiveThreshold: 10 * time.Second,
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	batch, err := consumer.FetchNoWait(limit)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	entries := []*DLQEntry{}
+	for msg := range batch.Messages() {
+		meta, err := msg.Metadata()
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		entry := newDLQEntry(&jetstream.RawStreamMsg{
+			Subject:  msg.Subject(),
+			Sequence: meta.Sequence.Stream,
+			Header:   msg.Headers(),
+		})
+		entry.Size, _ = strconv.Atoi(msg.Headers().Get(nats.MsgSize))
+		entries = append(entries, entry)
+	}
+	if err := batch.Error(); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Get returns the DLQ entry with its payload
+func (s *dlqStream) Get(ctx context.Context, seq uint64) (*DLQEntry, error) {
+	ctx, span := s.service.tp.Start(ctx, "stream:dlq:Get")
+	defer span.End()
+
+	msg, err := s.getMsg(ctx, seq)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	entry := newDLQEntry(msg)
+	entry.Payload = string(msg.Data)
+	return entry, nil
+}
+
+// Replay publishes the DLQ entry to its original subject with a fresh delivery count and removes it from the DLQ.
+// The transaction stays failed until the replayed message is sealed.
+func (s *dlqStream) Replay(ctx context.Context, seq uint64) (*DLQEntry, error) {
+	ctx, span := s.service.tp.Start(ctx, "stream:dlq:Replay")
+	defer span.End()
+
+	msg, err := s.getMsg(ctx, seq)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	entry := newDLQEntry(msg)
+
+	header := nats.Header{}
+	for key, values := range msg.Header {
+		if !strings.HasPrefix(key, "Dlq-") && key != jetstream.MsgIDHeader {
+			header[key] = values
+		}
+	}
+	if entry.TransactionID != "" {
+		header.Set(jetstream.MsgIDHeader, entry.TransactionID)
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// the entry is only removed once the message is back in its stream
+	ack, err := s.js.PublishMsg(publishCtx, &nats.Msg{
+		Subject: entry.Subject,
+		Header:  header,
+		Data:    msg.Data,
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if ack.Duplicate {
+		return nil, &helpers.RetryError{
+			Err:        helpers.NewErrorDetails("replay_too_soon", "the message is within the duplicate window of its stream"),
+			RetryAfter: s.duplicateWindow(entry.Subject),
+		}
+	}
+
+	if err := s.stream.DeleteMsg(ctx, seq); err != nil && !errors.Is(err, jetstream.ErrMsgNotFound) {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	s.log.Info("Replayed DLQ entry", "seq", seq, "transaction_id", entry.TransactionID, "subject", entry.Subject, "new_seq", ack.Sequence)
+
+	return entry, nil
+}
+
+// Discard removes the DLQ entry
+func (s *dlqStream) Discard(ctx context.Context, seq uint64) (*DLQEntry, error) {
+	ctx, span := s.service.tp.Start(ctx, "stream:dlq:Discard")
+	defer span.End()
+
+	msg, err := s.getMsg(ctx, seq)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if err := s.stream.SecureDeleteMsg(ctx, seq); err != nil {
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			return nil, helpers.ErrDLQEntryNotFound
+		}
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	entry := newDLQEntry(msg)
+	s.log.Info("Discarded DLQ entry", "seq", seq, "transaction_id", entry.TransactionID)
+
+	return entry, nil
+}
+
+// duplicateWindow is the duplicate window of the stream holding subject, the JetStream default when unknown
+func (s *dlqStream) duplicateWindow(subject string) time.Duration {
+	for _, stream := range []jetstream.Stream{s.service.Seal.stream, s.service.Cache.stream} {
+		info := stream.CachedInfo()
+		root, _, _ := strings.Cut(subject, ".")
+		if slices.Contains(info.Config.Subjects, root) && info.Config.Duplicates > 0 {
+			return info.Config.Duplicates
+		}
+	}
+	return 2 * time.Minute
+}
+
+func (s *dlqStream) getMsg(ctx context.Context, seq uint64) (*jetstream.RawStreamMsg, error) {
+	msg, err := s.stream.GetMsg(ctx, seq)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			return nil, helpers.ErrDLQEntryNotFound
+		}
+		return nil, err
+	}
+	return msg, nil
+}
+
+func newDLQEntry(msg *jetstream.RawStreamMsg) *DLQEntry {
+	streamSeq, _ := strconv.ParseUint(msg.Header.Get(dlqHeaderStreamSeq), 10, 64)
+	deliveries, _ := strconv.Atoi(msg.Header.Get(dlqHeaderDeliveries))
+	failedAt, _ := strconv.ParseInt(msg.Header.Get(dlqHeaderFailedAt), 10, 64)
+
+	return &DLQEntry{
+		Seq:           msg.Sequence,
+		Subject:       strings.TrimPrefix(msg.Subject, dlqSubjectPrefix),
+		TransactionID: msg.Header.Get(dlqHeaderMsgID),
+		Reason:        msg.Header.Get(dlqHeaderReason),
+		Error:         msg.Header.Get(dlqHeaderError),
+		Stream:        msg.Header.Get(dlqHeaderStream),
+		Consumer:      msg.Header.Get(dlqHeaderConsumer),
+		StreamSeq:     streamSeq,
+		Deliveries:    deliveries,
+		FailedAt:      failedAt,
+		Size:          len(msg.Data),
+	}
+}
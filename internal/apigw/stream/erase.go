@@ -0,0 +1,83 @@
+package stream
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// transactionSubject is the subject of the messages of a transaction, e.g. "SEAL.<transaction_id>",
+// so the messages of a transaction are found by subject instead of scanning the stream
+func transactionSubject(subject, transactionID string) string {
+	return subject + "." + transactionID
+}
+
+// streamSubjects are the subjects of a stream, the plain subject is kept for messages published before the
+// per transaction subjects, those are not found by transaction
+func streamSubjects(subject string) []string {
+	return []string{subject, subject + ".*"}
+}
+
+// Erase removes the pending SEAL and CACHE messages and the DLQ entries of the transaction, they carry the document.
+// It returns how many messages were removed.
+func (s *Service) Erase(ctx context.Context, transactionID string) (int, error) {
+	ctx, span := s.tp.Start(ctx, "stream:Erase")
+	defer span.End()
+
+	deleted, err := s.deletePending(ctx, transactionID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return deleted, err
+	}
+	for _, subject := range []string{sealSubject, cacheSubject} {
+		n, err := deleteTransactionMsgs(ctx, s.DLQ.stream, dlqSubjectPrefix+transactionSubject(subject, transactionID))
+		deleted += n
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return deleted, err
+		}
+	}
+
+	s.log.Info("Erased stream messages", "transaction_id", transactionID, "deleted", deleted)
+
+	return deleted, nil
+}
+
+// deletePending removes the SEAL and CACHE messages of the transaction
+func (s *Service) deletePending(ctx context.Context, transactionID string) (int, error) {
+	deleted := 0
+	for _, pending := range []struct {
+		stream  jetstream.Stream
+		subject string
+	}{
+		{s.Seal.stream, sealSubject},
+		{s.Cache.stream, cacheSubject},
+	} {
+		n, err := deleteTransactionMsgs(ctx, pending.stream, transactionSubject(pending.subject, transactionID))
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// deleteTransactionMsgs overwrites and deletes the messages on subject, last first
+func deleteTransactionMsgs(ctx context.Context, stream jetstream.Stream, subject string) (int, error) {
+	deleted := 0
+	for {
+		msg, err := stream.GetLastMsgForSubject(ctx, subject)
+		if err != nil {
+			if errors.Is(err, jetstream.ErrMsgNotFound) {
+				return deleted, nil
+			}
+			return deleted, err
+		}
+		if err := stream.SecureDeleteMsg(ctx, msg.Sequence); err != nil && !errors.Is(err, jetstream.ErrMsgNotFound) {
+			return deleted, err
+		}
+		deleted++
+	}
+}
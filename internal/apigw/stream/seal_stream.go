@@ -3,6 +3,8 @@ package stream
 import (
 	"context"
 	"eduseal/pkg/logger"
+	"eduseal/pkg/model"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel/codes"
@@ -11,6 +13,14 @@ import (
 	"github.com/nats-io/nats.go/jetstream"
 )
 
+const (
+	// sealSubject is where SEAL messages are published, as "SEAL.<transaction_id>"
+	sealSubject = "SEAL"
+
+	// sealingSubject is a core NATS subject where a sealer announces that it picked up a transaction, "SEALING.<transaction_id>"
+	sealingSubject = "SEALING.*"
+)
+
 type sealStream struct {
 	service         *Service
 	log             *logger.Log
@@ -18,6 +28,7 @@ type sealStream struct {
 	js              jetstream.JetStream
 	consumer        jetstream.Consumer
 	consumerContext jetstream.ConsumeContext
+	sealingSub      *nats.Subscription
 }
 
 func newSealStream(ctx context.Context, service *Service) (*sealStream, error) {
@@ -30,11 +41,42 @@ func newSealStream(ctx context.Context, service *Service) (*sealStream, error) {
 		return nil, err
 	}
 
+	if err := s.subscribeSealing(ctx); err != nil {
+		return nil, err
+	}
+
 	s.log.Info("Started")
 
 	return s, nil
 }
 
+// subscribeSealing records the sealing state when a sealer picks up a transaction, the queue group makes sure only one apigw replica records it
+func (s *sealStream) subscribeSealing(ctx context.Context) error {
+	var err error
+	s.sealingSub, err = s.service.natsClient.QueueSubscribe(sealingSubject, "apigw", func(m *nats.Msg) {
+		transactionID := strings.TrimPrefix(m.Subject, "SEALING.")
+		sealerBackend := m.Header.Get("sealer_backend")
+		if s.service.kv.Erasure.IsErased(ctx, transactionID) {
+			return
+		}
+		if err := s.service.kv.Transaction.Transition(ctx, transactionID, model.TransactionStateSealing, sealerBackend, ""); err != nil {
+			s.log.Error(err, "Failed to record sealing state", "transaction_id", transactionID)
+		}
+	})
+	if err != nil {
+		s.log.Error(err, "Failed to subscribe to sealing notifications")
+		return err
+	}
+
+	return nil
+}
+
+func (s *sealStream) close(ctx context.Context) error {
+	s.log.Debug("Closing")
+
+	return s.sealingSub.Unsubscribe()
+}
+
 // Publish publishes a message to the stream
 func (s *sealStream) Publish(ctx context.Context, payload []byte, transactionID string) error {
 	ctx, span := s.service.tp.Start(ctx, "stream:seal:PDFSign")
@@ -46,7 +88,7 @@ func (s *sealStream) Publish(ctx context.Context, payload []byte, transactionID
 	s.log.Info("Publishing", "transaction_id", transactionID)
 
 	ack, err := s.js.PublishMsg(ctx, &nats.Msg{
-		Subject: "SEAL",
+		Subject: transactionSubject(sealSubject, transactionID),
 		Header: map[string][]string{
 			"Nats-Msg-Id": {transactionID},
 		},
@@ -65,6 +107,10 @@ func (s *sealStream) Publish(ctx context.Context, payload []byte, transactionID
 
 	s.log.Debug("Published", "transaction_id", transactionID, "ack", ack)
 
+	if err := s.service.kv.Transaction.Transition(ctx, transactionID, model.TransactionStateQueued, "", ""); err != nil {
+		s.log.Error(err, "Failed to record queued state", "transaction_id", transactionID)
+	}
+
 	//	select {
 	//	case <-s.service.stream.PublishAsyncComplete():
 	//		s.log.Debug("Published", "transaction_id", transactionID)
@@ -88,7 +134,7 @@ func (s *sealStream) createStream(ctx context.Context) error {
 
 	s.stream, err = s.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
 		Name:      "seal_stream",
-		Subjects:  []string{"SEAL"},
+		Subjects:  streamSubjects(sealSubject),
 		Retention: jetstream.WorkQueuePolicy,
 		NoAck:     false,
 	})
@@ -101,10 +147,12 @@ func (s *sealStream) createStream(ctx context.Context) error {
 	s.log.Debug("Consumers", "consumers", consumers)
 
 	s.consumer, err = s.stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
-		Name:          "sealer",
-		Durable:       "sealer",
-		AckPolicy:     jetstream.AckExplicitPolicy,
-		FilterSubject: "SEAL",
+		Name:           "sealer",
+		Durable:        "sealer",
+		AckPolicy:      jetstream.AckExplicitPolicy,
+		FilterSubjects: streamSubjects(sealSubject),
+		MaxDeliver:     s.service.maxDeliver,
+		BackOff:        s.service.backoff,
 	})
 	if err != nil {
 		s.log.Error(err, "Failed to create seal_stream consumer")
@@ -0,0 +1,49 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// sealedSubject is a core NATS subject, not part of any stream. It reaches every
+// apigw replica, so the replica holding a waiting request is notified even if
+// another replica consumed the CACHE message.
+const sealedSubject = "SEALED.%s"
+
+// SealedWaiter waits for a transaction to be cached by any apigw replica
+type SealedWaiter struct {
+	sub *nats.Subscription
+}
+
+// NewSealedWaiter subscribes to the sealed notification for transactionID.
+// It has to be created before the transaction is published to SEAL, otherwise the notification might be missed.
+func (s *Service) NewSealedWaiter(ctx context.Context, transactionID string) (*SealedWaiter, error) {
+	_, span := s.tp.Start(ctx, "stream:NewSealedWaiter")
+	defer span.End()
+
+	sub, err := s.natsClient.SubscribeSync(fmt.Sprintf(sealedSubject, transactionID))
+	if err != nil {
+		s.log.Error(err, "Failed to subscribe to sealed notification", "transaction_id", transactionID)
+		return nil, err
+	}
+
+	return &SealedWaiter{sub: sub}, nil
+}
+
+// Wait blocks until the transaction has been cached or ctx is done
+func (w *SealedWaiter) Wait(ctx context.Context) error {
+	_, err := w.sub.NextMsgWithContext(ctx)
+	return err
+}
+
+// Close removes the subscription
+func (w *SealedWaiter) Close() error {
+	return w.sub.Unsubscribe()
+}
+
+// notifySealed tells all waiting replicas that transactionID has been cached
+func (s *Service) notifySealed(transactionID string) error {
+	return s.natsClient.Publish(fmt.Sprintf(sealedSubject, transactionID), nil)
+}
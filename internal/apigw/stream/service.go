@@ -2,15 +2,20 @@ package stream
 
 import (
 	"context"
+	"eduseal/internal/apigw/db"
+	"eduseal/internal/apigw/metrics"
 	"eduseal/internal/gen/status/v1_status"
 	"eduseal/pkg/kvclient"
 	"eduseal/pkg/logger"
 	"eduseal/pkg/model"
 	"eduseal/pkg/trace"
+	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -21,24 +26,40 @@ type Service struct {
 	cfg        *model.Cfg
 	natsClient *nats.Conn
 	kv         *kvclient.Client
+	db         *db.Service
 	probeStore *v1_status.StatusProbeStore
 	statusTick *time.Ticker
-	tp         *trace.Tracer
 
-	Seal  *sealStream
-	Cache *cacheStream
+	sealerProbeMu    sync.Mutex
+	sealerProbeStore *v1_status.StatusProbeStore
+	tp               *trace.Tracer
+	metrics          *metrics.Metrics
+
+	// maxDeliver and backoff are the redelivery policy of the seal and cache consumers
+	maxDeliver int
+	backoff    []time.Duration
+
+	Seal    *sealStream
+	Cache   *cacheStream
+	Webhook *webhookStream
+	DLQ     *dlqStream
 }
 
 // New creates a new stream service
-func New(ctx context.Context, kv *kvclient.Client, tp *trace.Tracer, cfg *model.Cfg, log *logger.Log) (*Service, error) {
+func New(ctx context.Context, kv *kvclient.Client, db *db.Service, metrics *metrics.Metrics, tp *trace.Tracer, cfg *model.Cfg, log *logger.Log) (*Service, error) {
 	s := &Service{
 		log:        log,
 		cfg:        cfg,
 		kv:         kv,
+		db:         db,
 		probeStore: &v1_status.StatusProbeStore{},
 		statusTick: time.NewTicker(time.Second * 10),
-		tp:         tp,
+
+		sealerProbeStore: &v1_status.StatusProbeStore{},
+		tp:               tp,
+		metrics:          metrics,
 	}
+	s.maxDeliver, s.backoff = cfg.APIGW.Redelivery.Policy()
 
 	if err := s.connect(ctx); err != nil {
 		return nil, err
@@ -48,6 +69,18 @@ func New(ctx context.Context, kv *kvclient.Client, tp *trace.Tracer, cfg *model.
 
 	var err error
 
+	s.Webhook, err = newWebhookStream(ctx, s)
+	if err != nil {
+		s.log.Error(err, "Failed to create webhook stream")
+		return nil, err
+	}
+
+	s.DLQ, err = newDLQStream(ctx, s)
+	if err != nil {
+		s.log.Error(err, "Failed to create dlq stream")
+		return nil, err
+	}
+
 	s.Cache, err = newCacheStream(ctx, s)
 	if err != nil {
 		s.log.Error(err, "Failed to create cache stream")
@@ -60,6 +93,11 @@ func New(ctx context.Context, kv *kvclient.Client, tp *trace.Tracer, cfg *model.
 		return nil, err
 	}
 
+	if err := s.DLQ.subscribeMaxDeliveries(ctx); err != nil {
+		return nil, err
+	}
+	s.DLQ.startSweep(ctx)
+
 	go func() {
 		for {
 			select {
@@ -127,8 +165,104 @@ func (s *Service) Status(ctx context.Context) *v1_status.StatusProbe {
 	return s.probeStore.PreviousResult
 }
 
+// SealerProbe is healthy while a sealer takes SEAL messages, a sealer either waits in a pull request or holds unacknowledged messages.
+// A paused sealer consumer is reported as unhealthy. The result is reused for 10 seconds.
+func (s *Service) SealerProbe(ctx context.Context) *v1_status.StatusProbe {
+	ctx, span := s.tp.Start(ctx, "stream:SealerProbe")
+	defer span.End()
+
+	s.sealerProbeMu.Lock()
+	defer s.sealerProbeMu.Unlock()
+
+	if time.Now().Before(s.sealerProbeStore.NextCheck.AsTime()) {
+		return s.sealerProbeStore.PreviousResult
+	}
+
+	probe := s.sealerProbe(ctx)
+
+	s.sealerProbeStore.PreviousResult = probe
+	s.sealerProbeStore.NextCheck = timestamppb.New(time.Now().Add(10 * time.Second))
+
+	return probe
+}
+
+// sealerProbe asks the sealer consumer for its pull requests and pending messages, idle sealers degrade the service
+func (s *Service) sealerProbe(ctx context.Context) *v1_status.StatusProbe {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
+	defer cancel()
+
+	probe := &v1_status.StatusProbe{
+		Name:          "stream/sealers",
+		Severity:      model.ProbeSeverityDegraded,
+		LastCheckedTS: timestamppb.Now(),
+	}
+
+	info, err := s.Seal.consumer.Info(ctx)
+	if err != nil {
+		probe.Message = err.Error()
+		return probe
+	}
+	if pause, err := s.consumerPause(ctx, "CONSUMER.INFO", info.Stream, info.Name, nil); err == nil && pause.Paused {
+		probe.Message = "sealer consumer is paused"
+		return probe
+	}
+
+	probe.Healthy = info.NumWaiting > 0 || info.NumAckPending > 0
+	probe.Message = fmt.Sprintf("%d pull requests waiting, %d messages being sealed, %d pending", info.NumWaiting, info.NumAckPending, info.NumPending)
+
+	return probe
+}
+
+// ConsumerStats returns the pending and redelivered messages of the apigw consumers, consumers that fail to report are left out
+func (s *Service) ConsumerStats(ctx context.Context) []metrics.ConsumerStats {
+	consumers := []jetstream.Consumer{s.Seal.consumer, s.Cache.consumer, s.Webhook.consumer}
+
+	stats := make([]metrics.ConsumerStats, 0, len(consumers))
+	for _, consumer := range consumers {
+		info, err := consumer.Info(ctx)
+		if err != nil {
+			s.log.Error(err, "Failed to get consumer info")
+			continue
+		}
+		stats = append(stats, metrics.ConsumerStats{
+			Stream:      info.Stream,
+			Consumer:    info.Name,
+			Pending:     info.NumPending,
+			AckPending:  info.NumAckPending,
+			Redelivered: info.NumRedelivered,
+		})
+	}
+
+	return stats
+}
+
+// backoffDelay is the delay before redelivering a message delivered n times, the last backoff step repeats
+// MaxPayload is the largest message the nats server accepts, zero until the first connection
+func (s *Service) MaxPayload() int64 {
+	return s.natsClient.MaxPayload()
+}
+
+func (s *Service) backoffDelay(n int) time.Duration {
+	if len(s.backoff) == 0 {
+		return 0
+	}
+	return s.backoff[min(max(n-1, 0), len(s.backoff)-1)]
+}
+
 // Close closes the stream service
 func (s *Service) Close(ctx context.Context) error {
+	if err := s.Seal.close(ctx); err != nil {
+		s.log.Error(err, "Failed to close seal stream")
+	}
+	if err := s.Cache.close(ctx); err != nil {
+		s.log.Error(err, "Failed to close cache stream")
+	}
+	if err := s.Webhook.close(ctx); err != nil {
+		s.log.Error(err, "Failed to close webhook stream")
+	}
+	if err := s.DLQ.close(ctx); err != nil {
+		s.log.Error(err, "Failed to close dlq stream")
+	}
 	s.natsClient.Close()
 	s.log.Info("Closed")
 	ctx.Done()
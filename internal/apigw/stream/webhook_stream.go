@@ -0,0 +1,287 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/logger"
+	"eduseal/pkg/model"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// webhookBaseDelay is the delay before the second delivery attempt, it doubles for each attempt
+	webhookBaseDelay = 5 * time.Second
+	// webhookMaxDelay caps the delay between delivery attempts
+	webhookMaxDelay = 30 * time.Minute
+)
+
+type webhookStream struct {
+	service         *Service
+	log             *logger.Log
+	stream          jetstream.Stream
+	js              jetstream.JetStream
+	consumer        jetstream.Consumer
+	consumerContext jetstream.ConsumeContext
+	httpClient      *http.Client
+	maxAttempts     int
+}
+
+func newWebhookStream(ctx context.Context, service *Service) (*webhookStream, error) {
+	s := &webhookStream{
+		service:     service,
+		log:         service.log.New("webhook"),
+		maxAttempts: service.cfg.APIGW.Webhook.MaxAttempts,
+		httpClient: &http.Client{
+			Timeout: time.Duration(service.cfg.APIGW.Webhook.Timeout) * time.Second,
+			// no proxy, so the dialer sees the address of the receiver and can refuse internal ones, also after a redirect
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout: 10 * time.Second,
+					Control: helpers.DialPublicOnly,
+				}).DialContext,
+				ForceAttemptHTTP2:     true,
+				TLSHandshakeTimeout:   10 * time.Second,
+				IdleConnTimeout:       90 * time.Second,
+				ExpectContinueTimeout: time.Second,
+			},
+		},
+	}
+
+	if s.maxAttempts == 0 {
+		s.maxAttempts = 8
+	}
+	if s.httpClient.Timeout == 0 {
+		s.httpClient.Timeout = 10 * time.Second
+	}
+
+	if err := s.createStream(ctx); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		if err := s.Consume(ctx); err != nil {
+			s.log.Error(err, "failed to consume")
+		}
+	}()
+
+	s.log.Info("Started")
+
+	return s, nil
+}
+
+func (s *webhookStream) createStream(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var err error
+	s.js, err = jetstream.New(s.service.natsClient)
+	if err != nil {
+		s.log.Error(err, "Failed to connect to JetStream")
+		return err
+	}
+
+	s.stream, err = s.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:      "webhook_stream",
+		Subjects:  []string{"WEBHOOK"},
+		Retention: jetstream.WorkQueuePolicy,
+		NoAck:     false,
+	})
+	if err != nil {
+		s.log.Error(err, "Failed to create stream")
+		return err
+	}
+
+	s.consumer, err = s.stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Name:          "webhooker",
+		Durable:       "webhooker",
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       s.httpClient.Timeout + 30*time.Second,
+		MaxDeliver:    s.maxAttempts,
+		FilterSubject: "WEBHOOK",
+	})
+	if err != nil {
+		s.log.Error(err, "Failed to create webhook_stream consumer")
+		return err
+	}
+
+	return nil
+}
+
+// Enqueue publishes a webhook delivery for the event, it is a no-op if the transaction has no callback_url
+func (s *webhookStream) Enqueue(ctx context.Context, event *model.WebhookEvent) error {
+	ctx, span := s.service.tp.Start(ctx, "stream:webhook:Enqueue")
+	defer span.End()
+
+	meta, err := s.service.kv.Transaction.GetMeta(ctx, event.TransactionID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	if meta.CallbackURL == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(&model.WebhookDelivery{
+		CallbackURL:    meta.CallbackURL,
+		OrganizationID: meta.OrganizationID,
+		Event:          event,
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if _, err := s.js.PublishMsg(ctx, &nats.Msg{
+		Subject: "WEBHOOK",
+		Header: map[string][]string{
+			"Nats-Msg-Id": {fmt.Sprintf("%s:%s", event.TransactionID, event.Event)},
+		},
+		Data: payload,
+	}); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		s.log.Error(err, "Failed to publish", "transaction_id", event.TransactionID)
+		return err
+	}
+
+	return nil
+}
+
+func (s *webhookStream) Consume(ctx context.Context) error {
+	var err error
+	s.consumerContext, err = s.consumer.Consume(func(m jetstream.Msg) {
+		delivery := &model.WebhookDelivery{}
+		if err := json.Unmarshal(m.Data(), delivery); err != nil {
+			s.log.Error(err, "Failed to unmarshal")
+			m.Term()
+			return
+		}
+
+		meta, err := m.Metadata()
+		if err != nil {
+			s.log.Error(err, "Failed to get message metadata")
+			m.Nak()
+			return
+		}
+
+		attempt := &model.WebhookAttempt{
+			Event:   delivery.Event.Event,
+			Attempt: meta.NumDelivered,
+			TS:      time.Now().Unix(),
+		}
+
+		attempt.StatusCode, err = s.deliver(ctx, delivery)
+		switch {
+		case err == nil:
+			attempt.Delivered = true
+			attempt.Final = true
+			m.Ack()
+		case meta.NumDelivered >= uint64(s.maxAttempts):
+			attempt.Error = err.Error()
+			attempt.Final = true
+			m.Term()
+		default:
+			attempt.Error = err.Error()
+			m.NakWithDelay(webhookBackoff(meta.NumDelivered))
+		}
+
+		if err := s.service.kv.Webhook.AddAttempt(ctx, delivery.Event.TransactionID, attempt); err != nil {
+			s.log.Error(err, "Failed to record delivery attempt", "transaction_id", delivery.Event.TransactionID)
+		}
+	})
+	if err != nil {
+		s.log.Error(err, "Failed to consume")
+		return err
+	}
+
+	return nil
+}
+
+// deliver posts the event to the callback url, signed with the organization's secret
+func (s *webhookStream) deliver(ctx context.Context, delivery *model.WebhookDelivery) (int, error) {
+	ctx, span := s.service.tp.Start(ctx, "stream:webhook:deliver")
+	defer span.End()
+
+	secret, ok := s.service.cfg.APIGW.Webhook.Secrets[delivery.OrganizationID]
+	if !ok {
+		err := fmt.Errorf("no webhook secret for organization %q", delivery.OrganizationID)
+		span.SetStatus(codes.Error, err.Error())
+		return 0, err
+	}
+
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return 0, err
+	}
+
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Eduseal-Timestamp", ts)
+	req.Header.Set("X-Eduseal-Signature", "sha256="+WebhookSignature(secret, ts, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err := fmt.Errorf("callback replied with status %d", resp.StatusCode)
+		span.SetStatus(codes.Error, err.Error())
+		return resp.StatusCode, err
+	}
+
+	return resp.StatusCode, nil
+}
+
+// WebhookSignature returns the hex encoded HMAC-SHA256 of "<timestamp>.<body>", receivers recompute it to verify a delivery
+func WebhookSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff returns the exponential delay before the next attempt, numDelivered starts at 1
+func webhookBackoff(numDelivered uint64) time.Duration {
+	delay := webhookBaseDelay
+	for i := uint64(1); i < numDelivered; i++ {
+		delay *= 2
+		if delay >= webhookMaxDelay {
+			return webhookMaxDelay
+		}
+	}
+	return delay
+}
+
+func (s *webhookStream) close(ctx context.Context) error {
+	s.consumerContext.Stop()
+	s.log.Debug("Closing")
+
+	return nil
+}
@@ -27,6 +27,10 @@ type SealRequest struct {
 
 	TransactionId string `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
 	Data          string `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// metadata overrides the sealer's configured signature metadata, empty fields keep the configured value
+	Metadata *SignatureMetadata `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// appearance adds a visible seal stamp, without it the signature is only shown in the signature panel
+	Appearance *SignatureAppearance `protobuf:"bytes,4,opt,name=appearance,proto3" json:"appearance,omitempty"`
 }
 
 func (x *SealRequest) Reset() {
@@ -75,6 +79,245 @@ func (x *SealRequest) GetData() string {
 	return ""
 }
 
+func (x *SealRequest) GetMetadata() *SignatureMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *SealRequest) GetAppearance() *SignatureAppearance {
+	if x != nil {
+		return x.Appearance
+	}
+	return nil
+}
+
+type SignatureMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Reason      string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	Location    string `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	Name        string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	ContactInfo string `protobuf:"bytes,4,opt,name=contact_info,json=contactInfo,proto3" json:"contact_info,omitempty"`
+	FieldName   string `protobuf:"bytes,5,opt,name=field_name,json=fieldName,proto3" json:"field_name,omitempty"`
+}
+
+func (x *SignatureMetadata) Reset() {
+	*x = SignatureMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_sealer_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignatureMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignatureMetadata) ProtoMessage() {}
+
+func (x *SignatureMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_sealer_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignatureMetadata.ProtoReflect.Descriptor instead.
+func (*SignatureMetadata) Descriptor() ([]byte, []int) {
+	return file_v1_sealer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SignatureMetadata) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SignatureMetadata) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *SignatureMetadata) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SignatureMetadata) GetContactInfo() string {
+	if x != nil {
+		return x.ContactInfo
+	}
+	return ""
+}
+
+func (x *SignatureMetadata) GetFieldName() string {
+	if x != nil {
+		return x.FieldName
+	}
+	return ""
+}
+
+type SignatureAppearance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// page is the page number of the stamp, starting at 1
+	Page int32      `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Rect *Rectangle `protobuf:"bytes,2,opt,name=rect,proto3" json:"rect,omitempty"`
+	// image is the name of a background image in the sealer's image registry
+	Image string `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	// text is the stamp text, {signer}, {ts}, {reason}, {location} and {transaction_id} are replaced
+	Text string `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+}
+
+func (x *SignatureAppearance) Reset() {
+	*x = SignatureAppearance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_sealer_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignatureAppearance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignatureAppearance) ProtoMessage() {}
+
+func (x *SignatureAppearance) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_sealer_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignatureAppearance.ProtoReflect.Descriptor instead.
+func (*SignatureAppearance) Descriptor() ([]byte, []int) {
+	return file_v1_sealer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SignatureAppearance) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *SignatureAppearance) GetRect() *Rectangle {
+	if x != nil {
+		return x.Rect
+	}
+	return nil
+}
+
+func (x *SignatureAppearance) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *SignatureAppearance) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// Rectangle is in pdf points, 1/72 inch, from the lower left corner of the page
+type Rectangle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Llx float64 `protobuf:"fixed64,1,opt,name=llx,proto3" json:"llx,omitempty"`
+	Lly float64 `protobuf:"fixed64,2,opt,name=lly,proto3" json:"lly,omitempty"`
+	Urx float64 `protobuf:"fixed64,3,opt,name=urx,proto3" json:"urx,omitempty"`
+	Ury float64 `protobuf:"fixed64,4,opt,name=ury,proto3" json:"ury,omitempty"`
+}
+
+func (x *Rectangle) Reset() {
+	*x = Rectangle{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_sealer_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Rectangle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rectangle) ProtoMessage() {}
+
+func (x *Rectangle) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_sealer_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rectangle.ProtoReflect.Descriptor instead.
+func (*Rectangle) Descriptor() ([]byte, []int) {
+	return file_v1_sealer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Rectangle) GetLlx() float64 {
+	if x != nil {
+		return x.Llx
+	}
+	return 0
+}
+
+func (x *Rectangle) GetLly() float64 {
+	if x != nil {
+		return x.Lly
+	}
+	return 0
+}
+
+func (x *Rectangle) GetUrx() float64 {
+	if x != nil {
+		return x.Urx
+	}
+	return 0
+}
+
+func (x *Rectangle) GetUry() float64 {
+	if x != nil {
+		return x.Ury
+	}
+	return 0
+}
+
 type SealReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -89,7 +332,7 @@ type SealReply struct {
 func (x *SealReply) Reset() {
 	*x = SealReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1_sealer_proto_msgTypes[1]
+		mi := &file_v1_sealer_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -102,7 +345,7 @@ func (x *SealReply) String() string {
 func (*SealReply) ProtoMessage() {}
 
 func (x *SealReply) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_sealer_proto_msgTypes[1]
+	mi := &file_v1_sealer_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -115,7 +358,7 @@ func (x *SealReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SealReply.ProtoReflect.Descriptor instead.
 func (*SealReply) Descriptor() ([]byte, []int) {
-	return file_v1_sealer_proto_rawDescGZIP(), []int{1}
+	return file_v1_sealer_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *SealReply) GetSealerBackend() string {
@@ -150,27 +393,58 @@ var File_v1_sealer_proto protoreflect.FileDescriptor
 
 var file_v1_sealer_proto_rawDesc = []byte{
 	0x0a, 0x0f, 0x76, 0x31, 0x2d, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x12, 0x09, 0x76, 0x31, 0x2e, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x22, 0x48, 0x0a, 0x0b,
-	0x53, 0x65, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x74,
-	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x83, 0x01, 0x0a, 0x09, 0x53, 0x65, 0x61, 0x6c, 0x52,
-	0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x5f, 0x62,
-	0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x65,
-	0x61, 0x6c, 0x65, 0x72, 0x42, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x74,
-	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x32, 0x40, 0x0a, 0x06,
-	0x53, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x12, 0x36, 0x0a, 0x04, 0x53, 0x65, 0x61, 0x6c, 0x12, 0x16,
-	0x2e, 0x76, 0x31, 0x2e, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x61, 0x6c, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x76, 0x31, 0x2e, 0x73, 0x65, 0x61, 0x6c,
-	0x65, 0x72, 0x2e, 0x53, 0x65, 0x61, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x27,
-	0x5a, 0x25, 0x65, 0x64, 0x75, 0x73, 0x65, 0x61, 0x6c, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e,
-	0x61, 0x6c, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x2f, 0x76, 0x31,
-	0x5f, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x12, 0x09, 0x76, 0x31, 0x2e, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x22, 0xc2, 0x01, 0x0a,
+	0x0b, 0x53, 0x65, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x0e,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x38, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x31, 0x2e, 0x73,
+	0x65, 0x61, 0x6c, 0x65, 0x72, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x3e, 0x0a, 0x0a, 0x61, 0x70, 0x70, 0x65, 0x61, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x76, 0x31, 0x2e, 0x73, 0x65, 0x61, 0x6c, 0x65,
+	0x72, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x41, 0x70, 0x70, 0x65, 0x61,
+	0x72, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x0a, 0x61, 0x70, 0x70, 0x65, 0x61, 0x72, 0x61, 0x6e, 0x63,
+	0x65, 0x22, 0x9d, 0x01, 0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12,
+	0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x4e, 0x61, 0x6d,
+	0x65, 0x22, 0x7d, 0x0a, 0x13, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x41, 0x70,
+	0x70, 0x65, 0x61, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x04,
+	0x72, 0x65, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x76, 0x31, 0x2e,
+	0x73, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x63, 0x74, 0x61, 0x6e, 0x67, 0x6c, 0x65,
+	0x52, 0x04, 0x72, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x65, 0x78, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74,
+	0x22, 0x53, 0x0a, 0x09, 0x52, 0x65, 0x63, 0x74, 0x61, 0x6e, 0x67, 0x6c, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x6c, 0x6c, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6c, 0x6c, 0x78, 0x12,
+	0x10, 0x0a, 0x03, 0x6c, 0x6c, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6c, 0x6c,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03,
+	0x75, 0x72, 0x78, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x03, 0x75, 0x72, 0x79, 0x22, 0x83, 0x01, 0x0a, 0x09, 0x53, 0x65, 0x61, 0x6c, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x5f, 0x62, 0x61,
+	0x63, 0x6b, 0x65, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x65, 0x61,
+	0x6c, 0x65, 0x72, 0x42, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49,
+	0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x32, 0x40, 0x0a, 0x06, 0x53,
+	0x65, 0x61, 0x6c, 0x65, 0x72, 0x12, 0x36, 0x0a, 0x04, 0x53, 0x65, 0x61, 0x6c, 0x12, 0x16, 0x2e,
+	0x76, 0x31, 0x2e, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x61, 0x6c, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x76, 0x31, 0x2e, 0x73, 0x65, 0x61, 0x6c, 0x65,
+	0x72, 0x2e, 0x53, 0x65, 0x61, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x27, 0x5a,
+	0x25, 0x65, 0x64, 0x75, 0x73, 0x65, 0x61, 0x6c, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x5f,
+	0x73, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -185,19 +459,25 @@ func file_v1_sealer_proto_rawDescGZIP() []byte {
 	return file_v1_sealer_proto_rawDescData
 }
 
-var file_v1_sealer_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_v1_sealer_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_v1_sealer_proto_goTypes = []any{
-	(*SealRequest)(nil), // 0: v1.sealer.SealRequest
-	(*SealReply)(nil),   // 1: v1.sealer.SealReply
+	(*SealRequest)(nil),         // 0: v1.sealer.SealRequest
+	(*SignatureMetadata)(nil),   // 1: v1.sealer.SignatureMetadata
+	(*SignatureAppearance)(nil), // 2: v1.sealer.SignatureAppearance
+	(*Rectangle)(nil),           // 3: v1.sealer.Rectangle
+	(*SealReply)(nil),           // 4: v1.sealer.SealReply
 }
 var file_v1_sealer_proto_depIdxs = []int32{
-	0, // 0: v1.sealer.Sealer.Seal:input_type -> v1.sealer.SealRequest
-	1, // 1: v1.sealer.Sealer.Seal:output_type -> v1.sealer.SealReply
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: v1.sealer.SealRequest.metadata:type_name -> v1.sealer.SignatureMetadata
+	2, // 1: v1.sealer.SealRequest.appearance:type_name -> v1.sealer.SignatureAppearance
+	3, // 2: v1.sealer.SignatureAppearance.rect:type_name -> v1.sealer.Rectangle
+	0, // 3: v1.sealer.Sealer.Seal:input_type -> v1.sealer.SealRequest
+	4, // 4: v1.sealer.Sealer.Seal:output_type -> v1.sealer.SealReply
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_v1_sealer_proto_init() }
@@ -219,6 +499,42 @@ func file_v1_sealer_proto_init() {
 			}
 		}
 		file_v1_sealer_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*SignatureMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_sealer_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*SignatureAppearance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_sealer_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*Rectangle); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_sealer_proto_msgTypes[4].Exporter = func(v any, i int) any {
 			switch v := v.(*SealReply); i {
 			case 0:
 				return &v.state
@@ -237,7 +553,7 @@ func file_v1_sealer_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_v1_sealer_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,267 @@
+// Package client is the Go client of the apigw api
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultMaxRetries is used when Config.MaxRetries is zero
+	defaultMaxRetries = 3
+	// defaultRetryWait is used when Config.RetryWait is zero, it doubles for every retry
+	defaultRetryWait = 500 * time.Millisecond
+	// maxRetryWait caps the wait between retries, a reply asking to wait longer with Retry-After is returned instead
+	maxRetryWait = 30 * time.Second
+	// defaultPollInterval is used when Config.PollInterval is zero
+	defaultPollInterval = time.Second
+
+	// errQuotaExceeded is the error title of a 429 reply when the organization's sealing quota is used up
+	errQuotaExceeded = "quota_exceeded"
+
+	mimeJSON = "application/json"
+	mimePDF  = "application/pdf"
+)
+
+// TokenSource returns the jwt sent as bearer token, it is called for every request so it can refresh the token
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a TokenSource that always returns the same token
+type StaticToken string
+
+// Token returns the token
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// Config holds the client configuration
+type Config struct {
+	// URL is the apigw base url, e.g. https://apigw.example.org
+	URL string
+	// TokenSource is optional, without it no Authorization header is sent
+	TokenSource TokenSource
+	// HTTPClient is optional, http.DefaultClient is used without it
+	HTTPClient *http.Client
+	// MaxRetries is how many times a failed request is retried, zero means 3 and a negative value disables retries
+	MaxRetries int
+	// RetryWait is the wait before the first retry, zero means 500ms
+	RetryWait time.Duration
+	// PollInterval is the wait between status requests in Wait, zero means one second
+	PollInterval time.Duration
+}
+
+// Client is an apigw api client
+type Client struct {
+	rootURL      *url.URL
+	baseURL      *url.URL
+	tokenSource  TokenSource
+	httpClient   *http.Client
+	maxRetries   int
+	retryWait    time.Duration
+	pollInterval time.Duration
+}
+
+// New creates a new client
+func New(cfg *Config) (*Client, error) {
+	baseURL, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	if baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("client: url %q is not absolute", cfg.URL)
+	}
+
+	c := &Client{
+		rootURL:      baseURL,
+		baseURL:      baseURL.JoinPath("api/v1"),
+		tokenSource:  cfg.TokenSource,
+		httpClient:   cfg.HTTPClient,
+		maxRetries:   cfg.MaxRetries,
+		retryWait:    cfg.RetryWait,
+		pollInterval: cfg.PollInterval,
+	}
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	if c.maxRetries == 0 {
+		c.maxRetries = defaultMaxRetries
+	}
+	if c.maxRetries < 0 {
+		c.maxRetries = 0
+	}
+	if c.retryWait <= 0 {
+		c.retryWait = defaultRetryWait
+	}
+	if c.pollInterval <= 0 {
+		c.pollInterval = defaultPollInterval
+	}
+
+	return c, nil
+}
+
+// request is one api call, it is sent again on retries
+type request struct {
+	method string
+	// path is relative to /api/v1, or to the server root when root is set
+	path   string
+	root   bool
+	query  url.Values
+	header http.Header
+	// body returns the request body for every attempt, nil means no body
+	body func() (io.Reader, error)
+	// once is set when the body can only be read once, the request is then never retried
+	once        bool
+	contentType string
+	accept      string
+}
+
+// jsonBody returns a body that marshals v for every attempt
+func jsonBody(v any) (func() (io.Reader, error), error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return func() (io.Reader, error) {
+		return bytes.NewReader(b), nil
+	}, nil
+}
+
+// streamBody returns a body that streams r and reports if r can be sent again, which needs r to be an io.Seeker
+func streamBody(r io.Reader) (func() (io.Reader, error), bool) {
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		return func() (io.Reader, error) { return r, nil }, false
+	}
+
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return func() (io.Reader, error) { return r, nil }, false
+	}
+	return func() (io.Reader, error) {
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}, true
+}
+
+// do sends the request, retrying transport errors, 429 and 502-504 replies, and returns the successful response.
+// An exceeded quota is not retried, it only resets at the end of the day or month.
+// The caller closes the response body.
+func (c *Client) do(ctx context.Context, req *request) (*http.Response, error) {
+	wait := c.retryWait
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, req)
+		if err == nil && resp.StatusCode < 300 {
+			return resp, nil
+		}
+
+		retryAfter := time.Duration(0)
+		if err == nil {
+			apiErr := decodeError(resp)
+			if !retryable(resp.StatusCode) || apiErr.Err.Title == errQuotaExceeded || apiErr.RetryAfter > maxRetryWait {
+				return nil, apiErr
+			}
+			retryAfter = apiErr.RetryAfter
+			err = apiErr
+		} else if ctx.Err() != nil {
+			return nil, err
+		}
+
+		if attempt >= c.maxRetries || req.once {
+			return nil, err
+		}
+
+		if retryAfter < wait {
+			retryAfter = wait
+		}
+		wait = min(2*wait, maxRetryWait)
+
+		timer := time.NewTimer(retryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, req *request) (*http.Response, error) {
+	u := c.baseURL.JoinPath(req.path)
+	if req.root {
+		u = c.rootURL.JoinPath(req.path)
+	}
+	u.RawQuery = req.query.Encode()
+
+	var body io.Reader
+	if req.body != nil {
+		var err error
+		body, err = req.body()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range req.header {
+		httpReq.Header[key] = values
+	}
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+	httpReq.Header.Set("Accept", mimeJSON)
+	if req.accept != "" {
+		httpReq.Header.Set("Accept", req.accept)
+	}
+	if c.tokenSource != nil {
+		token, err := c.tokenSource.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return c.httpClient.Do(httpReq)
+}
+
+// doJSON sends the request and decodes the json reply into reply
+func (c *Client) doJSON(ctx context.Context, req *request, reply any) error {
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(reply)
+}
+
+// retryable reports if a reply with status may succeed when sent again
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter parses a Retry-After header in seconds, the http date form is not used by apigw
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
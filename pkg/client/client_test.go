@@ -0,0 +1,318 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"eduseal/internal/apigw/apiv1"
+	"eduseal/internal/apigw/httpserver"
+	"eduseal/internal/apigw/metrics"
+	"eduseal/internal/gen/sealer/v1_sealer"
+	"eduseal/internal/gen/status/v1_status"
+	"eduseal/internal/gen/validator/v1_validator"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/logger"
+	"eduseal/pkg/model"
+	"eduseal/pkg/trace"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+var testPDF = []byte("%PDF-1.7\n%test\n%%EOF\n")
+
+// fakeAPI records the requests the real httpserver hands to apiv1, the endpoints the tests do not use panic
+type fakeAPI struct {
+	httpserver.Apiv1
+
+	mu          sync.Mutex
+	signs       []*apiv1.PDFSignRequest
+	signErrors  []error
+	states      []model.TransactionState
+	revocations []*apiv1.PDFRevokeRequest
+}
+
+func (f *fakeAPI) PDFSign(ctx context.Context, req *apiv1.PDFSignRequest) (*apiv1.PDFSignReply, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.signs = append(f.signs, req)
+	if len(f.signErrors) > 0 {
+		err := f.signErrors[0]
+		f.signErrors = f.signErrors[1:]
+		return nil, err
+	}
+	return &apiv1.PDFSignReply{Data: &v1_sealer.SealReply{TransactionId: "tx-1"}}, nil
+}
+
+func (f *fakeAPI) PDFStatus(ctx context.Context, req *apiv1.PDFStatusRequest) (*apiv1.PDFStatusReply, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if req.TransactionID != "tx-1" {
+		return nil, helpers.ErrTransactionNotFound
+	}
+	state := f.states[0]
+	if len(f.states) > 1 {
+		f.states = f.states[1:]
+	}
+	return &apiv1.PDFStatusReply{Data: &model.TransactionStatus{TransactionID: req.TransactionID, State: state}}, nil
+}
+
+func (f *fakeAPI) PDFGetSigned(ctx context.Context, req *apiv1.PDFGetSignedRequest) (*apiv1.PDFGetSignedReply, error) {
+	if req.TransactionID == "tx-failed" {
+		return &apiv1.PDFGetSignedReply{Data: &model.Document{TransactionID: req.TransactionID, Error: "sealer unavailable"}}, nil
+	}
+	return &apiv1.PDFGetSignedReply{Data: &model.Document{TransactionID: req.TransactionID, Data: base64.StdEncoding.EncodeToString(testPDF)}}, nil
+}
+
+func (f *fakeAPI) PDFValidate(ctx context.Context, req *apiv1.PDFValidateRequest) (*apiv1.PDFValidateReply, error) {
+	pdf, err := base64.StdEncoding.DecodeString(req.PDF)
+	if err != nil || !bytes.Equal(pdf, testPDF) {
+		return nil, helpers.NewError("unexpected_pdf")
+	}
+	return &apiv1.PDFValidateReply{Data: &v1_validator.ValidateReply{ValidationBackend: "test", IntactSignature: true, ValidSignature: true}}, nil
+}
+
+func (f *fakeAPI) PDFRevoke(ctx context.Context, req *apiv1.PDFRevokeRequest) (*apiv1.PDFRevokeReply, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.revocations = append(f.revocations, req)
+	reply := &apiv1.PDFRevokeReply{}
+	reply.Data.Status = true
+	return reply, nil
+}
+
+func (f *fakeAPI) Health(ctx context.Context) (*v1_status.StatusReply, error) {
+	return model.Probes{{Name: "kv", Healthy: false, Message: "connection refused"}}.Check("apigw"), nil
+}
+
+// newTestServer starts the apigw httpserver on a free port, with jwt auth against a local jwks, and returns its url and a valid token
+func newTestServer(t *testing.T, api httpserver.Apiv1) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test",
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(jwks.Close)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"organization_id":  "org-a",
+		"sub":              "alice",
+		"requested_access": []any{map[string]any{"type": "eduseal"}},
+		"exp":              time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "test"
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	cfg := &model.Cfg{
+		Common: model.Common{Production: true},
+		APIGW: model.APIGW{
+			APIServer: model.APIServer{Addr: addr},
+			JWTAuth: model.JWTAuth{
+				Enabled: true,
+				Access:  map[string]string{"org-a": "eduseal"},
+				JWKURL:  jwks.URL,
+			},
+		},
+	}
+
+	m, err := metrics.New("test")
+	require.NoError(t, err)
+	tp := &trace.Tracer{Tracer: noop.NewTracerProvider().Tracer("")}
+
+	_, err = httpserver.New(context.Background(), cfg, api, nil, m, tp, logger.NewSimple("test"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return "http://" + addr, signed
+}
+
+func newTestClient(t *testing.T, api httpserver.Apiv1) *Client {
+	url, token := newTestServer(t, api)
+	c, err := New(&Config{
+		URL:          url,
+		TokenSource:  StaticToken(token),
+		RetryWait:    10 * time.Millisecond,
+		PollInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	return c
+}
+
+func TestSign(t *testing.T) {
+	api := &fakeAPI{
+		signErrors: []error{&helpers.RetryError{Err: helpers.NewError("rate_limited"), RetryAfter: time.Second}},
+	}
+	c := newTestClient(t, api)
+
+	reply, err := c.Sign(context.Background(), bytes.NewReader(testPDF), &SignOptions{
+		ExternalReference: "student-1",
+		Labels:            map[string]string{"course": "math"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "tx-1", reply.Data.TransactionID)
+
+	require.Len(t, api.signs, 2, "the rate limited request is retried")
+	first, retry := api.signs[0], api.signs[1]
+	assert.NotEmpty(t, first.IdempotencyKey)
+	assert.Equal(t, first.IdempotencyKey, retry.IdempotencyKey, "a retry reuses the idempotency key")
+	assert.Equal(t, base64.StdEncoding.EncodeToString(testPDF), retry.PDF, "the pdf is sent again in full")
+	assert.Equal(t, "org-a", retry.OrganizationID)
+	assert.Equal(t, "student-1", retry.ExternalReference)
+	assert.Equal(t, map[string]string{"course": "math"}, retry.Labels)
+}
+
+func TestSignNoRetryOfUnseekableBody(t *testing.T) {
+	api := &fakeAPI{
+		signErrors: []error{&helpers.RetryError{Err: helpers.NewError("rate_limited"), RetryAfter: time.Second}},
+	}
+	c := newTestClient(t, api)
+
+	_, err := c.Sign(context.Background(), io.MultiReader(bytes.NewReader(testPDF)), nil)
+	apiErr := &Error{}
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	assert.Equal(t, time.Second, apiErr.RetryAfter)
+	assert.Len(t, api.signs, 1)
+}
+
+func TestSignNoRetryOfExceededQuota(t *testing.T) {
+	tts := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "quota exceeded",
+			err:  &helpers.RetryError{Err: helpers.NewError("quota_exceeded"), RetryAfter: time.Second},
+		},
+		{
+			name: "retry after too long",
+			err:  &helpers.RetryError{Err: helpers.NewError("rate_limited"), RetryAfter: time.Hour},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{signErrors: []error{tt.err}}
+			c := newTestClient(t, api)
+
+			_, err := c.Sign(context.Background(), bytes.NewReader(testPDF), nil)
+			apiErr := &Error{}
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+			assert.Len(t, api.signs, 1)
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	api := &fakeAPI{
+		states: []model.TransactionState{model.TransactionStateQueued, model.TransactionStateSealing, model.TransactionStateSealed},
+	}
+	c := newTestClient(t, api)
+
+	reply, err := c.Wait(context.Background(), "tx-1")
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStateSealed, reply.Data.State)
+}
+
+func TestErrors(t *testing.T) {
+	c := newTestClient(t, &fakeAPI{})
+
+	_, err := c.Status(context.Background(), "tx-unknown")
+	assert.ErrorIs(t, err, helpers.ErrTransactionNotFound)
+	apiErr := &Error{}
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+
+	c.tokenSource = nil
+	_, err = c.Status(context.Background(), "tx-1")
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.Equal(t, "unauthorized", apiErr.Err.Title)
+}
+
+func TestFetchAndDownload(t *testing.T) {
+	c := newTestClient(t, &fakeAPI{})
+
+	reply, err := c.Fetch(context.Background(), "tx-1")
+	require.NoError(t, err)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(testPDF), reply.Data.Data)
+
+	var buf bytes.Buffer
+	n, err := c.Download(context.Background(), "tx-1", &buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(testPDF)), n)
+	assert.Equal(t, testPDF, buf.Bytes())
+
+	_, err = c.Download(context.Background(), "tx-failed", &buf)
+	assert.ErrorIs(t, err, ErrNoPDF)
+}
+
+func TestValidateAndRevoke(t *testing.T) {
+	api := &fakeAPI{}
+	c := newTestClient(t, api)
+
+	validation, err := c.Validate(context.Background(), bytes.NewReader(testPDF), "")
+	require.NoError(t, err)
+	assert.True(t, validation.Data.ValidSignature)
+
+	revocation, err := c.Revoke(context.Background(), "tx-1", model.RevocationReasonSuperseded, "replaced by tx-2")
+	require.NoError(t, err)
+	assert.True(t, revocation.Data.Status)
+
+	require.Len(t, api.revocations, 1)
+	assert.Equal(t, "tx-1", api.revocations[0].TransactionID)
+	assert.Equal(t, model.RevocationReasonSuperseded, api.revocations[0].ReasonCode)
+	assert.Equal(t, "replaced by tx-2", api.revocations[0].Reason)
+	assert.Equal(t, "alice", api.revocations[0].Principal)
+}
+
+func TestHealth(t *testing.T) {
+	c := newTestClient(t, &fakeAPI{})
+
+	reply, err := c.Health(context.Background())
+	require.NoError(t, err)
+	require.Len(t, reply.Data.Probes, 1)
+	assert.Equal(t, "kv", reply.Data.Probes[0].Name)
+	assert.False(t, reply.Data.Probes[0].Healthy)
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"eduseal/pkg/helpers"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxErrorBodySize limits how much of an error reply is read
+const maxErrorBodySize = 64 << 10
+
+// ErrNoPDF is returned by Download when the transaction has no sealed pdf, use Status for the reason
+var ErrNoPDF = errors.New("client: the transaction has no sealed pdf")
+
+// Error is an error reply of the api.
+// It matches the helpers errors by title, so errors.Is(err, helpers.ErrTransactionNotFound) works on it.
+type Error struct {
+	StatusCode int
+	// RetryAfter is set from the Retry-After header of 429 replies
+	RetryAfter time.Duration
+	Err        *helpers.Error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("apigw: %d %s", e.StatusCode, e.Err.Error())
+}
+
+// Unwrap returns the error reported by the api
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports if target is a helpers error with the same title
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*helpers.Error)
+	return ok && e.Err != nil && t.Title == e.Err.Title
+}
+
+// decodeError reads an error reply, replies without a json error body get the http status text as title
+func decodeError(resp *http.Response) *Error {
+	defer resp.Body.Close()
+
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+
+	reply := &helpers.ErrorResponse{}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err == nil && json.Unmarshal(body, reply) == nil && reply.Error != nil && reply.Error.Title != "" {
+		apiErr.Err = reply.Error
+		return apiErr
+	}
+
+	apiErr.Err = helpers.NewErrorDetails(http.StatusText(resp.StatusCode), string(body))
+	return apiErr
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// HealthReply is the reply of Health
+type HealthReply struct {
+	Data *Health `json:"data"`
+}
+
+// Health is the apigw health and the state of the services it depends on
+type Health struct {
+	ServiceName    string          `json:"serviceName,omitempty"`
+	BuildVariables *BuildVariables `json:"build_variables,omitempty"`
+	Probes         []*Probe        `json:"probes,omitempty"`
+	Status         string          `json:"status,omitempty"`
+}
+
+// BuildVariables describe the apigw build
+type BuildVariables struct {
+	GitCommit   string `json:"git_commit,omitempty"`
+	GitBranch   string `json:"git_branch,omitempty"`
+	Timestamp   string `json:"timestamp,omitempty"`
+	GoVersion   string `json:"go_version,omitempty"`
+	GoArch      string `json:"go_arch,omitempty"`
+	Version     string `json:"version,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
+}
+
+// Probe is the state of one service apigw depends on
+type Probe struct {
+	Name     string `json:"name,omitempty"`
+	Healthy  bool   `json:"healthy,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// Health returns the apigw health and the state of the services it depends on
+func (c *Client) Health(ctx context.Context) (*HealthReply, error) {
+	reply := &HealthReply{}
+	err := c.doJSON(ctx, &request{
+		method: http.MethodGet,
+		path:   "health",
+		root:   true,
+	}, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
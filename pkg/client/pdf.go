@@ -0,0 +1,294 @@
+package client
+
+import (
+	"context"
+	"eduseal/pkg/model"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SignOptions are the optional parameters of Sign
+type SignOptions struct {
+	// IdempotencyKey identifies the document across retries, a random key is used when it is empty.
+	// Reuse the key when retrying a Sign that failed, so the document is not sealed twice.
+	IdempotencyKey string
+	// Wait makes the server wait up to this long for the sealed document before replying
+	Wait time.Duration
+	// CallbackURL is notified when the document is sealed, failed or revoked
+	CallbackURL string
+	// ExternalReference is a client identifier of the document, e.g. a student uid
+	ExternalReference string
+	// Labels are client key value pairs, transactions can be searched by them
+	Labels map[string]string
+}
+
+// Seal is the transaction of a document queued for sealing
+type Seal struct {
+	TransactionID string `json:"transaction_id,omitempty"`
+	SealerBackend string `json:"sealer_backend,omitempty"`
+	// Data is the sealed pdf, base64 encoded, it is only set when SignOptions.Wait is set and sealing finished in time
+	Data  string `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Validation is the outcome of validating a signed pdf
+type Validation struct {
+	ValidationBackend string `json:"validation_backend,omitempty"`
+	IntactSignature   bool   `json:"intact_signature,omitempty"`
+	ValidSignature    bool   `json:"valid_signature,omitempty"`
+	TransactionID     string `json:"transaction_id,omitempty"`
+	Error             string `json:"error,omitempty"`
+	// Signatures is one report per signature in document order, only set with detail "full"
+	Signatures []*SignatureReport `json:"signatures,omitempty"`
+}
+
+// SignatureReport is the validation of one signature of a pdf
+type SignatureReport struct {
+	FieldName     string `json:"field_name,omitempty"`
+	SignerSubject string `json:"signer_subject,omitempty"`
+	// Chain is the signer certificate first, up to the trust root when a path was found
+	Chain []*Certificate `json:"chain,omitempty"`
+	// SigningTime is the signer reported time, unix seconds, zero if not given
+	SigningTime int64            `json:"signing_time,omitempty"`
+	Timestamp   *TimestampReport `json:"timestamp,omitempty"`
+	// RevocationStatus is good, revoked or not_checked
+	RevocationStatus string `json:"revocation_status,omitempty"`
+	// Coverage is entire_file, entire_revision or partial
+	Coverage string `json:"coverage,omitempty"`
+	// ModifiedAfter is true when the document was changed after it was signed
+	ModifiedAfter bool `json:"modified_after,omitempty"`
+	// ModificationLevel is none, lta_updates, form_filling, annotations or other
+	ModificationLevel string `json:"modification_level,omitempty"`
+	Intact            bool   `json:"intact,omitempty"`
+	Valid             bool   `json:"valid,omitempty"`
+	Trusted           bool   `json:"trusted,omitempty"`
+	// Eduseal is true when the signer is one of the eduSeal sealing certificates
+	Eduseal bool   `json:"eduseal,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Certificate is a certificate of a signature chain
+type Certificate struct {
+	Subject           string `json:"subject,omitempty"`
+	Issuer            string `json:"issuer,omitempty"`
+	SerialNumber      string `json:"serial_number,omitempty"`
+	NotBefore         int64  `json:"not_before,omitempty"`
+	NotAfter          int64  `json:"not_after,omitempty"`
+	Sha256Fingerprint string `json:"sha256_fingerprint,omitempty"`
+}
+
+// TimestampReport is the signature timestamp of a signature
+type TimestampReport struct {
+	Present bool `json:"present,omitempty"`
+	// Time is unix seconds
+	Time       int64  `json:"time,omitempty"`
+	TSASubject string `json:"tsa_subject,omitempty"`
+	Valid      bool   `json:"valid,omitempty"`
+}
+
+// SignReply is the reply of Sign
+type SignReply struct {
+	Data *Seal `json:"data"`
+	// Status is only set when SignOptions.Wait is set
+	Status string `json:"status,omitempty"`
+}
+
+// StatusReply is the reply of Status
+type StatusReply struct {
+	Data *model.TransactionStatus `json:"data"`
+}
+
+// FetchReply is the reply of Fetch
+type FetchReply struct {
+	Data *model.Document `json:"data"`
+}
+
+// ValidateReply is the reply of Validate
+type ValidateReply struct {
+	Data *Validation `json:"data"`
+}
+
+// RevokeReply is the reply of Revoke
+type RevokeReply struct {
+	Data struct {
+		Status bool `json:"status"`
+	} `json:"data"`
+}
+
+// Sign streams the pdf in r to the sealer queue and returns the transaction id.
+// Failed requests are retried with the same Idempotency-Key when r is an io.Seeker, e.g. an *os.File.
+func (c *Client) Sign(ctx context.Context, r io.Reader, opts *SignOptions) (*SignReply, error) {
+	if opts == nil {
+		opts = &SignOptions{}
+	}
+	idempotencyKey := opts.IdempotencyKey
+	if idempotencyKey == "" {
+		idempotencyKey = uuid.NewString()
+	}
+
+	query := url.Values{}
+	if opts.Wait > 0 {
+		query.Set("wait", opts.Wait.String())
+	}
+	if opts.CallbackURL != "" {
+		query.Set("callback_url", opts.CallbackURL)
+	}
+	if opts.ExternalReference != "" {
+		query.Set("external_reference", opts.ExternalReference)
+	}
+	for key, value := range opts.Labels {
+		query.Set("labels["+key+"]", value)
+	}
+
+	body, rewindable := streamBody(r)
+
+	reply := &SignReply{}
+	err := c.doJSON(ctx, &request{
+		method:      http.MethodPost,
+		path:        "pdf/sign",
+		query:       query,
+		header:      http.Header{"Idempotency-Key": []string{idempotencyKey}},
+		body:        body,
+		once:        !rewindable,
+		contentType: mimePDF,
+	}, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// Status returns the lifecycle state of the transaction
+func (c *Client) Status(ctx context.Context, transactionID string) (*StatusReply, error) {
+	reply := &StatusReply{}
+	err := c.doJSON(ctx, &request{
+		method: http.MethodGet,
+		path:   "pdf/" + url.PathEscape(transactionID) + "/status",
+	}, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// Wait polls the status of the transaction until it is sealed, failed, expired or revoked, or ctx is done
+func (c *Client) Wait(ctx context.Context, transactionID string) (*StatusReply, error) {
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		reply, err := c.Status(ctx, transactionID)
+		if err != nil {
+			return nil, err
+		}
+		if done(reply.Data.State) {
+			return reply, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// done reports if a transaction in state will not change unless suspended, reinstated or revoked
+func done(state model.TransactionState) bool {
+	switch state {
+	case model.TransactionStateSealed, model.TransactionStateFailed, model.TransactionStateExpired, model.TransactionStateSuspended, model.TransactionStateRevoked:
+		return true
+	}
+	return false
+}
+
+// Fetch returns the sealed document, base64 encoded, with its sealing details
+func (c *Client) Fetch(ctx context.Context, transactionID string) (*FetchReply, error) {
+	reply := &FetchReply{}
+	err := c.doJSON(ctx, &request{
+		method: http.MethodGet,
+		path:   "pdf/" + url.PathEscape(transactionID),
+	}, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// Download streams the sealed pdf to w and returns the number of bytes written.
+// It returns ErrNoPDF when the transaction is not sealed, or sealing failed.
+func (c *Client) Download(ctx context.Context, transactionID string, w io.Writer) (int64, error) {
+	resp, err := c.do(ctx, &request{
+		method: http.MethodGet,
+		path:   "pdf/" + url.PathEscape(transactionID),
+		accept: mimePDF,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// the document is sent as json when there is no pdf to download
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != mimePDF {
+		return 0, ErrNoPDF
+	}
+
+	return io.Copy(w, resp.Body)
+}
+
+// Validate streams the signed pdf in r to the validator, detail "full" adds a report for every signature
+func (c *Client) Validate(ctx context.Context, r io.Reader, detail string) (*ValidateReply, error) {
+	query := url.Values{}
+	if detail != "" {
+		query.Set("detail", detail)
+	}
+
+	body, rewindable := streamBody(r)
+
+	reply := &ValidateReply{}
+	err := c.doJSON(ctx, &request{
+		method:      http.MethodPost,
+		path:        "pdf/validate",
+		query:       query,
+		body:        body,
+		once:        !rewindable,
+		contentType: mimePDF,
+	}, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// Revoke revokes the sealed document, reason is free text kept with the document
+func (c *Client) Revoke(ctx context.Context, transactionID string, reasonCode model.RevocationReason, reason string) (*RevokeReply, error) {
+	body, err := jsonBody(map[string]any{
+		"reason_code": reasonCode,
+		"reason":      reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &RevokeReply{}
+	err = c.doJSON(ctx, &request{
+		method:      http.MethodPut,
+		path:        "pdf/revoke/" + url.PathEscape(transactionID),
+		body:        body,
+		contentType: mimeJSON,
+	}, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
@@ -2,10 +2,12 @@ package grpcclient
 
 import (
 	"context"
+	"eduseal/internal/gen/status/v1_status"
 	"eduseal/pkg/logger"
 	"eduseal/pkg/model"
 	"eduseal/pkg/trace"
 	"fmt"
+	"sync"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -20,6 +22,10 @@ type Client struct {
 
 	Validator *Validator
 	Sealer    *Sealer
+
+	// healthConns are the connections of the health checks by node
+	healthMu    sync.Mutex
+	healthConns map[string]*grpc.ClientConn
 }
 
 // New creates a new instance of the gRPC client
@@ -31,8 +37,9 @@ func New(ctx context.Context, cfg *model.Cfg, tp *trace.Tracer, log *logger.Log)
 	}
 
 	c.Validator = &Validator{
-		client: c,
-		scheme: "validator",
+		client:     c,
+		scheme:     "validator",
+		probeStore: &v1_status.StatusProbeStore{},
 		DNS: map[string][]string{
 			c.cfg.Common.ValidatorServiceName: cfg.Common.ValidatorNodes,
 		},
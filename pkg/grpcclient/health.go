@@ -0,0 +1,134 @@
+package grpcclient
+
+import (
+	"context"
+	"eduseal/internal/gen/status/v1_status"
+	"eduseal/pkg/model"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// healthTimeout bounds the health check of one node
+	healthTimeout = time.Second
+	// probeInterval is how long a probe result is reused, /health, /readyz and scrapes do not reach the nodes every time
+	probeInterval = 10 * time.Second
+)
+
+// Probe checks every validator node with the grpc.health.v1 protocol, it is healthy while at least one node is serving, no serving node degrades the service
+func (c *Validator) Probe(ctx context.Context) *v1_status.StatusProbe {
+	ctx, span := c.client.tp.Start(ctx, "grpcclient:Validator:Probe")
+	defer span.End()
+
+	c.probeMu.Lock()
+	defer c.probeMu.Unlock()
+
+	if time.Now().Before(c.probeStore.NextCheck.AsTime()) {
+		return c.probeStore.PreviousResult
+	}
+
+	probe := c.client.healthProbe(ctx, "grpc/validators", c.client.cfg.Common.ValidatorServiceName, c.client.cfg.Common.ValidatorNodes)
+	probe.Severity = model.ProbeSeverityDegraded
+
+	c.probeStore.PreviousResult = probe
+	c.probeStore.NextCheck = timestamppb.New(time.Now().Add(probeInterval))
+
+	return probe
+}
+
+// healthProbe checks the nodes concurrently, the message lists the nodes that are not serving
+func (c *Client) healthProbe(ctx context.Context, name, serviceName string, nodes []string) *v1_status.StatusProbe {
+	probe := &v1_status.StatusProbe{
+		Name:          name,
+		LastCheckedTS: timestamppb.Now(),
+	}
+	if len(nodes) == 0 {
+		probe.Message = "no nodes configured"
+		return probe
+	}
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		notServing []string
+	)
+	for _, node := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.checkNode(ctx, serviceName, node); err != nil {
+				mu.Lock()
+				notServing = append(notServing, fmt.Sprintf("%s: %v", node, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	serving := len(nodes) - len(notServing)
+	probe.Healthy = serving > 0
+	probe.Message = fmt.Sprintf("%d of %d nodes serving", serving, len(nodes))
+	if len(notServing) > 0 {
+		probe.Message += ", " + strings.Join(notServing, ", ")
+	}
+
+	return probe
+}
+
+// checkNode asks one node for the health of the whole server
+func (c *Client) checkNode(ctx context.Context, serviceName, node string) error {
+	conn, err := c.healthConn(serviceName, node)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+
+	reply, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if reply.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("status %s", reply.GetStatus())
+	}
+
+	return nil
+}
+
+// healthConn returns the connection to node, the connections are kept for the following checks.
+// The authority is the service name the node certificate is issued for.
+func (c *Client) healthConn(serviceName, node string) (*grpc.ClientConn, error) {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+
+	if conn, ok := c.healthConns[node]; ok {
+		return conn, nil
+	}
+
+	clientTLS, err := credentials.NewClientTLSFromFile(c.cfg.Common.RootCAPath, "")
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.NewClient(node,
+		grpc.WithTransportCredentials(clientTLS),
+		grpc.WithAuthority(serviceName),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.healthConns == nil {
+		c.healthConns = map[string]*grpc.ClientConn{}
+	}
+	c.healthConns[node] = conn
+
+	return conn, nil
+}
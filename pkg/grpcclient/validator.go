@@ -2,7 +2,9 @@ package grpcclient
 
 import (
 	"context"
+	"eduseal/internal/gen/status/v1_status"
 	"eduseal/internal/gen/validator/v1_validator"
+	"sync"
 )
 
 // Validator is the gRPC validator client object
@@ -10,6 +12,9 @@ type Validator struct {
 	client *Client
 	scheme string
 	DNS    map[string][]string
+
+	probeMu    sync.Mutex
+	probeStore *v1_status.StatusProbeStore
 }
 
 // Validate sends a request to the validator service to validate the signature of a PDF
@@ -3,7 +3,9 @@ package helpers
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/moogar0880/problems"
@@ -12,6 +14,14 @@ import (
 var (
 	// ErrDocumentIsRevoked is returned when a document is revoked
 	ErrDocumentIsRevoked = NewError("document_is_revoked")
+	// ErrDocumentIsSuspended is returned when a document is suspended
+	ErrDocumentIsSuspended = NewError("document_is_suspended")
+	// ErrDocumentNotSuspended is returned when reinstating a document that is not suspended
+	ErrDocumentNotSuspended = NewError("document_not_suspended")
+	// ErrDocumentUnderLegalHold is returned when erasing a document that is under legal hold
+	ErrDocumentUnderLegalHold = NewError("document_under_legal_hold")
+	// ErrDocumentIsErased is returned when a document has already been erased
+	ErrDocumentIsErased = NewError("document_is_erased")
 	// ErrNoTransactionID is returned when transactionID is not present
 	ErrNoTransactionID = NewError("no_transaction_id")
 
@@ -23,6 +33,33 @@ var (
 
 	// ErrEmptyPDF is returned when the PDF is empty
 	ErrEmptyPDF = NewError("empty_pdf")
+
+	// ErrTransactionNotFound is returned when no status is known for a transaction
+	ErrTransactionNotFound = NewError("transaction_not_found")
+
+	// ErrWebhookNotConfigured is returned when a callback_url is given but the organization has no webhook secret
+	ErrWebhookNotConfigured = NewError("webhook_not_configured")
+
+	// ErrBatchNotFound is returned when a batch is unknown
+	ErrBatchNotFound = NewError("batch_not_found")
+
+	// ErrEmptyBatch is returned when a batch holds no documents
+	ErrEmptyBatch = NewError("empty_batch")
+
+	// ErrSignatureMetadataNotAllowed is returned when the signature metadata matches none of the organization's templates
+	ErrSignatureMetadataNotAllowed = NewError("signature_metadata_not_allowed")
+
+	// ErrIdempotencyKeyReused is returned when an Idempotency-Key is sent again with a different request
+	ErrIdempotencyKeyReused = NewError("idempotency_key_reused")
+
+	// ErrDatabaseDisabled is returned by endpoints that need the database when mongo is disabled
+	ErrDatabaseDisabled = NewError("database_disabled")
+
+	// ErrNoPendingMessage is returned when the streams hold no message for a transaction
+	ErrNoPendingMessage = NewError("no_pending_message")
+
+	// ErrDLQEntryNotFound is returned when the DLQ stream holds no entry with the sequence
+	ErrDLQEntryNotFound = NewError("dlq_entry_not_found")
 )
 
 type Error struct {
@@ -40,6 +77,26 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("Error: [%s] %+v", e.Title, e.Details)
 }
 
+// RetryError is returned when a rate limit or quota is exceeded, the request can be retried after RetryAfter
+type RetryError struct {
+	Err        *Error
+	RetryAfter time.Duration
+}
+
+func (e *RetryError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error that is rendered to the caller
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfterSeconds is the value of the Retry-After header, at least one second
+func (e *RetryError) RetryAfterSeconds() int64 {
+	return max(int64(math.Ceil(e.RetryAfter.Seconds())), 1)
+}
+
 type ErrorResponse struct {
 	Error *Error `json:"error"`
 }
@@ -60,6 +117,9 @@ func NewErrorFromError(err error) *Error {
 	if pbErr, ok := err.(*Error); ok {
 		return pbErr
 	}
+	if retryErr, ok := err.(*RetryError); ok {
+		return retryErr.Err
+	}
 	if jsonUnmarshalTypeError, ok := err.(*json.UnmarshalTypeError); ok {
 		return &Error{Title: "json_type_error", Details: formatJSONUnmarshalTypeError(jsonUnmarshalTypeError)}
 	}
@@ -0,0 +1,123 @@
+package kvclient
+
+import (
+	"context"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// maxBatchUpdateAttempts bounds the retries when the batch changes while it is updated
+const maxBatchUpdateAttempts = 3
+
+// Batch holds the batch kv object
+type Batch struct {
+	client *Client
+	key    string
+}
+
+func (b *Batch) mkKey(batchID string) string {
+	return fmt.Sprintf(b.key, batchID)
+}
+
+// Save stores the batch and its transaction ids
+func (b *Batch) Save(ctx context.Context, batch *model.Batch) error {
+	ctx, span := b.client.tp.Start(ctx, "kv:Batch:Save")
+	defer span.End()
+
+	data, err := json.Marshal(batch)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	if err := b.client.RedictCC.Set(ctx, b.mkKey(batch.BatchID), data, transactionRetention).Err(); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Get returns the batch, item states are not included
+func (b *Batch) Get(ctx context.Context, batchID string) (*model.Batch, error) {
+	ctx, span := b.client.tp.Start(ctx, "kv:Batch:Get")
+	defer span.End()
+
+	data, err := b.client.RedictCC.Get(ctx, b.mkKey(batchID)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, helpers.ErrBatchNotFound
+		}
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	batch := &model.Batch{}
+	if err := json.Unmarshal(data, batch); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// RemoveItem removes the item of transactionID from the batch, e.g. when the transaction is erased, the batch keeps its expiry.
+// The batch is read and written in a watched transaction, so concurrent removals do not undo each other.
+func (b *Batch) RemoveItem(ctx context.Context, batchID, transactionID string) error {
+	ctx, span := b.client.tp.Start(ctx, "kv:Batch:RemoveItem")
+	defer span.End()
+
+	key := b.mkKey(batchID)
+	remove := func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, key).Bytes()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				return nil
+			}
+			return err
+		}
+
+		batch := &model.Batch{}
+		if err := json.Unmarshal(data, batch); err != nil {
+			return err
+		}
+		n := len(batch.Items)
+		batch.Items = slices.DeleteFunc(batch.Items, func(item *model.BatchItem) bool {
+			return item.TransactionID == transactionID
+		})
+		if len(batch.Items) == n {
+			return nil
+		}
+
+		data, err = json.Marshal(batch)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SetArgs(ctx, key, data, redis.SetArgs{KeepTTL: true})
+			return nil
+		})
+		return err
+	}
+
+	var err error
+	for range maxBatchUpdateAttempts {
+		err = b.client.RedictCC.Watch(ctx, remove, key)
+		if !errors.Is(err, redis.TxFailedErr) {
+			break
+		}
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package kvclient
+
+import (
+	"context"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchRemoveItem(t *testing.T) {
+	ctx := context.Background()
+	c, mr := newTestClient(t)
+
+	require.NoError(t, c.Batch.Save(ctx, &model.Batch{
+		BatchID:        "batch-1",
+		OrganizationID: "org-a",
+		Items: []*model.BatchItem{
+			{Name: "alice.pdf", TransactionID: "tx-1"},
+			{Name: "bob.pdf", TransactionID: "tx-2"},
+		},
+	}))
+	ttl := mr.TTL(c.Batch.mkKey("batch-1"))
+	mr.FastForward(time.Hour)
+
+	require.NoError(t, c.Batch.RemoveItem(ctx, "batch-1", "tx-1"))
+	require.NoError(t, c.Batch.RemoveItem(ctx, "batch-1", "tx-3"), "an item that is not in the batch is ignored")
+
+	batch, err := c.Batch.Get(ctx, "batch-1")
+	require.NoError(t, err)
+	assert.Equal(t, []*model.BatchItem{{Name: "bob.pdf", TransactionID: "tx-2"}}, batch.Items)
+	assert.Equal(t, ttl-time.Hour, mr.TTL(c.Batch.mkKey("batch-1")), "the batch keeps its expiry")
+
+	require.NoError(t, c.Batch.RemoveItem(ctx, "batch-2", "tx-1"), "an expired batch is ignored")
+	_, err = c.Batch.Get(ctx, "batch-2")
+	assert.ErrorIs(t, err, helpers.ErrBatchNotFound)
+}
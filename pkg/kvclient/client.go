@@ -26,6 +26,14 @@ type Client struct {
 	statusTick *time.Ticker
 
 	Doc               *Doc
+	Transaction       *Transaction
+	Webhook           *Webhook
+	Batch             *Batch
+	Idempotency       *Idempotency
+	RateLimit         *RateLimit
+	Erasure           *Erasure
+	Quota             *Quota
+	Stats             *Stats
 	MetricSigning     *MetricSigning
 	MetricFetching    *MetricFetching
 	MetricValidations *MetricValidations
@@ -70,10 +78,7 @@ func New(ctx context.Context, cfg *model.Cfg, tracer *trace.Tracer, log *logger.
 
 	c.probe(ctx)
 
-	c.Doc = &Doc{client: c, key: "doc:%s:%s"}
-	c.MetricSigning = &MetricSigning{client: c, key: "metric:signings"}
-	c.MetricFetching = &MetricFetching{client: c, key: "metric:fetching"}
-	c.MetricValidations = &MetricValidations{client: c, key: "metric:validations"}
+	c.initObjects()
 
 	go func() {
 		for {
@@ -92,6 +97,22 @@ func New(ctx context.Context, cfg *model.Cfg, tracer *trace.Tracer, log *logger.
 	return c, nil
 }
 
+// initObjects sets up the kv objects and their key formats
+func (c *Client) initObjects() {
+	c.Doc = &Doc{client: c, key: "doc:%s:%s"}
+	c.Transaction = &Transaction{client: c, key: "transaction:%s"}
+	c.Webhook = &Webhook{client: c, key: "webhook:%s:attempts"}
+	c.Batch = &Batch{client: c, key: "batch:%s"}
+	c.Idempotency = &Idempotency{client: c, key: "idempotency:%s:%s"}
+	c.RateLimit = &RateLimit{client: c, key: "ratelimit:%s:%s:%d", slidingKey: "ratelimit:%s:%s"}
+	c.Quota = &Quota{client: c, key: "quota:%s:%s"}
+	c.Erasure = &Erasure{client: c, key: "erased:%s"}
+	c.Stats = &Stats{client: c, key: "stats:%s:%s:%d", organizationsKey: "stats:organizations"}
+	c.MetricSigning = &MetricSigning{client: c, key: "metric:signings"}
+	c.MetricFetching = &MetricFetching{client: c, key: "metric:fetching"}
+	c.MetricValidations = &MetricValidations{client: c, key: "metric:validations"}
+}
+
 func (c *Client) probe(ctx context.Context) {
 	c.probeStore.PreviousResult = &v1_status.StatusProbe{
 		Name:          "kv",
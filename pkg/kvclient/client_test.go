@@ -0,0 +1,31 @@
+package kvclient
+
+import (
+	"eduseal/pkg/logger"
+	"eduseal/pkg/model"
+	"eduseal/pkg/trace"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// newTestClient returns a client on an in-memory redis, the redis is returned to inspect or change the keys
+func newTestClient(t *testing.T) (*Client, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+
+	c := &Client{
+		RedictCC: redis.NewClusterClient(&redis.ClusterOptions{Addrs: []string{mr.Addr()}}),
+		cfg:      &model.Cfg{},
+		log:      logger.NewSimple("test"),
+		tp:       &trace.Tracer{Tracer: noop.NewTracerProvider().Tracer("")},
+	}
+	c.initObjects()
+	t.Cleanup(func() {
+		require.NoError(t, c.RedictCC.Close())
+	})
+
+	return c, mr
+}
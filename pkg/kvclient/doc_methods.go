@@ -10,6 +10,9 @@ import (
 	"go.opentelemetry.io/otel/codes"
 )
 
+// SignedDocTTL is how long a signed document is cached after it has been sealed
+const SignedDocTTL = 10 * time.Second
+
 // Doc holds the document kv object
 type Doc struct {
 	client *Client
@@ -39,7 +42,20 @@ func (d *Doc) SaveSigned(ctx context.Context, doc *model.Document) error {
 		return err
 	}
 
-	if err := d.client.RedictCC.Expire(ctx, d.signedKey(doc.TransactionID), 10*time.Second).Err(); err != nil {
+	if err := d.client.RedictCC.Expire(ctx, d.signedKey(doc.TransactionID), SignedDocTTL).Err(); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// KeepSigned extends how long the signed document is cached
+func (d *Doc) KeepSigned(ctx context.Context, transactionID string, ttl time.Duration) error {
+	ctx, span := d.client.tp.Start(ctx, "kv:KeepSigned")
+	defer span.End()
+
+	if err := d.client.RedictCC.Expire(ctx, d.signedKey(transactionID), ttl).Err(); err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
@@ -52,8 +68,18 @@ func (d *Doc) GetSigned(ctx context.Context, transactionID string) (*model.Docum
 	ctx, span := d.client.tp.Start(ctx, "kv:GetSigned")
 	defer span.End()
 
+	res := d.client.RedictCC.HGetAll(ctx, d.signedKey(transactionID))
+	if err := res.Err(); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if len(res.Val()) == 0 {
+		span.SetStatus(codes.Error, helpers.ErrNoDocumentFound.Error())
+		return nil, helpers.ErrNoDocumentFound
+	}
+
 	dest := &model.Document{}
-	if err := d.client.RedictCC.HGetAll(ctx, d.signedKey(transactionID)).Scan(dest); err != nil {
+	if err := res.Scan(dest); err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
@@ -0,0 +1,72 @@
+package kvclient
+
+import (
+	"context"
+	"eduseal/pkg/helpers"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// erasedRetention is how long an erased transaction is remembered, sealer replies that arrive after the erasure are dropped meanwhile
+const erasedRetention = 7 * 24 * time.Hour
+
+// Erasure holds the erased transaction markers
+type Erasure struct {
+	client *Client
+	key    string
+}
+
+func (e *Erasure) mkKey(transactionID string) string {
+	return fmt.Sprintf(e.key, transactionID)
+}
+
+// Mark remembers that the transaction is erased, it has to be set before anything is removed
+func (e *Erasure) Mark(ctx context.Context, transactionID string, erasedAt int64) error {
+	ctx, span := e.client.tp.Start(ctx, "kv:Erasure:Mark")
+	defer span.End()
+
+	if transactionID == "" {
+		span.SetStatus(codes.Error, helpers.ErrNoTransactionID.Error())
+		return helpers.ErrNoTransactionID
+	}
+
+	if err := e.client.RedictCC.Set(ctx, e.mkKey(transactionID), erasedAt, erasedRetention).Err(); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// IsErased reports if the transaction has been erased
+func (e *Erasure) IsErased(ctx context.Context, transactionID string) bool {
+	ctx, span := e.client.tp.Start(ctx, "kv:Erasure:IsErased")
+	defer span.End()
+
+	return e.client.RedictCC.Exists(ctx, e.mkKey(transactionID)).Val() == 1
+}
+
+// Purge removes everything kept about the transaction, the keys live in different slots so they are deleted one by one
+func (e *Erasure) Purge(ctx context.Context, transactionID string) error {
+	ctx, span := e.client.tp.Start(ctx, "kv:Erasure:Purge")
+	defer span.End()
+
+	keys := []string{
+		e.client.Doc.signedKey(transactionID),
+		e.client.Transaction.mkKey(transactionID),
+		e.client.Webhook.mkKey(transactionID),
+	}
+
+	pipe := e.client.RedictCC.Pipeline()
+	for _, key := range keys {
+		pipe.Del(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package kvclient
+
+import (
+	"context"
+	"eduseal/pkg/model"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// defaultIdempotencyRetention is used when apigw.idempotency.retention is not configured
+const defaultIdempotencyRetention = 24 * time.Hour
+
+// ErrIdempotencyKeyExpired is returned when the key expired every time between reserving and reading it
+var ErrIdempotencyKeyExpired = errors.New("idempotency key expired while it was reserved")
+
+// forget deletes an Idempotency-Key record only when it maps to the transaction id in ARGV[1]
+var forget = redis.NewScript(`
+local data = redis.call("GET", KEYS[1])
+if not data or cjson.decode(data)["transaction_id"] ~= ARGV[1] then
+	return 0
+end
+return redis.call("DEL", KEYS[1])
+`)
+
+// Idempotency holds the Idempotency-Key kv object, keys are scoped per organization
+type Idempotency struct {
+	client *Client
+	key    string
+}
+
+func (i *Idempotency) mkKey(organizationID, idempotencyKey string) string {
+	return fmt.Sprintf(i.key, organizationID, idempotencyKey)
+}
+
+func (i *Idempotency) retention() time.Duration {
+	if i.client.cfg.APIGW.Idempotency.Retention > 0 {
+		return time.Duration(i.client.cfg.APIGW.Idempotency.Retention) * time.Second
+	}
+	return defaultIdempotencyRetention
+}
+
+// Reserve stores record for the key unless the key is already used.
+// It returns nil when record was stored, else the record of the first request with the key.
+func (i *Idempotency) Reserve(ctx context.Context, organizationID, idempotencyKey string, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	ctx, span := i.client.tp.Start(ctx, "kv:Idempotency:Reserve")
+	defer span.End()
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	key := i.mkKey(organizationID, idempotencyKey)
+
+	// the key can expire between SetNX and Get, that is tried once more
+	for range 2 {
+		stored, err := i.client.RedictCC.SetNX(ctx, key, data, i.retention()).Result()
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		if stored {
+			return nil, nil
+		}
+
+		existing, err := i.client.RedictCC.Get(ctx, key).Bytes()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+
+		reply := &model.IdempotencyRecord{}
+		if err := json.Unmarshal(existing, reply); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+
+		return reply, nil
+	}
+
+	span.SetStatus(codes.Error, ErrIdempotencyKeyExpired.Error())
+	return nil, ErrIdempotencyKeyExpired
+}
+
+// Release removes the key, it is used when the request that reserved it failed so a retry can start over
+func (i *Idempotency) Release(ctx context.Context, organizationID, idempotencyKey string) error {
+	ctx, span := i.client.tp.Start(ctx, "kv:Idempotency:Release")
+	defer span.End()
+
+	if err := i.client.RedictCC.Del(ctx, i.mkKey(organizationID, idempotencyKey)).Err(); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Forget removes the key if it still maps to transactionID, it is used when the transaction is erased.
+// A key that was released and reused by a later request is left to that request.
+func (i *Idempotency) Forget(ctx context.Context, organizationID, idempotencyKey, transactionID string) error {
+	ctx, span := i.client.tp.Start(ctx, "kv:Idempotency:Forget")
+	defer span.End()
+
+	if err := forget.Run(ctx, i.client.RedictCC, []string{i.mkKey(organizationID, idempotencyKey)}, transactionID).Err(); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package kvclient
+
+import (
+	"context"
+	"eduseal/pkg/model"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// takenAfterExpiry lets the key expire right before every GET and another request take it again right after,
+// so SETNX and GET both miss
+type takenAfterExpiry struct {
+	mr    *miniredis.Miniredis
+	setNX int
+}
+
+func (h *takenAfterExpiry) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *takenAfterExpiry) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		switch cmd.Name() {
+		case "set", "setnx":
+			h.setNX++
+		case "get":
+			key := cmd.Args()[1].(string)
+			value, _ := h.mr.Get(key)
+			h.mr.Del(key)
+			defer h.mr.Set(key, value)
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h *takenAfterExpiry) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestIdempotencyReserve(t *testing.T) {
+	ctx := context.Background()
+	first := &model.IdempotencyRecord{TransactionID: "tx-1", RequestHash: "hash-1", CreatedAt: 1}
+	retry := &model.IdempotencyRecord{TransactionID: "tx-2", RequestHash: "hash-1", CreatedAt: 2}
+
+	tts := []struct {
+		name string
+		// setup runs before the second reservation
+		setup func(t *testing.T, c *Client)
+		want  *model.IdempotencyRecord
+	}{
+		{
+			name:  "retry gets the first record",
+			setup: func(t *testing.T, c *Client) {},
+			want:  first,
+		},
+		{
+			name: "other organization",
+			setup: func(t *testing.T, c *Client) {
+				stored, err := c.Idempotency.Reserve(ctx, "org-b", "key-1", first)
+				require.NoError(t, err)
+				require.Nil(t, stored)
+			},
+			want: first,
+		},
+		{
+			name: "released key is reserved again",
+			setup: func(t *testing.T, c *Client) {
+				require.NoError(t, c.Idempotency.Release(ctx, "org-a", "key-1"))
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestClient(t)
+
+			stored, err := c.Idempotency.Reserve(ctx, "org-a", "key-1", first)
+			require.NoError(t, err)
+			assert.Nil(t, stored, "the first request reserves the key")
+
+			tt.setup(t, c)
+
+			stored, err = c.Idempotency.Reserve(ctx, "org-a", "key-1", retry)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, stored)
+		})
+	}
+}
+
+func TestIdempotencyReserveExpiredKey(t *testing.T) {
+	ctx := context.Background()
+	c, mr := newTestClient(t)
+
+	_, err := c.Idempotency.Reserve(ctx, "org-a", "key-1", &model.IdempotencyRecord{TransactionID: "tx-1"})
+	require.NoError(t, err)
+
+	hook := &takenAfterExpiry{mr: mr}
+	c.RedictCC.AddHook(hook)
+
+	_, err = c.Idempotency.Reserve(ctx, "org-a", "key-1", &model.IdempotencyRecord{TransactionID: "tx-2"})
+	assert.ErrorIs(t, err, ErrIdempotencyKeyExpired)
+	assert.Equal(t, 2, hook.setNX, "the reservation is tried once more")
+}
+
+func TestIdempotencyForget(t *testing.T) {
+	tts := []struct {
+		name          string
+		transactionID string
+		wantForgotten bool
+	}{
+		{
+			name:          "key of the erased transaction",
+			transactionID: "tx-1",
+			wantForgotten: true,
+		},
+		{
+			name:          "key reused by a later transaction",
+			transactionID: "tx-0",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c, _ := newTestClient(t)
+
+			_, err := c.Idempotency.Reserve(ctx, "org-a", "key-1", &model.IdempotencyRecord{TransactionID: "tx-1"})
+			require.NoError(t, err)
+
+			require.NoError(t, c.Idempotency.Forget(ctx, "org-a", "key-1", tt.transactionID))
+
+			stored, err := c.Idempotency.Reserve(ctx, "org-a", "key-1", &model.IdempotencyRecord{TransactionID: "tx-2"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantForgotten, stored == nil)
+		})
+	}
+}
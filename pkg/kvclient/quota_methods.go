@@ -0,0 +1,149 @@
+package kvclient
+
+import (
+	"context"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Quota holds the sealed document counters of each organization, per UTC calendar day and month
+type Quota struct {
+	client *Client
+	key    string
+}
+
+// QuotaUsage is how much of a quota is used in the current period
+type QuotaUsage struct {
+	Limit    int64 `json:"limit"`
+	Used     int64 `json:"used"`
+	ResetsAt int64 `json:"resets_at"`
+}
+
+// quotaPeriod is one calendar period of a quota
+type quotaPeriod struct {
+	name     string
+	id       string
+	limit    int64
+	resetsAt time.Time
+}
+
+func (q *Quota) mkKey(organizationID string, period quotaPeriod) string {
+	return fmt.Sprintf(q.key, organizationID, period.id)
+}
+
+// periods returns the daily and the monthly period containing now
+func (q *Quota) periods(now time.Time, limits model.TenantLimits) []quotaPeriod {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return []quotaPeriod{
+		{name: "daily_seals", id: day.Format("day:2006-01-02"), limit: limits.DailySeals, resetsAt: day.AddDate(0, 0, 1)},
+		{name: "monthly_seals", id: month.Format("month:2006-01"), limit: limits.MonthlySeals, resetsAt: month.AddDate(0, 1, 0)},
+	}
+}
+
+// Consume counts n documents against the organization's quotas, nothing is counted when a quota would be exceeded.
+// The counters are kept for unlimited organizations too, so their usage can be reported.
+// It returns the keys of the counted periods, a refund has to go to those even when a period ended in between.
+func (q *Quota) Consume(ctx context.Context, organizationID string, n int64, limits model.TenantLimits) ([]string, error) {
+	ctx, span := q.client.tp.Start(ctx, "kv:Quota:Consume")
+	defer span.End()
+
+	now := time.Now()
+	periods := q.periods(now, limits)
+
+	pipe := q.client.RedictCC.Pipeline()
+	keys := make([]string, len(periods))
+	counts := make([]*redis.IntCmd, len(periods))
+	for i, period := range periods {
+		keys[i] = q.mkKey(organizationID, period)
+		counts[i] = pipe.IncrBy(ctx, keys[i], n)
+		// a day longer than the period, so the usage of the period can still be read when it ends
+		pipe.ExpireAt(ctx, keys[i], period.resetsAt.Add(24*time.Hour))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	for i, period := range periods {
+		if period.limit <= 0 || counts[i].Val() <= period.limit {
+			continue
+		}
+		if err := q.refund(ctx, keys, n); err != nil {
+			q.client.log.Error(err, "failed to refund quota", "organization_id", organizationID)
+		}
+		err := &helpers.RetryError{
+			Err: helpers.NewErrorDetails("quota_exceeded", map[string]any{
+				"quota":     period.name,
+				"limit":     period.limit,
+				"used":      counts[i].Val() - n,
+				"requested": n,
+				"resets_at": period.resetsAt.Unix(),
+			}),
+			RetryAfter: period.resetsAt.Sub(now),
+		}
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Refund gives back n documents to the period keys Consume returned, e.g. when they could not be queued
+func (q *Quota) Refund(ctx context.Context, keys []string, n int64) error {
+	ctx, span := q.client.tp.Start(ctx, "kv:Quota:Refund")
+	defer span.End()
+
+	if err := q.refund(ctx, keys, n); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (q *Quota) refund(ctx context.Context, keys []string, n int64) error {
+	pipe := q.client.RedictCC.Pipeline()
+	for _, key := range keys {
+		pipe.DecrBy(ctx, key, n)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Usage returns the daily and monthly usage of the organization's quotas
+func (q *Quota) Usage(ctx context.Context, organizationID string, limits model.TenantLimits) (map[string]*QuotaUsage, error) {
+	ctx, span := q.client.tp.Start(ctx, "kv:Quota:Usage")
+	defer span.End()
+
+	periods := q.periods(time.Now(), limits)
+
+	pipe := q.client.RedictCC.Pipeline()
+	counts := make([]*redis.StringCmd, len(periods))
+	for i, period := range periods {
+		counts[i] = pipe.Get(ctx, q.mkKey(organizationID, period))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	reply := map[string]*QuotaUsage{}
+	for i, period := range periods {
+		used, _ := counts[i].Int64()
+		reply[period.name] = &QuotaUsage{
+			Limit:    period.limit,
+			Used:     used,
+			ResetsAt: period.resetsAt.Unix(),
+		}
+	}
+
+	return reply, nil
+}
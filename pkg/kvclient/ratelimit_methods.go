@@ -0,0 +1,101 @@
+package kvclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// slidingWindow keeps the requests of the last window in a sorted set scored by time in milliseconds.
+// It returns {1, 0} when the request is allowed and {0, ms until the oldest request leaves the window} when not.
+var slidingWindow = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call("ZREMRANGEBYSCORE", key, "-inf", now - window)
+if redis.call("ZCARD", key) < limit then
+	redis.call("ZADD", key, now, ARGV[4])
+	redis.call("PEXPIRE", key, window)
+	return {1, 0}
+end
+local oldest = redis.call("ZRANGE", key, 0, 0, "WITHSCORES")
+return {0, tonumber(oldest[2]) + window - now}
+`)
+
+// RateLimit holds the request counters, fixed windows have one key per bucket, caller and window, sliding windows one per bucket and caller
+type RateLimit struct {
+	client     *Client
+	key        string
+	slidingKey string
+}
+
+func (r *RateLimit) mkKey(bucket, id string, windowStart int64) string {
+	return fmt.Sprintf(r.key, bucket, id, windowStart)
+}
+
+func (r *RateLimit) mkSlidingKey(bucket, id string) string {
+	return fmt.Sprintf(r.slidingKey, bucket, id)
+}
+
+// AllowSliding counts one request by id in bucket if it is within limit for the window ending now,
+// if not it returns how long until a request leaves the window.
+func (r *RateLimit) AllowSliding(ctx context.Context, bucket, id string, limit int64, window time.Duration) (bool, time.Duration, error) {
+	ctx, span := r.client.tp.Start(ctx, "kv:RateLimit:AllowSliding")
+	defer span.End()
+
+	now := time.Now().UnixMilli()
+	res, err := slidingWindow.Run(ctx, r.client.RedictCC, []string{r.mkSlidingKey(bucket, id)}, now, window.Milliseconds(), limit, uuid.NewString()).Int64Slice()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return false, 0, err
+	}
+
+	if res[0] == 1 {
+		return true, 0, nil
+	}
+	return false, time.Duration(res[1]) * time.Millisecond, nil
+}
+
+// SlidingUsage returns how many requests by id in bucket are in the window ending now
+func (r *RateLimit) SlidingUsage(ctx context.Context, bucket, id string, window time.Duration) (int64, error) {
+	ctx, span := r.client.tp.Start(ctx, "kv:RateLimit:SlidingUsage")
+	defer span.End()
+
+	from := time.Now().Add(-window).UnixMilli()
+	n, err := r.client.RedictCC.ZCount(ctx, r.mkSlidingKey(bucket, id), fmt.Sprintf("(%d", from), "+inf").Result()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return 0, err
+	}
+	return n, nil
+}
+
+// Allow counts one request by id in bucket. It reports if the request is within limit for the current window,
+// and if not, how long until the window ends.
+func (r *RateLimit) Allow(ctx context.Context, bucket, id string, limit int64, window time.Duration) (bool, time.Duration, error) {
+	ctx, span := r.client.tp.Start(ctx, "kv:RateLimit:Allow")
+	defer span.End()
+
+	now := time.Now()
+	windowStart := now.Truncate(window)
+	key := r.mkKey(bucket, id, windowStart.Unix())
+
+	pipe := r.client.RedictCC.TxPipeline()
+	count := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return false, 0, err
+	}
+
+	if count.Val() > limit {
+		return false, windowStart.Add(window).Sub(now), nil
+	}
+
+	return true, 0, nil
+}
@@ -0,0 +1,118 @@
+package kvclient
+
+import (
+	"context"
+	"eduseal/pkg/model"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// statsAnonymous is the organization of operations made without a jwt
+	statsAnonymous = "anonymous"
+	// hourlyStatsRetention is how long the hourly buckets are kept
+	hourlyStatsRetention = 14 * 24 * time.Hour
+	// dailyStatsRetention is how long the daily buckets are kept
+	dailyStatsRetention = 400 * 24 * time.Hour
+)
+
+// Stats holds the usage statistics of each organization, as one hash per organization and bucket with a field per operation
+type Stats struct {
+	client           *Client
+	key              string
+	organizationsKey string
+}
+
+func (s *Stats) mkKey(organizationID string, granularity model.StatsGranularity, start time.Time) string {
+	return fmt.Sprintf(s.key, organizationID, granularity, start.Unix())
+}
+
+// Retention returns how long buckets of granularity are kept
+func (s *Stats) Retention(granularity model.StatsGranularity) time.Duration {
+	if granularity == model.StatsGranularityDay {
+		return dailyStatsRetention
+	}
+	return hourlyStatsRetention
+}
+
+// Inc counts one operation of the organization in the current hourly bucket and rolls it up into the daily bucket
+func (s *Stats) Inc(ctx context.Context, organizationID string, operation model.StatsOperation) error {
+	ctx, span := s.client.tp.Start(ctx, "kv:Stats:Inc")
+	defer span.End()
+
+	if organizationID == "" {
+		organizationID = statsAnonymous
+	}
+	now := time.Now()
+
+	pipe := s.client.RedictCC.Pipeline()
+	for _, granularity := range model.StatsGranularities {
+		start := granularity.Truncate(now)
+		key := s.mkKey(organizationID, granularity, start)
+		pipe.HIncrBy(ctx, key, string(operation), 1)
+		pipe.ExpireAt(ctx, key, granularity.Next(start).Add(s.Retention(granularity)))
+	}
+	pipe.SAdd(ctx, s.organizationsKey, organizationID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Buckets returns the organization's buckets overlapping [from, to), expired and empty buckets are all zero
+func (s *Stats) Buckets(ctx context.Context, organizationID string, granularity model.StatsGranularity, from, to time.Time) ([]*model.StatsBucket, error) {
+	ctx, span := s.client.tp.Start(ctx, "kv:Stats:Buckets")
+	defer span.End()
+
+	if organizationID == "" {
+		organizationID = statsAnonymous
+	}
+	starts := granularity.BucketStarts(from, to)
+	if len(starts) == 0 {
+		return []*model.StatsBucket{}, nil
+	}
+
+	pipe := s.client.RedictCC.Pipeline()
+	counts := make([]*redis.MapStringStringCmd, len(starts))
+	for i, start := range starts {
+		counts[i] = pipe.HGetAll(ctx, s.mkKey(organizationID, granularity, start))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	buckets := make([]*model.StatsBucket, len(starts))
+	for i, start := range starts {
+		buckets[i] = &model.StatsBucket{Start: start.Unix()}
+		for operation, value := range counts[i].Val() {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			buckets[i].Add(model.StatsOperation(operation), n)
+		}
+	}
+
+	return buckets, nil
+}
+
+// Organizations returns every organization that has made a counted operation, operations made without a jwt are counted as "anonymous"
+func (s *Stats) Organizations(ctx context.Context) ([]string, error) {
+	ctx, span := s.client.tp.Start(ctx, "kv:Stats:Organizations")
+	defer span.End()
+
+	organizations, err := s.client.RedictCC.SMembers(ctx, s.organizationsKey).Result()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return organizations, nil
+}
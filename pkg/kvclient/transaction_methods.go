@@ -0,0 +1,227 @@
+package kvclient
+
+import (
+	"context"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// transactionRetention is how long the lifecycle status of a transaction is kept after its last transition
+const transactionRetention = 30 * 24 * time.Hour
+
+// transition records a state transition, the state only changes when the new state has the same or a higher rank.
+// The read and the write are one script so concurrent events can not move the state backwards.
+// ARGV is state, ts, rank, sealer backend, error and retention in seconds, followed by state and rank pairs.
+var transition = redis.NewScript(`
+local key = KEYS[1]
+local current = redis.call("HGET", key, "state")
+local rank = 0
+if current then
+	for i = 7, #ARGV, 2 do
+		if ARGV[i] == current then
+			rank = tonumber(ARGV[i + 1])
+		end
+	end
+end
+redis.call("HSET", key, "ts_" .. ARGV[1], ARGV[2])
+if tonumber(ARGV[3]) >= rank then
+	redis.call("HSET", key, "state", ARGV[1])
+end
+if ARGV[4] ~= "" then
+	redis.call("HSET", key, "sealer_backend", ARGV[4])
+end
+if ARGV[5] ~= "" then
+	redis.call("HSET", key, "error", ARGV[5])
+end
+redis.call("EXPIRE", key, ARGV[6])
+return 1
+`)
+
+// transitionFrom records a state transition only when the current state is the expected one, whatever the ranks.
+// ARGV is the expected state, state, ts and retention in seconds, it returns 1 when the state changed.
+var transitionFrom = redis.NewScript(`
+local key = KEYS[1]
+if redis.call("HGET", key, "state") ~= ARGV[1] then
+	return 0
+end
+redis.call("HSET", key, "state", ARGV[2])
+redis.call("HSET", key, "ts_" .. ARGV[2], ARGV[3])
+redis.call("EXPIRE", key, ARGV[4])
+return 1
+`)
+
+// Transaction holds the transaction lifecycle kv object
+type Transaction struct {
+	client *Client
+	key    string
+}
+
+func (t *Transaction) mkKey(transactionID string) string {
+	return fmt.Sprintf(t.key, transactionID)
+}
+
+// Transition records that transactionID entered state, sealerBackend and errMsg are only stored when not empty
+func (t *Transaction) Transition(ctx context.Context, transactionID string, state model.TransactionState, sealerBackend, errMsg string) error {
+	ctx, span := t.client.tp.Start(ctx, "kv:Transaction:Transition")
+	defer span.End()
+
+	if transactionID == "" {
+		span.SetStatus(codes.Error, helpers.ErrNoTransactionID.Error())
+		return helpers.ErrNoTransactionID
+	}
+
+	args := []any{string(state), time.Now().Unix(), state.Rank(), sealerBackend, errMsg, int64(transactionRetention.Seconds())}
+	for s, rank := range model.TransactionStateRanks() {
+		args = append(args, string(s), rank)
+	}
+	if err := transition.Run(ctx, t.client.RedictCC, []string{t.mkKey(transactionID)}, args...).Err(); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	t.client.log.Debug("transaction transition", "transaction_id", transactionID, "state", state)
+
+	return nil
+}
+
+// TransitionFrom moves transactionID from state from to state to, also to a lower rank, e.g. from suspended back to sealed.
+// It reports false and leaves the state as it is when the transaction is not in state from.
+func (t *Transaction) TransitionFrom(ctx context.Context, transactionID string, from, to model.TransactionState) (bool, error) {
+	ctx, span := t.client.tp.Start(ctx, "kv:Transaction:TransitionFrom")
+	defer span.End()
+
+	if transactionID == "" {
+		span.SetStatus(codes.Error, helpers.ErrNoTransactionID.Error())
+		return false, helpers.ErrNoTransactionID
+	}
+
+	moved, err := transitionFrom.Run(ctx, t.client.RedictCC, []string{t.mkKey(transactionID)}, string(from), string(to), time.Now().Unix(), int64(transactionRetention.Seconds())).Int()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return false, err
+	}
+
+	t.client.log.Debug("transaction transition", "transaction_id", transactionID, "from", from, "state", to, "moved", moved == 1)
+
+	return moved == 1, nil
+}
+
+// Get returns the lifecycle status of transactionID
+func (t *Transaction) Get(ctx context.Context, transactionID string) (*model.TransactionStatus, error) {
+	ctx, span := t.client.tp.Start(ctx, "kv:Transaction:Get")
+	defer span.End()
+
+	res, err := t.client.RedictCC.HGetAll(ctx, t.mkKey(transactionID)).Result()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, helpers.ErrTransactionNotFound
+	}
+
+	status := &model.TransactionStatus{
+		TransactionID: transactionID,
+		State:         model.TransactionState(res["state"]),
+		SealerBackend: res["sealer_backend"],
+		Error:         res["error"],
+		Transitions:   []*model.TransactionTransition{},
+	}
+
+	for field, value := range res {
+		state, ok := strings.CutPrefix(field, "ts_")
+		if !ok {
+			continue
+		}
+		ts, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		status.Transitions = append(status.Transitions, &model.TransactionTransition{
+			State: model.TransactionState(state),
+			TS:    ts,
+		})
+	}
+
+	if validation, ok := res["validation"]; ok {
+		status.Validation = &model.ValidationOutcome{}
+		if err := json.Unmarshal([]byte(validation), status.Validation); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+	}
+
+	sort.Slice(status.Transitions, func(i, j int) bool {
+		if status.Transitions[i].TS == status.Transitions[j].TS {
+			return status.Transitions[i].State.Rank() < status.Transitions[j].State.Rank()
+		}
+		return status.Transitions[i].TS < status.Transitions[j].TS
+	})
+
+	return status, nil
+}
+
+// SetValidation stores the outcome of the latest validation of transactionID
+func (t *Transaction) SetValidation(ctx context.Context, transactionID string, outcome *model.ValidationOutcome) error {
+	ctx, span := t.client.tp.Start(ctx, "kv:Transaction:SetValidation")
+	defer span.End()
+
+	b, err := json.Marshal(outcome)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	key := t.mkKey(transactionID)
+
+	pipe := t.client.RedictCC.TxPipeline()
+	pipe.HSet(ctx, key, "validation", b)
+	pipe.Expire(ctx, key, transactionRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// SetMeta stores the client supplied context of transactionID
+func (t *Transaction) SetMeta(ctx context.Context, transactionID string, meta *model.TransactionMeta) error {
+	ctx, span := t.client.tp.Start(ctx, "kv:Transaction:SetMeta")
+	defer span.End()
+
+	key := t.mkKey(transactionID)
+
+	pipe := t.client.RedictCC.TxPipeline()
+	pipe.HSet(ctx, key, meta)
+	pipe.Expire(ctx, key, transactionRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetMeta returns the client supplied context of transactionID, fields are empty if nothing is stored
+func (t *Transaction) GetMeta(ctx context.Context, transactionID string) (*model.TransactionMeta, error) {
+	ctx, span := t.client.tp.Start(ctx, "kv:Transaction:GetMeta")
+	defer span.End()
+
+	meta := &model.TransactionMeta{}
+	if err := t.client.RedictCC.HMGet(ctx, t.mkKey(transactionID), model.TransactionMetaFields...).Scan(meta); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return meta, nil
+}
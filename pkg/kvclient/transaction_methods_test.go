@@ -0,0 +1,143 @@
+package kvclient
+
+import (
+	"context"
+	"eduseal/pkg/helpers"
+	"eduseal/pkg/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionTransition(t *testing.T) {
+	tts := []struct {
+		name        string
+		transitions []model.TransactionState
+		want        model.TransactionState
+	}{
+		{
+			name:        "in order",
+			transitions: []model.TransactionState{model.TransactionStateQueued, model.TransactionStateSealing, model.TransactionStateSealed},
+			want:        model.TransactionStateSealed,
+		},
+		{
+			name:        "sealing notification after the cache reply",
+			transitions: []model.TransactionState{model.TransactionStateQueued, model.TransactionStateSealed, model.TransactionStateSealing},
+			want:        model.TransactionStateSealed,
+		},
+		{
+			name:        "late cache reply of a suspended document",
+			transitions: []model.TransactionState{model.TransactionStateSealed, model.TransactionStateSuspended, model.TransactionStateSealed},
+			want:        model.TransactionStateSuspended,
+		},
+		{
+			name:        "expired document suspended",
+			transitions: []model.TransactionState{model.TransactionStateSealed, model.TransactionStateExpired, model.TransactionStateSuspended},
+			want:        model.TransactionStateSuspended,
+		},
+		{
+			name:        "suspended document revoked",
+			transitions: []model.TransactionState{model.TransactionStateSealed, model.TransactionStateSuspended, model.TransactionStateRevoked},
+			want:        model.TransactionStateRevoked,
+		},
+		{
+			name:        "revoked document suspended",
+			transitions: []model.TransactionState{model.TransactionStateSealed, model.TransactionStateRevoked, model.TransactionStateSuspended},
+			want:        model.TransactionStateRevoked,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c, _ := newTestClient(t)
+
+			for _, state := range tt.transitions {
+				require.NoError(t, c.Transaction.Transition(ctx, "tx-1", state, "", ""))
+			}
+
+			status, err := c.Transaction.Get(ctx, "tx-1")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, status.State)
+
+			recorded := []model.TransactionState{}
+			for _, transition := range status.Transitions {
+				recorded = append(recorded, transition.State)
+			}
+			for _, state := range tt.transitions {
+				assert.Contains(t, recorded, state, "every transition is recorded, also the ones that do not change the state")
+			}
+		})
+	}
+}
+
+func TestTransactionTransitionFrom(t *testing.T) {
+	tts := []struct {
+		name      string
+		current   model.TransactionState
+		from, to  model.TransactionState
+		wantMoved bool
+		want      model.TransactionState
+	}{
+		{
+			name:      "reinstated",
+			current:   model.TransactionStateSuspended,
+			from:      model.TransactionStateSuspended,
+			to:        model.TransactionStateSealed,
+			wantMoved: true,
+			want:      model.TransactionStateSealed,
+		},
+		{
+			name:    "revoked while reinstating",
+			current: model.TransactionStateRevoked,
+			from:    model.TransactionStateSuspended,
+			to:      model.TransactionStateSealed,
+			want:    model.TransactionStateRevoked,
+		},
+		{
+			name:      "republished",
+			current:   model.TransactionStateSealing,
+			from:      model.TransactionStateSealing,
+			to:        model.TransactionStateQueued,
+			wantMoved: true,
+			want:      model.TransactionStateQueued,
+		},
+		{
+			name:    "sealed before republishing",
+			current: model.TransactionStateSealed,
+			from:    model.TransactionStateSealing,
+			to:      model.TransactionStateQueued,
+			want:    model.TransactionStateSealed,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c, _ := newTestClient(t)
+
+			require.NoError(t, c.Transaction.Transition(ctx, "tx-1", tt.current, "", ""))
+
+			moved, err := c.Transaction.TransitionFrom(ctx, "tx-1", tt.from, tt.to)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMoved, moved)
+
+			status, err := c.Transaction.Get(ctx, "tx-1")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, status.State)
+		})
+	}
+}
+
+func TestTransactionTransitionFromUnknown(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestClient(t)
+
+	moved, err := c.Transaction.TransitionFrom(ctx, "tx-1", model.TransactionStateSuspended, model.TransactionStateSealed)
+	require.NoError(t, err)
+	assert.False(t, moved)
+
+	_, err = c.Transaction.Get(ctx, "tx-1")
+	assert.ErrorIs(t, err, helpers.ErrTransactionNotFound, "nothing is stored for an unknown transaction")
+}
@@ -0,0 +1,68 @@
+package kvclient
+
+import (
+	"context"
+	"eduseal/pkg/model"
+	"encoding/json"
+	"fmt"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Webhook holds the webhook delivery attempts kv object
+type Webhook struct {
+	client *Client
+	key    string
+}
+
+func (w *Webhook) mkKey(transactionID string) string {
+	return fmt.Sprintf(w.key, transactionID)
+}
+
+// AddAttempt appends a delivery attempt to the history of transactionID
+func (w *Webhook) AddAttempt(ctx context.Context, transactionID string, attempt *model.WebhookAttempt) error {
+	ctx, span := w.client.tp.Start(ctx, "kv:Webhook:AddAttempt")
+	defer span.End()
+
+	b, err := json.Marshal(attempt)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	key := w.mkKey(transactionID)
+
+	pipe := w.client.RedictCC.TxPipeline()
+	pipe.RPush(ctx, key, b)
+	pipe.Expire(ctx, key, transactionRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Attempts returns every delivery attempt of transactionID, oldest first
+func (w *Webhook) Attempts(ctx context.Context, transactionID string) ([]*model.WebhookAttempt, error) {
+	ctx, span := w.client.tp.Start(ctx, "kv:Webhook:Attempts")
+	defer span.End()
+
+	res, err := w.client.RedictCC.LRange(ctx, w.mkKey(transactionID), 0, -1).Result()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	attempts := make([]*model.WebhookAttempt, 0, len(res))
+	for _, r := range res {
+		attempt := &model.WebhookAttempt{}
+		if err := json.Unmarshal([]byte(r), attempt); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, nil
+}
@@ -18,6 +18,7 @@ import (
 type Metric struct {
 	Provider *sdkmetric.MeterProvider
 	exporter *otlpmetricgrpc.Exporter
+	reader   *sdkmetric.ManualReader
 	log      *logger.Log
 	metric.Meter
 }
@@ -0,0 +1,182 @@
+package metric
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// PrometheusContentType is the content type of the text exposition format written by WritePrometheus
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// NewPrometheus returns a metric that is scraped through WritePrometheus instead of pushed to a collector
+func NewPrometheus(serviceName string) *Metric {
+	m := &Metric{
+		reader: sdkmetric.NewManualReader(),
+	}
+	m.Provider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(m.reader))
+	m.Meter = m.Provider.Meter(serviceName)
+
+	return m
+}
+
+// WritePrometheus collects every instrument, observable callbacks included, and writes them in the prometheus text format.
+// Instrument names have their dots replaced by underscores, monotonic sums get the _total suffix.
+func (m *Metric) WritePrometheus(ctx context.Context, w io.Writer) error {
+	if m.reader == nil {
+		return errors.New("metric is not created by NewPrometheus")
+	}
+
+	rm := &metricdata.ResourceMetrics{}
+	if err := m.reader.Collect(ctx, rm); err != nil {
+		return err
+	}
+
+	metrics := []metricdata.Metrics{}
+	for _, sm := range rm.ScopeMetrics {
+		metrics = append(metrics, sm.Metrics...)
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+
+	b := bufio.NewWriter(w)
+	for _, metric := range metrics {
+		writeMetric(b, metric)
+	}
+
+	return b.Flush()
+}
+
+func writeMetric(b *bufio.Writer, metric metricdata.Metrics) {
+	name := promName(metric.Name)
+
+	switch data := metric.Data.(type) {
+	case metricdata.Sum[int64]:
+		writeSum(b, name, metric.Description, data.IsMonotonic, data.DataPoints)
+	case metricdata.Sum[float64]:
+		writeSum(b, name, metric.Description, data.IsMonotonic, data.DataPoints)
+	case metricdata.Gauge[int64]:
+		writeHeader(b, name, metric.Description, "gauge")
+		for _, dp := range data.DataPoints {
+			writeSample(b, name, dp.Attributes, nil, float64(dp.Value))
+		}
+	case metricdata.Gauge[float64]:
+		writeHeader(b, name, metric.Description, "gauge")
+		for _, dp := range data.DataPoints {
+			writeSample(b, name, dp.Attributes, nil, dp.Value)
+		}
+	case metricdata.Histogram[int64]:
+		writeHistogram(b, name, metric.Description, data.DataPoints)
+	case metricdata.Histogram[float64]:
+		writeHistogram(b, name, metric.Description, data.DataPoints)
+	}
+}
+
+func writeSum[N int64 | float64](b *bufio.Writer, name, description string, monotonic bool, dataPoints []metricdata.DataPoint[N]) {
+	kind := "gauge"
+	if monotonic {
+		kind = "counter"
+		if !strings.HasSuffix(name, "_total") {
+			name += "_total"
+		}
+	}
+	writeHeader(b, name, description, kind)
+	for _, dp := range dataPoints {
+		writeSample(b, name, dp.Attributes, nil, float64(dp.Value))
+	}
+}
+
+func writeHistogram[N int64 | float64](b *bufio.Writer, name, description string, dataPoints []metricdata.HistogramDataPoint[N]) {
+	writeHeader(b, name, description, "histogram")
+	for _, dp := range dataPoints {
+		cumulative := uint64(0)
+		for i, bound := range dp.Bounds {
+			cumulative += dp.BucketCounts[i]
+			writeSample(b, name+"_bucket", dp.Attributes, &attribute.KeyValue{Key: "le", Value: attribute.StringValue(formatFloat(bound))}, float64(cumulative))
+		}
+		writeSample(b, name+"_bucket", dp.Attributes, &attribute.KeyValue{Key: "le", Value: attribute.StringValue("+Inf")}, float64(dp.Count))
+		writeSample(b, name+"_sum", dp.Attributes, nil, float64(dp.Sum))
+		writeSample(b, name+"_count", dp.Attributes, nil, float64(dp.Count))
+	}
+}
+
+func writeHeader(b *bufio.Writer, name, description, kind string) {
+	if description != "" {
+		fmt.Fprintf(b, "# HELP %s %s\n", name, escapeHelp(description))
+	}
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, kind)
+}
+
+// writeSample writes one line, extra is a label added after the attributes, e.g. the le label of a bucket
+func writeSample(b *bufio.Writer, name string, attributes attribute.Set, extra *attribute.KeyValue, value float64) {
+	b.WriteString(name)
+
+	labels := attributes.ToSlice()
+	if extra != nil {
+		labels = append(labels, *extra)
+	}
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(b, "%s=\"%s\"", promName(string(label.Key)), escapeLabel(label.Value.Emit()))
+		}
+		b.WriteByte('}')
+	}
+
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(value))
+	b.WriteByte('\n')
+}
+
+// promName replaces the characters prometheus does not allow in metric and label names
+func promName(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':':
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9' && i > 0:
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
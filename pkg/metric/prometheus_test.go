@@ -0,0 +1,54 @@
+package metric
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	ctx := context.Background()
+	m := NewPrometheus("test")
+
+	counter, err := m.Int64Counter("eduseal.seals", metric.WithDescription("sealed documents"))
+	assert.NoError(t, err)
+	counter.Add(ctx, 2, metric.WithAttributes(attribute.String("organization_id", `a"b`)))
+
+	histogram, err := m.Float64Histogram("http.duration", metric.WithExplicitBucketBoundaries(0.1, 1))
+	assert.NoError(t, err)
+	histogram.Record(ctx, 0.05, metric.WithAttributes(attribute.Int("status", 200)))
+	histogram.Record(ctx, 0.5, metric.WithAttributes(attribute.Int("status", 200)))
+
+	_, err = m.Int64ObservableGauge("probe.healthy", metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+		o.Observe(1, metric.WithAttributes(attribute.String("probe", "kv")))
+		return nil
+	}))
+	assert.NoError(t, err)
+
+	b := &bytes.Buffer{}
+	assert.NoError(t, m.WritePrometheus(ctx, b))
+
+	want := `# HELP eduseal_seals_total sealed documents
+# TYPE eduseal_seals_total counter
+eduseal_seals_total{organization_id="a\"b"} 2
+# TYPE http_duration histogram
+http_duration_bucket{status="200",le="0.1"} 1
+http_duration_bucket{status="200",le="1"} 2
+http_duration_bucket{status="200",le="+Inf"} 2
+http_duration_sum{status="200"} 0.55
+http_duration_count{status="200"} 2
+# TYPE probe_healthy gauge
+probe_healthy{probe="kv"} 1
+`
+	assert.Equal(t, want, b.String())
+}
+
+func TestWritePrometheusNotScrapeable(t *testing.T) {
+	m, err := NewSimple(context.Background(), "test")
+	assert.NoError(t, err)
+	assert.Error(t, m.WritePrometheus(context.Background(), &bytes.Buffer{}))
+}
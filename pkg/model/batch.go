@@ -0,0 +1,24 @@
+package model
+
+import "eduseal/pkg/pdfinspect"
+
+// Batch is a set of documents submitted for sealing in one request
+type Batch struct {
+	BatchID        string       `json:"batch_id"`
+	OrganizationID string       `json:"organization_id,omitempty"`
+	CreatedAt      int64        `json:"created_at"`
+	Items          []*BatchItem `json:"items"`
+
+	// Summary counts the items per state, it is only set when the batch is read
+	Summary map[TransactionState]int `json:"summary,omitempty"`
+}
+
+// BatchItem is one document in a batch
+type BatchItem struct {
+	Name          string           `json:"name,omitempty"`
+	TransactionID string           `json:"transaction_id"`
+	State         TransactionState `json:"state,omitempty"`
+	Error         string           `json:"error,omitempty"`
+	// Problems are the failed preflight checks of a document that was not queued
+	Problems []pdfinspect.Problem `json:"problems,omitempty"`
+}
@@ -1,10 +1,21 @@
 package model
 
+import (
+	"slices"
+	"time"
+)
+
 // APIServer holds the api server configuration
 type APIServer struct {
 	Addr       string            `yaml:"addr" validate:"required"`
 	PublicKeys map[string]string `yaml:"public_keys"`
 	TLS        TLS               `yaml:"tls" validate:"omitempty"`
+	// TrustedProxies are the addresses or cidrs whose X-Forwarded-For header is used as the client address,
+	// empty means the header is ignored and the peer address is the client address
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	// UploadTimeout is how many seconds a sign, batch sign or validate request may take to upload and answer,
+	// the other routes keep the short server timeouts
+	UploadTimeout int64 `yaml:"upload_timeout"`
 }
 
 // JWTAuth holds the jwt auth configuration
@@ -12,6 +23,33 @@ type JWTAuth struct {
 	Enabled bool              `yaml:"enabled"`
 	Access  map[string]string `yaml:"access"`
 	JWKURL  string            `yaml:"jwk_url"`
+	// Limits maps organization_id to its rate limit and quotas, organizations not in it get DefaultLimits
+	Limits        map[string]TenantLimits `yaml:"limits"`
+	DefaultLimits TenantLimits            `yaml:"default_limits"`
+	// Admins are the organization_ids whose tokens may read every organization's statistics
+	Admins []string `yaml:"admins"`
+}
+
+// IsAdmin reports whether the organization may read every organization's statistics
+func (j *JWTAuth) IsAdmin(organizationID string) bool {
+	return slices.Contains(j.Admins, organizationID)
+}
+
+// LimitsFor returns the rate limit and quotas of the organization
+func (j *JWTAuth) LimitsFor(organizationID string) TenantLimits {
+	if limits, ok := j.Limits[organizationID]; ok {
+		return limits
+	}
+	return j.DefaultLimits
+}
+
+// TenantLimits holds the rate limit and sealing quotas of an organization, zero means unlimited
+type TenantLimits struct {
+	// RequestsPerSecond limits api requests over a sliding one second window
+	RequestsPerSecond int64 `yaml:"requests_per_second"`
+	// DailySeals and MonthlySeals limit the documents sent for sealing per UTC calendar day and month
+	DailySeals   int64 `yaml:"daily_seals"`
+	MonthlySeals int64 `yaml:"monthly_seals"`
 }
 
 // TLS holds the tls configuration
@@ -75,11 +113,159 @@ type PDF struct {
 	KeepUnsignedDuration int `yaml:"keep_unsigned_duration"`
 }
 
+// Webhook holds the webhook delivery configuration
+type Webhook struct {
+	// Secrets maps organization_id to the secret used to sign its deliveries
+	Secrets     map[string]string `yaml:"secrets"`
+	MaxAttempts int               `yaml:"max_attempts"`
+	Timeout     int64             `yaml:"timeout"`
+}
+
+// BatchSign holds the batch signing configuration
+type BatchSign struct {
+	MaxDocuments int `yaml:"max_documents"`
+	// MaxDocumentSize is the largest base64 encoded document, in bytes, it is capped at what fits in the nats server max_payload
+	MaxDocumentSize int `yaml:"max_document_size"`
+	// MaxBodySize is the largest batch sign request, in bytes, all documents together
+	MaxBodySize int64 `yaml:"max_body_size"`
+	// KeepSigned is how many seconds a sealed batch document is cached for the zip download
+	KeepSigned int64 `yaml:"keep_signed"`
+}
+
+// Archive holds the sealed document archive configuration
+type Archive struct {
+	// Enabled stores every sealed document in the database, it requires mongo
+	Enabled bool `yaml:"enabled"`
+}
+
+// Verify holds the public verification endpoint configuration
+type Verify struct {
+	// RateLimit is how many requests a client address may make per minute, zero means 60
+	RateLimit int64 `yaml:"rate_limit"`
+}
+
+// Prometheus holds the prometheus scrape endpoint configuration, it is served on the admin address with an operator token
+type Prometheus struct {
+	// Path serves the metrics in the prometheus text format on the admin address, empty means /metrics/prometheus
+	Path string `yaml:"path"`
+}
+
+// Admin holds the operator api configuration, it listens on its own address and does not accept the jwt of the public api
+type Admin struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"`
+	TLS     TLS    `yaml:"tls"`
+	// Tokens maps an operator name to its bearer token, the name is logged as the principal of the operation.
+	// A token shorter than 32 characters or a known placeholder is refused at start.
+	Tokens map[string]string `yaml:"tokens"`
+	// MaxPause is how many seconds the sealer consumer may be paused, zero means 24 hours
+	MaxPause int64 `yaml:"max_pause"`
+}
+
+// Redelivery holds the redelivery policy of the seal and cache consumers, a message out of attempts moves to the DLQ stream
+type Redelivery struct {
+	// MaxDeliver is how many times a message is delivered, zero means 5
+	MaxDeliver int `yaml:"max_deliver"`
+	// Backoff are the seconds before each redelivery, the last one repeats, empty means 30, 60, 300 and 900
+	Backoff []int64 `yaml:"backoff"`
+	// DLQRetention is how many seconds a DLQ entry is kept, zero means 30 days
+	DLQRetention int64 `yaml:"dlq_retention"`
+}
+
+// Policy returns the max deliveries and the backoff with defaults applied.
+// JetStream needs fewer backoff steps than deliveries, so the backoff is cut to MaxDeliver-1 steps.
+func (r *Redelivery) Policy() (int, []time.Duration) {
+	maxDeliver := r.MaxDeliver
+	if maxDeliver <= 0 {
+		maxDeliver = 5
+	}
+	seconds := r.Backoff
+	if len(seconds) == 0 {
+		seconds = []int64{30, 60, 300, 900}
+	}
+	if len(seconds) > maxDeliver-1 {
+		seconds = seconds[:maxDeliver-1]
+	}
+
+	backoff := make([]time.Duration, len(seconds))
+	for i, n := range seconds {
+		backoff[i] = time.Duration(n) * time.Second
+	}
+	return maxDeliver, backoff
+}
+
+// Retention returns how long a DLQ entry is kept
+func (r *Redelivery) Retention() time.Duration {
+	if r.DLQRetention <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(r.DLQRetention) * time.Second
+}
+
+// Idempotency holds the Idempotency-Key configuration
+type Idempotency struct {
+	// Retention is how many seconds a key maps to its transaction, zero means 24 hours
+	Retention int64 `yaml:"retention"`
+}
+
+// SignatureTemplate is one allowed set of signature metadata, a sign request can only use values from a template
+type SignatureTemplate struct {
+	Reason      string `yaml:"reason"`
+	Location    string `yaml:"location"`
+	Name        string `yaml:"name"`
+	ContactInfo string `yaml:"contact_info"`
+	FieldName   string `yaml:"field_name"`
+}
+
+// SignatureAppearance holds the visible seal stamp configuration
+type SignatureAppearance struct {
+	// Images are the names of the background images in the sealers' image registry
+	Images []string `yaml:"images"`
+	// MaxTextLength limits the stamp text, zero means 500 characters
+	MaxTextLength int `yaml:"max_text_length"`
+}
+
+// Preflight holds the checks a document has to pass before it is queued for sealing
+type Preflight struct {
+	// MaxSize is the largest decoded document, in bytes, zero means 32 MiB.
+	// It is capped at what fits base64 encoded in the nats server max_payload, which defaults to 1 MiB.
+	MaxSize int64 `yaml:"max_size"`
+	// MaxPages zero means 2000 pages
+	MaxPages int `yaml:"max_pages"`
+	// AllowJavaScript accepts documents with embedded JavaScript
+	AllowJavaScript bool `yaml:"allow_javascript"`
+	// RejectSigned rejects documents that are already signed, certified documents that permit no changes are always rejected
+	RejectSigned bool `yaml:"reject_signed"`
+	// RequirePDFA rejects documents without a PDF/A conformance claim
+	RequirePDFA bool `yaml:"require_pdfa"`
+}
+
 // APIGW holds the datastore configuration
 type APIGW struct {
 	APIServer  APIServer `yaml:"api_server" validate:"required"`
 	JWTAuth    JWTAuth   `yaml:"jwt_auth" validate:"required"`
 	ClientCert TLS       `yaml:"client_cert" validate:"required"`
+	Webhook    Webhook   `yaml:"webhook" validate:"omitempty"`
+	Batch      BatchSign `yaml:"batch" validate:"omitempty"`
+
+	Idempotency Idempotency `yaml:"idempotency" validate:"omitempty"`
+
+	Archive Archive `yaml:"archive" validate:"omitempty"`
+
+	Verify Verify `yaml:"verify" validate:"omitempty"`
+
+	Prometheus Prometheus `yaml:"prometheus" validate:"omitempty"`
+
+	Admin Admin `yaml:"admin" validate:"omitempty"`
+
+	Redelivery Redelivery `yaml:"redelivery" validate:"omitempty"`
+
+	// SignatureTemplates maps organization_id to the signature metadata its requests may use
+	SignatureTemplates map[string][]SignatureTemplate `yaml:"signature_templates" validate:"omitempty"`
+
+	SignatureAppearance SignatureAppearance `yaml:"signature_appearance" validate:"omitempty"`
+
+	Preflight Preflight `yaml:"preflight" validate:"omitempty"`
 }
 
 // Sealer holds the sealer configuration
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitsFor(t *testing.T) {
+	jwtAuth := &JWTAuth{
+		Limits: map[string]TenantLimits{
+			"org-a": {RequestsPerSecond: 50, DailySeals: 100},
+		},
+		DefaultLimits: TenantLimits{RequestsPerSecond: 5},
+	}
+
+	assert.Equal(t, TenantLimits{RequestsPerSecond: 50, DailySeals: 100}, jwtAuth.LimitsFor("org-a"))
+	assert.Equal(t, TenantLimits{RequestsPerSecond: 5}, jwtAuth.LimitsFor("org-b"))
+	assert.Equal(t, TenantLimits{}, (&JWTAuth{}).LimitsFor("org-a"), "no limits configured means unlimited")
+}
+
+func TestRedeliveryPolicy(t *testing.T) {
+	maxDeliver, backoff := (&Redelivery{}).Policy()
+	assert.Equal(t, 5, maxDeliver)
+	assert.Equal(t, []time.Duration{30 * time.Second, time.Minute, 5 * time.Minute, 15 * time.Minute}, backoff)
+
+	maxDeliver, backoff = (&Redelivery{MaxDeliver: 2, Backoff: []int64{10, 20, 30}}).Policy()
+	assert.Equal(t, 2, maxDeliver)
+	assert.Equal(t, []time.Duration{10 * time.Second}, backoff, "jetstream needs fewer backoff steps than deliveries")
+
+	maxDeliver, backoff = (&Redelivery{MaxDeliver: 1}).Policy()
+	assert.Equal(t, 1, maxDeliver)
+	assert.Empty(t, backoff)
+}
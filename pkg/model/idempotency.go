@@ -0,0 +1,9 @@
+package model
+
+// IdempotencyRecord maps an Idempotency-Key to the transaction the first request with that key created
+type IdempotencyRecord struct {
+	TransactionID string `json:"transaction_id"`
+	// RequestHash tells a retry from a different request that reuses the key
+	RequestHash string `json:"request_hash"`
+	CreatedAt   int64  `json:"created_at"`
+}
@@ -8,4 +8,24 @@ type Document struct {
 	Message       string `json:"message,omitempty" bson:"message" redis:"message"`
 	RevokedAt     int64  `json:"revoked_at,omitempty" bson:"revoked_at" redis:"revoke_at"`
 	Reason        string `json:"reason,omitempty" bson:"reason" redis:"reason"`
+	Error         string `json:"error,omitempty" bson:"error,omitempty" redis:"error"`
+
+	// the fields below are only stored in the database
+	OrganizationID    string             `json:"organization_id,omitempty" bson:"organization_id,omitempty" redis:"-"`
+	ExternalReference string             `json:"external_reference,omitempty" bson:"external_reference,omitempty" redis:"-"`
+	Labels            map[string]string  `json:"labels,omitempty" bson:"labels,omitempty" redis:"-"`
+	CreatedAt         int64              `json:"created_at,omitempty" bson:"created_at,omitempty" redis:"-"`
+	SealedAt          int64              `json:"sealed_at,omitempty" bson:"sealed_at,omitempty" redis:"-"`
+	SHA256            string             `json:"sha256,omitempty" bson:"sha256,omitempty" redis:"-"`
+	Validation        *ValidationOutcome `json:"validation,omitempty" bson:"validation,omitempty" redis:"-"`
+	ReasonCode        RevocationReason   `json:"reason_code,omitempty" bson:"reason_code,omitempty" redis:"-"`
+	RevokedBy         string             `json:"revoked_by,omitempty" bson:"revoked_by,omitempty" redis:"-"`
+	RevocationSeq     int64              `json:"revocation_seq,omitempty" bson:"revocation_seq,omitempty" redis:"-"`
+	SuspendedAt       int64              `json:"suspended_at,omitempty" bson:"suspended_at,omitempty" redis:"-"`
+	SuspendReason     string             `json:"suspend_reason,omitempty" bson:"suspend_reason,omitempty" redis:"-"`
+	History           []*DocumentEvent   `json:"history,omitempty" bson:"history,omitempty" redis:"-"`
+	LegalHold         bool               `json:"legal_hold,omitempty" bson:"legal_hold,omitempty" redis:"-"`
+	ErasedAt          int64              `json:"erased_at,omitempty" bson:"erased_at,omitempty" redis:"-"`
+	ErasureReason     string             `json:"erasure_reason,omitempty" bson:"erasure_reason,omitempty" redis:"-"`
+	ErasedBy          string             `json:"erased_by,omitempty" bson:"erased_by,omitempty" redis:"-"`
 }
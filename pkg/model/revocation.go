@@ -0,0 +1,67 @@
+package model
+
+import "slices"
+
+// RevocationReason is the structured reason a sealed document was revoked
+type RevocationReason string
+
+const (
+	// RevocationReasonUnspecified is used when no reason code is given
+	RevocationReasonUnspecified RevocationReason = "unspecified"
+	// RevocationReasonIssuedInError is used when the document should never have been sealed
+	RevocationReasonIssuedInError RevocationReason = "issued_in_error"
+	// RevocationReasonSuperseded is used when a corrected document has been sealed instead
+	RevocationReasonSuperseded RevocationReason = "superseded"
+	// RevocationReasonWithdrawn is used when what the document attests is withdrawn, e.g. a rescinded degree
+	RevocationReasonWithdrawn RevocationReason = "withdrawn"
+	// RevocationReasonFraud is used when the document was obtained by fraud
+	RevocationReasonFraud RevocationReason = "fraud"
+)
+
+// RevocationReasons are the known reason codes
+var RevocationReasons = []RevocationReason{
+	RevocationReasonUnspecified,
+	RevocationReasonIssuedInError,
+	RevocationReasonSuperseded,
+	RevocationReasonWithdrawn,
+	RevocationReasonFraud,
+}
+
+// Valid reports if r is a known reason code
+func (r RevocationReason) Valid() bool {
+	return slices.Contains(RevocationReasons, r)
+}
+
+// Revocation is one entry of the revocation feed, free text reasons and the revoking principal are left out since relying parties outside the organization read it
+type Revocation struct {
+	// Seq orders the feed, it is taken from a counter when the document is revoked
+	Seq           int64            `json:"seq" bson:"revocation_seq"`
+	TransactionID string           `json:"transaction_id" bson:"transaction_id"`
+	RevokedAt     int64            `json:"revoked_at" bson:"revoked_at"`
+	ReasonCode    RevocationReason `json:"reason_code" bson:"reason_code"`
+}
+
+// DocumentAction is a change of a sealed document's standing
+type DocumentAction string
+
+const (
+	// DocumentActionRevoked the document is permanently revoked
+	DocumentActionRevoked DocumentAction = "revoked"
+	// DocumentActionSuspended the document is held while e.g. an investigation runs
+	DocumentActionSuspended DocumentAction = "suspended"
+	// DocumentActionReinstated the suspension is lifted
+	DocumentActionReinstated DocumentAction = "reinstated"
+	// DocumentActionLegalHoldPlaced the document may not be erased
+	DocumentActionLegalHoldPlaced DocumentAction = "legal_hold_placed"
+	// DocumentActionLegalHoldReleased the document may be erased again
+	DocumentActionLegalHoldReleased DocumentAction = "legal_hold_released"
+)
+
+// DocumentEvent is one entry of a sealed document's history
+type DocumentEvent struct {
+	Action     DocumentAction   `json:"action" bson:"action"`
+	ReasonCode RevocationReason `json:"reason_code,omitempty" bson:"reason_code,omitempty"`
+	Reason     string           `json:"reason,omitempty" bson:"reason,omitempty"`
+	By         string           `json:"by,omitempty" bson:"by,omitempty"`
+	TS         int64            `json:"ts" bson:"ts"`
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// StatsOperation is an operation counted in the usage statistics
+type StatsOperation string
+
+const (
+	// StatsOperationSignings counts documents queued for sealing
+	StatsOperationSignings StatsOperation = "signings"
+	// StatsOperationFetches counts sealed documents fetched
+	StatsOperationFetches StatsOperation = "fetches"
+	// StatsOperationValidations counts validated documents
+	StatsOperationValidations StatsOperation = "validations"
+)
+
+// StatsGranularity is the length of a usage statistics bucket, buckets are aligned to UTC
+type StatsGranularity string
+
+const (
+	// StatsGranularityHour buckets are one hour long
+	StatsGranularityHour StatsGranularity = "hour"
+	// StatsGranularityDay buckets are one UTC calendar day long
+	StatsGranularityDay StatsGranularity = "day"
+)
+
+// StatsGranularities are the known granularities
+var StatsGranularities = []StatsGranularity{StatsGranularityHour, StatsGranularityDay}
+
+// Valid reports if g is a known granularity
+func (g StatsGranularity) Valid() bool {
+	return slices.Contains(StatsGranularities, g)
+}
+
+// Truncate returns the start of the bucket containing t
+func (g StatsGranularity) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	if g == StatsGranularityDay {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+// Next returns the start of the bucket after the one starting at start
+func (g StatsGranularity) Next(start time.Time) time.Time {
+	if g == StatsGranularityDay {
+		return start.AddDate(0, 0, 1)
+	}
+	return start.Add(time.Hour)
+}
+
+// BucketCount returns how many buckets overlap [from, to), without building them like BucketStarts.
+// UTC has no daylight saving, so every bucket of a granularity has the same length.
+func (g StatsGranularity) BucketCount(from, to time.Time) int64 {
+	start := g.Truncate(from)
+	if !start.Before(to) {
+		return 0
+	}
+	step := time.Hour
+	if g == StatsGranularityDay {
+		step = 24 * time.Hour
+	}
+	length := to.Sub(start)
+	n := int64(length / step)
+	if length%step != 0 {
+		n++
+	}
+	return n
+}
+
+// BucketStarts returns the start of every bucket overlapping [from, to)
+func (g StatsGranularity) BucketStarts(from, to time.Time) []time.Time {
+	starts := []time.Time{}
+	for start := g.Truncate(from); start.Before(to); start = g.Next(start) {
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// StatsBucket is the usage of an organization during one bucket
+type StatsBucket struct {
+	// Start is the unix time the bucket starts
+	Start       int64 `json:"start"`
+	Signings    int64 `json:"signings"`
+	Fetches     int64 `json:"fetches"`
+	Validations int64 `json:"validations"`
+}
+
+// Add counts n operations in the bucket
+func (b *StatsBucket) Add(operation StatsOperation, n int64) {
+	switch operation {
+	case StatsOperationSignings:
+		b.Signings += n
+	case StatsOperationFetches:
+		b.Fetches += n
+	case StatsOperationValidations:
+		b.Validations += n
+	}
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsGranularityBucketStarts(t *testing.T) {
+	from := time.Date(2024, 3, 30, 22, 15, 0, 0, time.UTC)
+
+	tts := []struct {
+		name        string
+		granularity StatsGranularity
+		to          time.Time
+		want        []time.Time
+	}{
+		{
+			name:        "hour",
+			granularity: StatsGranularityHour,
+			to:          time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 3, 30, 22, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 30, 23, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:        "day crossing a month",
+			granularity: StatsGranularityDay,
+			to:          time.Date(2024, 4, 1, 0, 0, 1, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:        "empty range",
+			granularity: StatsGranularityHour,
+			to:          time.Date(2024, 3, 30, 22, 0, 0, 0, time.UTC),
+			want:        []time.Time{},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.granularity.BucketStarts(from, tt.to))
+			assert.Equal(t, int64(len(tt.want)), tt.granularity.BucketCount(from, tt.to))
+		})
+	}
+}
+
+func TestStatsGranularityBucketCount(t *testing.T) {
+	to := time.Date(2024, 3, 30, 22, 15, 0, 0, time.UTC)
+	assert.Equal(t, int64(475511), StatsGranularityHour.BucketCount(time.Unix(0, 0), to))
+	assert.Equal(t, int64(19813), StatsGranularityDay.BucketCount(time.Unix(0, 0), to))
+}
+
+func TestStatsBucketAdd(t *testing.T) {
+	bucket := &StatsBucket{}
+	bucket.Add(StatsOperationSignings, 2)
+	bucket.Add(StatsOperationValidations, 1)
+	bucket.Add(StatsOperation("unknown"), 5)
+
+	assert.Equal(t, &StatsBucket{Signings: 2, Validations: 1}, bucket)
+}
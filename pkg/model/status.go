@@ -12,6 +12,15 @@ var (
 	StatusOK = "STATUS_OK_%s"
 	// StatusFail status fail
 	StatusFail = "STATUS_FAIL_%s"
+	// StatusDegraded status degraded, only probes of degraded severity fail
+	StatusDegraded = "STATUS_DEGRADED_%s"
+)
+
+const (
+	// ProbeSeverityCritical a failing probe fails the service, it is the default of a probe without severity
+	ProbeSeverityCritical = "critical"
+	// ProbeSeverityDegraded a failing probe degrades the service, it still serves most requests
+	ProbeSeverityDegraded = "degraded"
 )
 
 // Health contains status for each service
@@ -61,7 +70,7 @@ var (
 	ServiceName string = "undef"
 )
 
-// Check checks the status of each status, return the first that does not pass.
+// Check checks the status of each probe, the status fails when a critical probe fails and is degraded when only degraded probes fail
 func (probes Probes) Check(serviceName string) *v1_status.StatusReply {
 	health := &v1_status.StatusReply{
 		Data: &v1_status.StatusReply_Data{
@@ -85,12 +94,33 @@ func (probes Probes) Check(serviceName string) *v1_status.StatusReply {
 		return health
 	}
 
+	failed, degraded := false, false
 	for _, probe := range probes {
 		if !probe.Healthy {
-			health.Data.Status = fmt.Sprintf(StatusFail, serviceName)
+			if probe.GetSeverity() == ProbeSeverityDegraded {
+				degraded = true
+			} else {
+				failed = true
+			}
 		}
 		health.Data.Probes = append(health.Data.Probes, probe)
 	}
+	switch {
+	case failed:
+		health.Data.Status = fmt.Sprintf(StatusFail, serviceName)
+	case degraded:
+		health.Data.Status = fmt.Sprintf(StatusDegraded, serviceName)
+	}
 
 	return health
 }
+
+// Ready reports whether every critical probe passes
+func (probes Probes) Ready() bool {
+	for _, probe := range probes {
+		if !probe.Healthy && probe.GetSeverity() != ProbeSeverityDegraded {
+			return false
+		}
+	}
+	return true
+}
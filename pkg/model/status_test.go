@@ -0,0 +1,51 @@
+package model
+
+import (
+	"eduseal/internal/gen/status/v1_status"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbesCheck(t *testing.T) {
+	tts := []struct {
+		name   string
+		probes Probes
+		status string
+		ready  bool
+	}{
+		{
+			name:   "healthy",
+			probes: Probes{{Name: "kv", Healthy: true}, {Name: "validators", Healthy: true, Severity: ProbeSeverityDegraded}},
+			status: "STATUS_OK_apigw",
+			ready:  true,
+		},
+		{
+			name:   "degraded",
+			probes: Probes{{Name: "kv", Healthy: true}, {Name: "validators", Healthy: false, Severity: ProbeSeverityDegraded}},
+			status: "STATUS_DEGRADED_apigw",
+			ready:  true,
+		},
+		{
+			name:   "critical without severity",
+			probes: Probes{{Name: "kv", Healthy: false}, {Name: "validators", Healthy: false, Severity: ProbeSeverityDegraded}},
+			status: "STATUS_FAIL_apigw",
+			ready:  false,
+		},
+		{
+			name:   "critical",
+			probes: Probes{{Name: "nats", Healthy: false, Severity: ProbeSeverityCritical}},
+			status: "STATUS_FAIL_apigw",
+			ready:  false,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			health := tt.probes.Check("apigw")
+			assert.Equal(t, tt.status, health.Data.Status)
+			assert.Equal(t, []*v1_status.StatusProbe(tt.probes), health.Data.Probes)
+			assert.Equal(t, tt.ready, tt.probes.Ready())
+		})
+	}
+}
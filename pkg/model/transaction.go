@@ -0,0 +1,91 @@
+package model
+
+import "maps"
+
+// TransactionState is the lifecycle state of a sealing transaction
+type TransactionState string
+
+const (
+	// TransactionStateQueued the document is published to the SEAL stream
+	TransactionStateQueued TransactionState = "queued"
+	// TransactionStateSealing a sealer has picked up the document
+	TransactionStateSealing TransactionState = "sealing"
+	// TransactionStateSealed the sealed document is cached and can be fetched
+	TransactionStateSealed TransactionState = "sealed"
+	// TransactionStateFailed the sealer replied with an error
+	TransactionStateFailed TransactionState = "failed"
+	// TransactionStateExpired the sealed document is no longer cached
+	TransactionStateExpired TransactionState = "expired"
+	// TransactionStateSuspended the sealed document is held until it is reinstated, back to sealed, or revoked
+	TransactionStateSuspended TransactionState = "suspended"
+	// TransactionStateRevoked the sealed document is revoked
+	TransactionStateRevoked TransactionState = "revoked"
+)
+
+// transactionStateRank orders the states, a transition to a lower rank does not change the current state.
+// It protects against events arriving out of order, e.g. the sealing notification after the CACHE reply.
+// Moving back, e.g. reinstating a suspended document, is an explicit transition from the expected state.
+var transactionStateRank = map[TransactionState]int{
+	TransactionStateQueued:    1,
+	TransactionStateSealing:   2,
+	TransactionStateSealed:    3,
+	TransactionStateFailed:    3,
+	TransactionStateExpired:   4,
+	TransactionStateSuspended: 5,
+	TransactionStateRevoked:   6,
+}
+
+// TransactionStateRanks returns the rank of every known state
+func TransactionStateRanks() map[TransactionState]int {
+	return maps.Clone(transactionStateRank)
+}
+
+// Rank returns the order of the state in the lifecycle, zero for unknown states
+func (s TransactionState) Rank() int {
+	return transactionStateRank[s]
+}
+
+// TransactionTransition is one state change of a transaction
+type TransactionTransition struct {
+	State TransactionState `json:"state"`
+	TS    int64            `json:"ts"`
+}
+
+// TransactionStatus is the lifecycle status of a sealing transaction
+type TransactionStatus struct {
+	TransactionID string                   `json:"transaction_id"`
+	State         TransactionState         `json:"state"`
+	SealerBackend string                   `json:"sealer_backend,omitempty"`
+	Error         string                   `json:"error,omitempty"`
+	Transitions   []*TransactionTransition `json:"transitions"`
+	// Validation is the outcome of the latest validation of the sealed document, if any
+	Validation *ValidationOutcome `json:"validation,omitempty"`
+}
+
+// ValidationOutcome is the result of validating a sealed document by its transaction id
+type ValidationOutcome struct {
+	ValidatedAt       int64  `json:"validated_at" bson:"validated_at"`
+	ValidationBackend string `json:"validation_backend" bson:"validation_backend"`
+	IntactSignature   bool   `json:"intact_signature" bson:"intact_signature"`
+	ValidSignature    bool   `json:"valid_signature" bson:"valid_signature"`
+	Error             string `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+// TransactionMeta is the context of a transaction given when it was created
+type TransactionMeta struct {
+	OrganizationID string `redis:"organization_id"`
+	CallbackURL    string `redis:"callback_url"`
+	BatchID        string `redis:"batch_id"`
+	// KeepSigned overrides how many seconds the signed document is cached, zero means the default
+	KeepSigned        int64  `redis:"keep_signed"`
+	ExternalReference string `redis:"external_reference"`
+	// QueuedAt is when the document was published to the SEAL stream, in unix milliseconds
+	QueuedAt int64 `redis:"queued_at"`
+	// IdempotencyKey is the Idempotency-Key of the sign request, it is removed with the transaction when it is erased
+	IdempotencyKey string `redis:"idempotency_key"`
+	// Labels are only stored in the database
+	Labels map[string]string `redis:"-"`
+}
+
+// TransactionMetaFields are the kv fields of TransactionMeta
+var TransactionMetaFields = []string{"organization_id", "callback_url", "batch_id", "keep_signed", "external_reference", "queued_at", "idempotency_key"}
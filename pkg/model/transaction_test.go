@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionStateRank(t *testing.T) {
+	tts := []struct {
+		name  string
+		have  TransactionState
+		after TransactionState
+	}{
+		{name: "sealing after queued", have: TransactionStateSealing, after: TransactionStateQueued},
+		{name: "sealed after sealing", have: TransactionStateSealed, after: TransactionStateSealing},
+		{name: "failed after sealing", have: TransactionStateFailed, after: TransactionStateSealing},
+		{name: "expired after sealed", have: TransactionStateExpired, after: TransactionStateSealed},
+		{name: "suspended after expired", have: TransactionStateSuspended, after: TransactionStateExpired},
+		{name: "revoked after suspended", have: TransactionStateRevoked, after: TransactionStateSuspended},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Greater(t, tt.have.Rank(), tt.after.Rank())
+		})
+	}
+
+	assert.Equal(t, 0, TransactionState("unknown").Rank())
+}
@@ -0,0 +1,28 @@
+package model
+
+// WebhookEvent is the notification posted to a transaction's callback_url
+type WebhookEvent struct {
+	TransactionID string           `json:"transaction_id"`
+	Event         TransactionState `json:"event"`
+	SealerBackend string           `json:"sealer_backend,omitempty"`
+	Error         string           `json:"error,omitempty"`
+	TS            int64            `json:"ts"`
+}
+
+// WebhookDelivery is the message on the WEBHOOK stream, it carries everything needed to deliver the event
+type WebhookDelivery struct {
+	CallbackURL    string        `json:"callback_url"`
+	OrganizationID string        `json:"organization_id"`
+	Event          *WebhookEvent `json:"event"`
+}
+
+// WebhookAttempt is the outcome of one delivery attempt
+type WebhookAttempt struct {
+	Event      TransactionState `json:"event"`
+	Attempt    uint64           `json:"attempt"`
+	StatusCode int              `json:"status_code,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	Delivered  bool             `json:"delivered"`
+	Final      bool             `json:"final"`
+	TS         int64            `json:"ts"`
+}
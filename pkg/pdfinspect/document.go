@@ -0,0 +1,263 @@
+// Package pdfinspect reads the structure of a pdf without rendering it, it is used to check documents before they are sealed.
+package pdfinspect
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+var (
+	// ErrNotPDF is returned when the data has no pdf header
+	ErrNotPDF = errors.New("not a pdf document")
+	// ErrNoObjects is returned when the data has a pdf header but no object could be read
+	ErrNoObjects = errors.New("pdf document has no readable objects")
+	// ErrNoCatalog is returned when the document catalog can not be found
+	ErrNoCatalog = errors.New("pdf document catalog not found")
+	// ErrEncrypted is returned when the needed objects can not be read because the document is encrypted
+	ErrEncrypted = errors.New("pdf document is encrypted")
+)
+
+const (
+	// headerSearch is how far into the file the %PDF- header may start
+	headerSearch = 1024
+	// maxResolve limits reference chains
+	maxResolve = 32
+	// maxObjectStreamSize limits the decoded size of one object stream
+	maxObjectStreamSize = 64 << 20
+)
+
+var objectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// Document is a parsed pdf, the newest definition of every object is kept
+type Document struct {
+	// Version is the version from the header, e.g. "1.7"
+	Version string
+
+	data    []byte
+	objects map[int]any
+	trailer Dict
+}
+
+// Parse reads every object in data, including objects in object streams.
+// Objects are found by scanning the file rather than through the xref table, so a damaged xref does not hide them.
+func Parse(data []byte) (*Document, error) {
+	header := bytes.Index(data[:min(len(data), headerSearch)], []byte("%PDF-"))
+	if header < 0 {
+		return nil, ErrNotPDF
+	}
+
+	d := &Document{
+		data:    data,
+		objects: map[int]any{},
+		trailer: Dict{},
+	}
+
+	l := &lexer{data: data, pos: header + len("%PDF-")}
+	d.Version = string(l.regular())
+
+	d.scan()
+
+	if len(d.objects) == 0 {
+		return nil, ErrNoObjects
+	}
+
+	return d, nil
+}
+
+// scan reads the objects and trailers in file order, so objects of later incremental updates replace earlier ones
+func (d *Document) scan() {
+	trailerKeyword := []byte("trailer")
+	nextTrailer := func(from int) int {
+		if i := bytes.Index(d.data[from:], trailerKeyword); i >= 0 {
+			return from + i
+		}
+		return len(d.data)
+	}
+	trailerAt := nextTrailer(0)
+
+	pos := 0
+	for pos < len(d.data) {
+		if trailerAt < pos {
+			trailerAt = nextTrailer(pos)
+		}
+
+		loc := objectHeader.FindSubmatchIndex(d.data[pos:])
+
+		if trailerAt < len(d.data) && (loc == nil || trailerAt < pos+loc[0]) {
+			l := &lexer{data: d.data, pos: trailerAt + len(trailerKeyword)}
+			if trailer, err := l.object(0); err == nil {
+				if dict, ok := trailer.(Dict); ok {
+					d.mergeTrailer(dict)
+				}
+			}
+			pos = max(l.pos, trailerAt+1)
+			continue
+		}
+		if loc == nil {
+			return
+		}
+
+		num, _ := strconv.Atoi(string(d.data[pos+loc[2] : pos+loc[3]]))
+		l := &lexer{data: d.data, pos: pos + loc[1]}
+		value, err := d.indirectObject(l)
+		if err != nil {
+			pos += loc[1]
+			continue
+		}
+		pos = l.pos
+
+		d.objects[num] = value
+		if stream, ok := value.(*Stream); ok {
+			switch stream.Dict["Type"] {
+			case Name("XRef"):
+				d.mergeTrailer(stream.Dict)
+			case Name("ObjStm"):
+				d.readObjectStream(stream)
+			}
+		}
+	}
+}
+
+// mergeTrailer keeps the newest trailer entries, an incremental update repeats the entries it still needs
+func (d *Document) mergeTrailer(trailer Dict) {
+	for _, key := range []Name{"Root", "Encrypt", "Info", "ID", "Size", "Prev"} {
+		if value, ok := trailer[key]; ok {
+			d.trailer[key] = value
+		}
+	}
+}
+
+// indirectObject parses the value after "N G obj", including the stream data of a stream object
+func (d *Document) indirectObject(l *lexer) (any, error) {
+	value, err := l.object(0)
+	if err != nil {
+		return nil, err
+	}
+
+	dict, ok := value.(Dict)
+	if !ok {
+		return value, nil
+	}
+
+	l.skipWhitespace()
+	if !bytes.HasPrefix(l.data[l.pos:], []byte("stream")) {
+		return dict, nil
+	}
+	l.pos += len("stream")
+
+	raw, err := l.streamData(dict)
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{Dict: dict, Raw: raw}, nil
+}
+
+// readObjectStream adds the objects of a compressed object stream, streams that can not be decoded are ignored
+func (d *Document) readObjectStream(stream *Stream) {
+	data, err := d.decode(stream)
+	if err != nil {
+		return
+	}
+
+	n, _ := stream.Dict["N"].(float64)
+	first, _ := stream.Dict["First"].(float64)
+	if n <= 0 || first <= 0 || int(first) > len(data) {
+		return
+	}
+
+	header := &lexer{data: data[:int(first)]}
+	for i := 0; i < int(n); i++ {
+		num, err := header.object(0)
+		if err != nil {
+			return
+		}
+		offset, err := header.object(0)
+		if err != nil {
+			return
+		}
+		objNum, ok1 := num.(float64)
+		objOffset, ok2 := offset.(float64)
+		if !ok1 || !ok2 || int(first)+int(objOffset) >= len(data) {
+			return
+		}
+
+		l := &lexer{data: data, pos: int(first) + int(objOffset)}
+		value, err := l.object(0)
+		if err != nil {
+			continue
+		}
+		d.objects[int(objNum)] = value
+	}
+}
+
+// decode returns the decoded stream data, only FlateDecode without predictors is supported
+func (d *Document) decode(stream *Stream) ([]byte, error) {
+	filter := d.Resolve(stream.Dict["Filter"])
+	if a, ok := filter.(Array); ok && len(a) == 1 {
+		filter = d.Resolve(a[0])
+	}
+
+	switch filter {
+	case nil:
+		return stream.Raw, nil
+	case Name("FlateDecode"):
+		if _, ok := stream.Dict["DecodeParms"]; ok {
+			return nil, errors.New("unsupported stream predictor")
+		}
+		r, err := zlib.NewReader(bytes.NewReader(stream.Raw))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		data, err := io.ReadAll(io.LimitReader(r, maxObjectStreamSize))
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	return nil, errors.New("unsupported stream filter")
+}
+
+// Resolve follows references until a direct object is reached, a missing object resolves to nil
+func (d *Document) Resolve(value any) any {
+	for i := 0; i < maxResolve; i++ {
+		ref, ok := value.(Ref)
+		if !ok {
+			return value
+		}
+		value = d.objects[ref.Num]
+	}
+	return nil
+}
+
+// Trailer returns the merged trailer dictionary
+func (d *Document) Trailer() Dict {
+	return d.trailer
+}
+
+// Encrypted reports if the document has an encryption dictionary
+func (d *Document) Encrypted() bool {
+	_, ok := d.trailer["Encrypt"]
+	return ok
+}
+
+// Catalog returns the document catalog, from the trailer or by looking for an object of type Catalog
+func (d *Document) Catalog() (Dict, error) {
+	if catalog, ok := d.Resolve(d.trailer["Root"]).(Dict); ok {
+		return catalog, nil
+	}
+	for _, object := range d.objects {
+		if catalog, ok := object.(Dict); ok && catalog["Type"] == Name("Catalog") {
+			return catalog, nil
+		}
+	}
+	if d.Encrypted() {
+		return nil, ErrEncrypted
+	}
+	return nil, ErrNoCatalog
+}
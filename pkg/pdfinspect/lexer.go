@@ -0,0 +1,299 @@
+package pdfinspect
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strconv"
+)
+
+// Name is a pdf name object, without the leading slash
+type Name string
+
+// Ref is an indirect reference, "12 0 R"
+type Ref struct {
+	Num int
+	Gen int
+}
+
+// Dict is a pdf dictionary
+type Dict map[Name]any
+
+// Array is a pdf array
+type Array []any
+
+// String is a literal or hex string, the bytes are not decrypted
+type String []byte
+
+// Stream is a stream object, Raw is the still encoded stream data
+type Stream struct {
+	Dict Dict
+	Raw  []byte
+}
+
+// keyword is a bare token that is not a value, e.g. obj, endobj or stream
+type keyword string
+
+var (
+	errUnexpectedEOF = errors.New("unexpected end of data")
+	errSyntax        = errors.New("syntax error")
+)
+
+// maxNesting protects against deeply nested arrays and dictionaries
+const maxNesting = 64
+
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+func isWhitespace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *lexer) skipWhitespace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isWhitespace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// regular reads a run of regular characters, i.e. a number or a keyword
+func (l *lexer) regular() []byte {
+	start := l.pos
+	for l.pos < len(l.data) && !isWhitespace(l.data[l.pos]) && !isDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	return l.data[start:l.pos]
+}
+
+// object parses the next object, an integer followed by "<gen> R" is returned as a Ref
+func (l *lexer) object(depth int) (any, error) {
+	if depth > maxNesting {
+		return nil, errSyntax
+	}
+
+	l.skipWhitespace()
+	if l.pos >= len(l.data) {
+		return nil, errUnexpectedEOF
+	}
+
+	switch c := l.data[l.pos]; {
+	case c == '/':
+		l.pos++
+		return l.name(), nil
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+		l.pos += 2
+		return l.dict(depth)
+	case c == '<':
+		l.pos++
+		return l.hexString()
+	case c == '(':
+		l.pos++
+		return l.literalString()
+	case c == '[':
+		l.pos++
+		return l.array(depth)
+	case c == ']' || c == '>' || c == ')' || c == '{' || c == '}':
+		l.pos++
+		return keyword(c), nil
+	}
+
+	token := l.regular()
+	if len(token) == 0 {
+		l.pos++
+		return nil, errSyntax
+	}
+
+	switch string(token) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+
+	if n, err := strconv.ParseInt(string(token), 10, 64); err == nil {
+		if ref, ok := l.ref(n); ok {
+			return ref, nil
+		}
+		return float64(n), nil
+	}
+	if f, err := strconv.ParseFloat(string(token), 64); err == nil {
+		return f, nil
+	}
+
+	return keyword(token), nil
+}
+
+// ref looks ahead for "<gen> R" after the integer num, the position is only moved on a match
+func (l *lexer) ref(num int64) (Ref, bool) {
+	start := l.pos
+	l.skipWhitespace()
+	gen, err := strconv.Atoi(string(l.regular()))
+	if err == nil {
+		l.skipWhitespace()
+		if string(l.regular()) == "R" {
+			return Ref{Num: int(num), Gen: gen}, true
+		}
+	}
+	l.pos = start
+	return Ref{}, false
+}
+
+func (l *lexer) name() Name {
+	raw := l.regular()
+	if !bytes.ContainsRune(raw, '#') {
+		return Name(raw)
+	}
+	decoded := make([]byte, 0, len(raw))
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			if b, err := hex.DecodeString(string(raw[i+1 : i+3])); err == nil {
+				decoded = append(decoded, b[0])
+				i += 2
+				continue
+			}
+		}
+		decoded = append(decoded, raw[i])
+	}
+	return Name(decoded)
+}
+
+func (l *lexer) dict(depth int) (Dict, error) {
+	d := Dict{}
+	for {
+		l.skipWhitespace()
+		if l.pos+1 < len(l.data) && l.data[l.pos] == '>' && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return d, nil
+		}
+		key, err := l.object(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(Name)
+		if !ok {
+			return nil, errSyntax
+		}
+		value, err := l.object(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		d[name] = value
+	}
+}
+
+func (l *lexer) array(depth int) (Array, error) {
+	a := Array{}
+	for {
+		l.skipWhitespace()
+		if l.pos < len(l.data) && l.data[l.pos] == ']' {
+			l.pos++
+			return a, nil
+		}
+		value, err := l.object(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := value.(keyword); ok {
+			return nil, errSyntax
+		}
+		a = append(a, value)
+	}
+}
+
+func (l *lexer) hexString() (String, error) {
+	end := bytes.IndexByte(l.data[l.pos:], '>')
+	if end < 0 {
+		return nil, errUnexpectedEOF
+	}
+	digits := make([]byte, 0, end)
+	for _, c := range l.data[l.pos : l.pos+end] {
+		if !isWhitespace(c) {
+			digits = append(digits, c)
+		}
+	}
+	l.pos += end + 1
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	s, err := hex.DecodeString(string(digits))
+	if err != nil {
+		return nil, errSyntax
+	}
+	return s, nil
+}
+
+// literalString returns the string with escapes kept, callers only compare or search the raw bytes
+func (l *lexer) literalString() (String, error) {
+	start := l.pos
+	nesting := 1
+	for l.pos < len(l.data) {
+		switch l.data[l.pos] {
+		case '\\':
+			l.pos++
+		case '(':
+			nesting++
+		case ')':
+			nesting--
+			if nesting == 0 {
+				s := l.data[start:l.pos]
+				l.pos++
+				return s, nil
+			}
+		}
+		l.pos++
+	}
+	return nil, errUnexpectedEOF
+}
+
+// streamData reads the data following the "stream" keyword, the length is taken from the dictionary
+// when it is a direct integer that ends at "endstream", else the data runs to the next "endstream".
+func (l *lexer) streamData(d Dict) ([]byte, error) {
+	if l.pos < len(l.data) && l.data[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+		l.pos++
+	}
+	start := l.pos
+
+	if length, ok := d["Length"].(float64); ok && length >= 0 && start+int(length) <= len(l.data) {
+		end := start + int(length)
+		rest := bytes.TrimLeft(l.data[end:min(end+16, len(l.data))], "\r\n \t")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			l.pos = end
+			return l.data[start:end], nil
+		}
+	}
+
+	end := bytes.Index(l.data[start:], []byte("endstream"))
+	if end < 0 {
+		return nil, errUnexpectedEOF
+	}
+	l.pos = start + end
+	return bytes.TrimRight(l.data[start:start+end], "\r\n"), nil
+}
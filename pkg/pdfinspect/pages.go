@@ -0,0 +1,181 @@
+package pdfinspect
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNoPages is returned when the page tree is missing or empty
+	ErrNoPages = errors.New("pdf document has no pages")
+
+	errPageTree = errors.New("pdf page tree is cyclic or too deep")
+)
+
+// maxPageTreeDepth protects against cyclic or absurdly deep page trees
+const maxPageTreeDepth = 64
+
+// Rect is a rectangle in pdf user space units, 1/72 inch, normalized so LLX <= URX and LLY <= URY
+type Rect struct {
+	LLX float64 `json:"llx"`
+	LLY float64 `json:"lly"`
+	URX float64 `json:"urx"`
+	URY float64 `json:"ury"`
+}
+
+// Width of the rectangle
+func (r Rect) Width() float64 {
+	return r.URX - r.LLX
+}
+
+// Height of the rectangle
+func (r Rect) Height() float64 {
+	return r.URY - r.LLY
+}
+
+// Contains reports if o lies completely inside r
+func (r Rect) Contains(o Rect) bool {
+	return o.LLX >= r.LLX && o.LLY >= r.LLY && o.URX <= r.URX && o.URY <= r.URY
+}
+
+// Intersect returns the part of r that also lies in o
+func (r Rect) Intersect(o Rect) Rect {
+	i := Rect{
+		LLX: max(r.LLX, o.LLX),
+		LLY: max(r.LLY, o.LLY),
+		URX: min(r.URX, o.URX),
+		URY: min(r.URY, o.URY),
+	}
+	if i.URX < i.LLX || i.URY < i.LLY {
+		return Rect{}
+	}
+	return i
+}
+
+// Page is one page of the document, inherited attributes are already applied
+type Page struct {
+	// Number starts at 1
+	Number   int
+	MediaBox Rect
+	// CropBox is the MediaBox when the page has no crop box
+	CropBox Rect
+	Rotate  int
+}
+
+// Bounds is the visible area of the page, the crop box clipped to the media box
+func (p *Page) Bounds() Rect {
+	return p.CropBox.Intersect(p.MediaBox)
+}
+
+// pageAttributes are the inheritable page attributes
+type pageAttributes struct {
+	mediaBox *Rect
+	cropBox  *Rect
+	rotate   int
+}
+
+// Pages returns the pages in document order
+func (d *Document) Pages() ([]*Page, error) {
+	catalog, err := d.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := d.Resolve(catalog["Pages"]).(Dict)
+	if !ok {
+		if d.Encrypted() {
+			return nil, ErrEncrypted
+		}
+		return nil, ErrNoPages
+	}
+
+	pages := []*Page{}
+	visited := map[int]bool{}
+	if err := d.walkPages(root, pageAttributes{}, visited, 0, &pages); err != nil {
+		return nil, err
+	}
+	if len(pages) == 0 {
+		return nil, ErrNoPages
+	}
+
+	return pages, nil
+}
+
+// walkPages adds the pages below node, visited holds the object numbers of the nodes already walked
+func (d *Document) walkPages(node Dict, inherited pageAttributes, visited map[int]bool, depth int, pages *[]*Page) error {
+	if depth > maxPageTreeDepth {
+		return errPageTree
+	}
+
+	attributes := inherited
+	if r, ok := d.rect(node["MediaBox"]); ok {
+		attributes.mediaBox = &r
+	}
+	if r, ok := d.rect(node["CropBox"]); ok {
+		attributes.cropBox = &r
+	}
+	if rotate, ok := d.Resolve(node["Rotate"]).(float64); ok {
+		attributes.rotate = int(rotate)
+	}
+
+	kids, isTree := d.Resolve(node["Kids"]).(Array)
+	if node["Type"] == Name("Page") || !isTree {
+		page := &Page{
+			Number: len(*pages) + 1,
+			Rotate: attributes.rotate,
+		}
+		if attributes.mediaBox != nil {
+			page.MediaBox = *attributes.mediaBox
+		} else {
+			// US Letter is the default when no page box is given
+			page.MediaBox = Rect{URX: 612, URY: 792}
+		}
+		page.CropBox = page.MediaBox
+		if attributes.cropBox != nil {
+			page.CropBox = *attributes.cropBox
+		}
+		*pages = append(*pages, page)
+		return nil
+	}
+
+	for _, kid := range kids {
+		if ref, ok := kid.(Ref); ok {
+			if visited[ref.Num] {
+				return errPageTree
+			}
+			visited[ref.Num] = true
+		}
+		child, ok := d.Resolve(kid).(Dict)
+		if !ok {
+			continue
+		}
+		if err := d.walkPages(child, attributes, visited, depth+1, pages); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rect reads a rectangle array, the corners are normalized
+func (d *Document) rect(value any) (Rect, bool) {
+	a, ok := d.Resolve(value).(Array)
+	if !ok || len(a) != 4 {
+		return Rect{}, false
+	}
+
+	n := [4]float64{}
+	for i, v := range a {
+		f, ok := d.Resolve(v).(float64)
+		if !ok {
+			return Rect{}, false
+		}
+		n[i] = f
+	}
+
+	return Rect{
+		LLX: min(n[0], n[2]),
+		LLY: min(n[1], n[3]),
+		URX: max(n[0], n[2]),
+		URY: max(n[1], n[3]),
+	}, true
+}
@@ -0,0 +1,111 @@
+package pdfinspect
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// buildPDF writes a minimal pdf with the given objects, numbered from 1, and a classic trailer
+func buildPDF(objects ...string) []byte {
+	b := &bytes.Buffer{}
+	b.WriteString("%PDF-1.7\n")
+	for i, object := range objects {
+		fmt.Fprintf(b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	fmt.Fprintf(b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n0\n%%%%EOF\n", len(objects)+1)
+	return b.Bytes()
+}
+
+func TestPages(t *testing.T) {
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /MediaBox [0 0 595 842] >>",
+		"<< /Type /Page /Parent 2 0 R /Rotate 90 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [612 792 0 0] /CropBox [10 10 600 (skipped) ] >>",
+	)
+
+	doc, err := Parse(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.7", doc.Version)
+
+	pages, err := doc.Pages()
+	assert.NoError(t, err)
+	assert.Len(t, pages, 2)
+
+	assert.Equal(t, 1, pages[0].Number)
+	assert.Equal(t, Rect{URX: 595, URY: 842}, pages[0].MediaBox)
+	assert.Equal(t, 90, pages[0].Rotate)
+
+	assert.Equal(t, 2, pages[1].Number)
+	assert.Equal(t, Rect{URX: 612, URY: 792}, pages[1].MediaBox, "corners are normalized")
+	assert.Equal(t, pages[1].MediaBox, pages[1].Bounds(), "an invalid crop box is ignored")
+}
+
+func TestPagesObjectStream(t *testing.T) {
+	pagesObject := "<< /Type /Pages /Kids [4 0 R] /Count 1 >> "
+	objects := pagesObject + "<< /Type /Page /Parent 3 0 R /MediaBox [0 0 200 100] /CropBox [0 0 300 50] >>"
+	header := fmt.Sprintf("3 0 4 %d ", len(pagesObject))
+	z := &bytes.Buffer{}
+	w := zlib.NewWriter(z)
+	_, _ = w.Write([]byte(header + objects))
+	_ = w.Close()
+
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 3 0 R >>",
+		fmt.Sprintf("<< /Type /ObjStm /N 2 /First %d /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream", len(header), z.Len(), z.String()),
+	)
+
+	doc, err := Parse(data)
+	assert.NoError(t, err)
+
+	pages, err := doc.Pages()
+	assert.NoError(t, err)
+	assert.Len(t, pages, 1)
+	assert.Equal(t, Rect{URX: 200, URY: 50}, pages[0].Bounds())
+}
+
+func TestPagesIncrementalUpdate(t *testing.T) {
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 100 100] >>",
+	)
+	data = append(data, []byte("3 0 obj\n<< /Type /Page /Parent 2 0 R /MediaBox [0 0 400 400] >>\nendobj\ntrailer\n<< /Root 1 0 R /Prev 0 >>\n%%EOF\n")...)
+
+	doc, err := Parse(data)
+	assert.NoError(t, err)
+
+	pages, err := doc.Pages()
+	assert.NoError(t, err)
+	assert.Equal(t, Rect{URX: 400, URY: 400}, pages[0].MediaBox)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse([]byte("hello world"))
+	assert.ErrorIs(t, err, ErrNotPDF)
+
+	doc, err := Parse(buildPDF("<< /Type /Catalog >>"))
+	assert.NoError(t, err)
+	_, err = doc.Pages()
+	assert.ErrorIs(t, err, ErrNoPages)
+
+	doc, err = Parse(buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [2 0 R] >>",
+	))
+	assert.NoError(t, err)
+	_, err = doc.Pages()
+	assert.Error(t, err, "cyclic page tree")
+}
+
+func TestRectContains(t *testing.T) {
+	page := Rect{URX: 595, URY: 842}
+	assert.True(t, page.Contains(Rect{LLX: 10, LLY: 10, URX: 200, URY: 60}))
+	assert.True(t, page.Contains(page))
+	assert.False(t, page.Contains(Rect{LLX: 500, LLY: 10, URX: 600, URY: 60}))
+	assert.False(t, page.Contains(Rect{LLX: -1, LLY: 10, URX: 100, URY: 60}))
+}
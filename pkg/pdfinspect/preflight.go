@@ -0,0 +1,397 @@
+package pdfinspect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// The checks of a preflight, a Problem names the check it failed
+const (
+	CheckSize       = "size"
+	CheckHeader     = "header"
+	CheckStructure  = "structure"
+	CheckXref       = "xref"
+	CheckEncryption = "encryption"
+	CheckPages      = "pages"
+	CheckSignatures = "signatures"
+	CheckPDFA       = "pdfa"
+	CheckJavaScript = "javascript"
+)
+
+const (
+	// eofSearch is how far from the end of the file the %%EOF marker and startxref may be
+	eofSearch = 1024
+	// maxXrefSections limits the /Prev chain of xref sections
+	maxXrefSections = 256
+	// xrefSlack is how far an xref offset may be off, e.g. after line endings were converted, readers search around it as well
+	xrefSlack = 16
+)
+
+var (
+	headerVersion = regexp.MustCompile(`^[12]\.\d$`)
+	// pdfaPart and pdfaConformance match the PDF/A identification in XMP metadata, as attribute or as element
+	pdfaPart        = regexp.MustCompile(`pdfaid:part(?:\s*=\s*["']|>)\s*(\d+)`)
+	pdfaConformance = regexp.MustCompile(`pdfaid:conformance(?:\s*=\s*["']|>)\s*([A-Za-z])`)
+)
+
+// Limits are the rules of a preflight, a zero MaxSize or MaxPages means no limit
+type Limits struct {
+	MaxSize         int64
+	MaxPages        int
+	AllowJavaScript bool
+	RejectSigned    bool
+	RequirePDFA     bool
+}
+
+// Problem is a failed preflight check
+type Problem struct {
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+// Report is the outcome of a preflight, the document is acceptable when it has no problems
+type Report struct {
+	Version    string    `json:"version,omitempty"`
+	Size       int64     `json:"size"`
+	Pages      int       `json:"pages"`
+	Signatures int       `json:"signatures"`
+	Certified  bool      `json:"certified"`
+	PDFA       string    `json:"pdfa,omitempty"`
+	JavaScript bool      `json:"javascript"`
+	Encrypted  bool      `json:"encrypted"`
+	Problems   []Problem `json:"problems,omitempty"`
+
+	doc *Document
+}
+
+// OK reports if every check passed
+func (r *Report) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// Document is the parsed document, nil when data could not be parsed
+func (r *Report) Document() *Document {
+	return r.doc
+}
+
+func (r *Report) problem(check, format string, a ...any) {
+	r.Problems = append(r.Problems, Problem{Check: check, Message: fmt.Sprintf(format, a...)})
+}
+
+// Preflight checks that data is a pdf the sealer can seal, the report lists the problems of every check.
+// The checks that need the parsed document are skipped when the document is too large, can not be parsed or is encrypted.
+func Preflight(data []byte, limits Limits) *Report {
+	r := &Report{Size: int64(len(data))}
+
+	if limits.MaxSize > 0 && r.Size > limits.MaxSize {
+		r.problem(CheckSize, "document is %d bytes, the limit is %d bytes", r.Size, limits.MaxSize)
+		return r
+	}
+
+	doc, err := Parse(data)
+	if err != nil {
+		check := CheckStructure
+		if errors.Is(err, ErrNotPDF) {
+			check = CheckHeader
+		}
+		r.problem(check, "%v", err)
+		return r
+	}
+	r.doc = doc
+	r.Version = doc.Version
+	if !headerVersion.MatchString(doc.Version) {
+		r.problem(CheckHeader, "unknown pdf version %q", doc.Version)
+	}
+
+	if err := doc.checkXref(); err != nil {
+		r.problem(CheckXref, "%v", err)
+	}
+
+	r.Encrypted = doc.Encrypted()
+	if r.Encrypted {
+		r.problem(CheckEncryption, "%v", ErrEncrypted)
+		// the remaining checks would read encrypted strings and streams
+		return r
+	}
+
+	pages, err := doc.Pages()
+	switch {
+	case err != nil:
+		r.problem(CheckPages, "%v", err)
+	case limits.MaxPages > 0 && len(pages) > limits.MaxPages:
+		r.problem(CheckPages, "document has %d pages, the limit is %d pages", len(pages), limits.MaxPages)
+	}
+	r.Pages = len(pages)
+
+	r.Signatures, r.Certified = doc.signatures()
+	switch {
+	case r.Certified:
+		r.problem(CheckSignatures, "document is certified and does not permit changes")
+	case limits.RejectSigned && r.Signatures > 0:
+		r.problem(CheckSignatures, "document already has %d signatures", r.Signatures)
+	}
+
+	r.PDFA = doc.pdfaClaim()
+	if limits.RequirePDFA && r.PDFA == "" {
+		r.problem(CheckPDFA, "document does not claim PDF/A conformance")
+	}
+
+	r.JavaScript = doc.hasJavaScript()
+	if r.JavaScript && !limits.AllowJavaScript {
+		r.problem(CheckJavaScript, "document contains JavaScript")
+	}
+
+	return r
+}
+
+// checkXref follows startxref and the /Prev chain, every section has to be at its offset and every
+// object of a classic xref table has to be at the offset the table gives, both within xrefSlack bytes.
+// The entries of xref streams are not checked, they are usually encoded with a predictor decode does not support.
+func (d *Document) checkXref() error {
+	tail := d.data[max(0, len(d.data)-eofSearch):]
+	if !bytes.Contains(tail, []byte("%%EOF")) {
+		return errors.New("no %%EOF marker at the end of the file")
+	}
+	i := bytes.LastIndex(tail, []byte("startxref"))
+	if i < 0 {
+		return errors.New("no startxref at the end of the file")
+	}
+	l := &lexer{data: tail, pos: i + len("startxref")}
+	offset, err := l.object(0)
+	if err != nil {
+		return errors.New("startxref has no offset")
+	}
+
+	seen := map[int]bool{}
+	for section := 0; ; section++ {
+		o, ok := offset.(float64)
+		if !ok || o < 0 || int(o) >= len(d.data) || o != float64(int(o)) {
+			return fmt.Errorf("xref offset %v is outside the file", offset)
+		}
+		if seen[int(o)] || section >= maxXrefSections {
+			return errors.New("xref sections form a loop")
+		}
+		seen[int(o)] = true
+
+		trailer, err := d.xrefSection(int(o))
+		if err != nil {
+			return err
+		}
+		offset, ok = trailer["Prev"]
+		if !ok {
+			return nil
+		}
+	}
+}
+
+// xrefSection checks the classic xref table or xref stream at offset and returns its trailer dictionary
+func (d *Document) xrefSection(offset int) (Dict, error) {
+	start, ok := d.xrefStart(offset)
+	if !ok {
+		return nil, fmt.Errorf("no xref section at offset %d", offset)
+	}
+	l := &lexer{data: d.data, pos: start}
+
+	if !bytes.HasPrefix(d.data[l.pos:], []byte("xref")) {
+		loc := objectHeader.FindIndex(d.data[l.pos:min(len(d.data), l.pos+32)])
+		l.pos += loc[1]
+		value, err := d.indirectObject(l)
+		if err != nil {
+			return nil, fmt.Errorf("xref stream at offset %d: %w", offset, err)
+		}
+		stream, ok := value.(*Stream)
+		if !ok || stream.Dict["Type"] != Name("XRef") {
+			return nil, fmt.Errorf("object at offset %d is not an xref stream", offset)
+		}
+		return stream.Dict, nil
+	}
+	l.pos += len("xref")
+
+	for {
+		l.skipWhitespace()
+		if bytes.HasPrefix(d.data[l.pos:], []byte("trailer")) {
+			l.pos += len("trailer")
+			value, err := l.object(0)
+			if err != nil {
+				return nil, fmt.Errorf("xref trailer at offset %d: %w", offset, err)
+			}
+			trailer, ok := value.(Dict)
+			if !ok {
+				return nil, fmt.Errorf("xref trailer at offset %d is not a dictionary", offset)
+			}
+			return trailer, nil
+		}
+
+		start, err1 := strconv.Atoi(string(l.regular()))
+		l.skipWhitespace()
+		count, err2 := strconv.Atoi(string(l.regular()))
+		if err1 != nil || err2 != nil || start < 0 || count < 0 {
+			return nil, fmt.Errorf("malformed xref subsection at offset %d", offset)
+		}
+
+		for n := start; n < start+count; n++ {
+			l.skipWhitespace()
+			objOffset, err1 := strconv.Atoi(string(l.regular()))
+			l.skipWhitespace()
+			gen, err2 := strconv.Atoi(string(l.regular()))
+			l.skipWhitespace()
+			kind := string(l.regular())
+			if err1 != nil || err2 != nil || (kind != "n" && kind != "f") {
+				return nil, fmt.Errorf("malformed xref entry for object %d", n)
+			}
+			if kind == "n" && !d.objectAt(objOffset, n, gen) {
+				return nil, fmt.Errorf("xref entry for object %d points to offset %d, the object is not there", n, objOffset)
+			}
+		}
+	}
+}
+
+// xrefStart returns where the xref table or xref stream object at offset starts, whitespace before it is skipped
+func (d *Document) xrefStart(offset int) (int, bool) {
+	for _, start := range nearOffsets(offset, len(d.data)) {
+		l := &lexer{data: d.data, pos: start}
+		l.skipWhitespace()
+		if bytes.HasPrefix(d.data[l.pos:], []byte("xref")) {
+			return l.pos, true
+		}
+		if _, _, ok := d.objectHeaderAt(l.pos); ok {
+			return l.pos, true
+		}
+	}
+	return 0, false
+}
+
+// objectAt reports if the object header "num gen obj" starts at offset
+func (d *Document) objectAt(offset, num, gen int) bool {
+	for _, start := range nearOffsets(offset, len(d.data)) {
+		if n, g, ok := d.objectHeaderAt(start); ok && n == num && g == gen {
+			return true
+		}
+	}
+	return false
+}
+
+// objectHeaderAt returns the number and generation of the object header starting at offset
+func (d *Document) objectHeaderAt(offset int) (int, int, bool) {
+	if offset < 0 || offset >= len(d.data) || (offset > 0 && d.data[offset-1] >= '0' && d.data[offset-1] <= '9') {
+		return 0, 0, false
+	}
+	loc := objectHeader.FindSubmatchIndex(d.data[offset:min(len(d.data), offset+32)])
+	if loc == nil || loc[0] != 0 {
+		return 0, 0, false
+	}
+	num, _ := strconv.Atoi(string(d.data[offset+loc[2] : offset+loc[3]]))
+	gen, _ := strconv.Atoi(string(d.data[offset+loc[4] : offset+loc[5]]))
+	return num, gen, true
+}
+
+// nearOffsets returns offset and the offsets up to xrefSlack bytes around it that are inside the file, nearest first
+func nearOffsets(offset, size int) []int {
+	offsets := make([]int, 0, 2*xrefSlack+1)
+	for delta := 0; delta <= xrefSlack; delta++ {
+		for _, o := range []int{offset - delta, offset + delta} {
+			if o >= 0 && o < size && !slices.Contains(offsets, o) {
+				offsets = append(offsets, o)
+			}
+		}
+	}
+	return offsets
+}
+
+// walk calls fn for every dictionary in the document, including the ones nested in other objects
+func (d *Document) walk(fn func(Dict)) {
+	var visit func(value any)
+	visit = func(value any) {
+		switch v := value.(type) {
+		case Dict:
+			fn(v)
+			for _, child := range v {
+				visit(child)
+			}
+		case Array:
+			for _, child := range v {
+				visit(child)
+			}
+		case *Stream:
+			visit(v.Dict)
+		}
+	}
+	for _, object := range d.objects {
+		visit(object)
+	}
+}
+
+// signatures counts the signature dictionaries and reports if a certification signature forbids any change
+func (d *Document) signatures() (int, bool) {
+	n := 0
+	certified := false
+	d.walk(func(dict Dict) {
+		if _, ok := dict["ByteRange"]; !ok {
+			return
+		}
+		if _, ok := dict["Contents"]; !ok {
+			return
+		}
+		n++
+
+		references, _ := d.Resolve(dict["Reference"]).(Array)
+		for _, reference := range references {
+			ref, ok := d.Resolve(reference).(Dict)
+			if !ok || ref["TransformMethod"] != Name("DocMDP") {
+				continue
+			}
+			params, _ := d.Resolve(ref["TransformParams"]).(Dict)
+			if p, ok := d.Resolve(params["P"]).(float64); ok && p == 1 {
+				certified = true
+			}
+		}
+	})
+	return n, certified
+}
+
+// pdfaClaim returns the PDF/A conformance the document metadata claims, e.g. "PDF/A-2B", or an empty string
+func (d *Document) pdfaClaim() string {
+	catalog, err := d.Catalog()
+	if err != nil {
+		return ""
+	}
+	stream, ok := d.Resolve(catalog["Metadata"]).(*Stream)
+	if !ok {
+		return ""
+	}
+	xmp, err := d.decode(stream)
+	if err != nil {
+		return ""
+	}
+
+	part := pdfaPart.FindSubmatch(xmp)
+	if part == nil {
+		return ""
+	}
+	claim := "PDF/A-" + string(part[1])
+	if conformance := pdfaConformance.FindSubmatch(xmp); conformance != nil {
+		claim += string(bytes.ToUpper(conformance[1]))
+	}
+	return claim
+}
+
+// hasJavaScript reports if any action or name tree entry holds JavaScript
+func (d *Document) hasJavaScript() bool {
+	found := false
+	d.walk(func(dict Dict) {
+		if _, ok := dict["JS"]; ok {
+			found = true
+		}
+		if dict["S"] == Name("JavaScript") {
+			found = true
+		}
+		if _, ok := dict["JavaScript"]; ok {
+			found = true
+		}
+	})
+	return found
+}
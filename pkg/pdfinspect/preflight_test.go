@@ -0,0 +1,165 @@
+package pdfinspect
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// buildPDFXref writes a minimal pdf like buildPDF, with an xref table that points to the objects
+func buildPDFXref(trailer string, objects ...string) []byte {
+	b := &bytes.Buffer{}
+	b.WriteString("%PDF-1.7\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := b.Len()
+	fmt.Fprintf(b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(b, "trailer\n<< /Size %d /Root 1 0 R %s >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, trailer, xref)
+	return b.Bytes()
+}
+
+// problems returns the checks that failed
+func problems(r *Report) []string {
+	checks := []string{}
+	for _, p := range r.Problems {
+		checks = append(checks, p.Check)
+	}
+	return checks
+}
+
+func TestPreflight(t *testing.T) {
+	catalog := "<< /Type /Catalog /Pages 2 0 R >>"
+	pages := "<< /Type /Pages /Kids [3 0 R] /Count 1 >>"
+	page := "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] >>"
+
+	tts := []struct {
+		name   string
+		data   []byte
+		limits Limits
+		want   []string
+	}{
+		{
+			name: "ok",
+			data: buildPDFXref("", catalog, pages, page),
+			want: []string{},
+		},
+		{
+			name:   "too large",
+			data:   buildPDFXref("", catalog, pages, page),
+			limits: Limits{MaxSize: 100},
+			want:   []string{CheckSize},
+		},
+		{
+			name: "not a pdf",
+			data: []byte("hello world"),
+			want: []string{CheckHeader},
+		},
+		{
+			name: "no objects",
+			data: []byte("%PDF-1.7\n%%EOF\n"),
+			want: []string{CheckStructure},
+		},
+		{
+			name: "xref offsets a few bytes off",
+			data: bytes.Replace(buildPDFXref("", catalog, pages, page), []byte("%PDF-1.7\n"), []byte("%PDF-1.7\r\n\r\n"), 1),
+			want: []string{},
+		},
+		{
+			name: "xref points to the wrong offset",
+			data: buildPDF(catalog, pages, page),
+			want: []string{CheckXref},
+		},
+		{
+			name: "truncated",
+			data: func() []byte {
+				data := buildPDFXref("", catalog, pages, page)
+				return data[:len(data)-20]
+			}(),
+			want: []string{CheckXref},
+		},
+		{
+			name: "encrypted",
+			data: buildPDFXref("/Encrypt 4 0 R", catalog, pages, page, "<< /Filter /Standard /V 2 /R 3 >>"),
+			want: []string{CheckEncryption},
+		},
+		{
+			name:   "too many pages",
+			data:   buildPDFXref("", catalog, "<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >>", page, page),
+			limits: Limits{MaxPages: 1},
+			want:   []string{CheckPages},
+		},
+		{
+			name: "no pages",
+			data: buildPDFXref("", "<< /Type /Catalog >>"),
+			want: []string{CheckPages},
+		},
+		{
+			name: "signed",
+			data: buildPDFXref("", "<< /Type /Catalog /Pages 2 0 R /AcroForm << /Fields [4 0 R] >> >>", pages, page,
+				"<< /FT /Sig /V << /Type /Sig /ByteRange [0 10 20 10] /Contents <00> >> >>"),
+			want: []string{},
+		},
+		{
+			name: "signed rejected",
+			data: buildPDFXref("", "<< /Type /Catalog /Pages 2 0 R /AcroForm << /Fields [4 0 R] >> >>", pages, page,
+				"<< /FT /Sig /V << /Type /Sig /ByteRange [0 10 20 10] /Contents <00> >> >>"),
+			limits: Limits{RejectSigned: true},
+			want:   []string{CheckSignatures},
+		},
+		{
+			name: "certified",
+			data: buildPDFXref("", catalog, pages, page,
+				"<< /Type /Sig /ByteRange [0 10 20 10] /Contents <00> /Reference [<< /TransformMethod /DocMDP /TransformParams << /P 1 >> >>] >>"),
+			want: []string{CheckSignatures},
+		},
+		{
+			name: "javascript",
+			data: buildPDFXref("", "<< /Type /Catalog /Pages 2 0 R /OpenAction << /S /JavaScript /JS (app.alert\\(1\\)) >> >>", pages, page),
+			want: []string{CheckJavaScript},
+		},
+		{
+			name:   "javascript allowed",
+			data:   buildPDFXref("", "<< /Type /Catalog /Pages 2 0 R /OpenAction << /S /JavaScript /JS (app.alert\\(1\\)) >> >>", pages, page),
+			limits: Limits{AllowJavaScript: true},
+			want:   []string{},
+		},
+		{
+			name:   "pdfa required",
+			data:   buildPDFXref("", catalog, pages, page),
+			limits: Limits{RequirePDFA: true},
+			want:   []string{CheckPDFA},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Preflight(tt.data, tt.limits)
+			assert.Equal(t, tt.want, problems(r), "%+v", r.Problems)
+			assert.Equal(t, len(tt.want) == 0, r.OK())
+		})
+	}
+}
+
+func TestPreflightPDFA(t *testing.T) {
+	xmp := `<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:Description pdfaid:part="2" pdfaid:conformance="b"/></x:xmpmeta>`
+	data := buildPDFXref("",
+		"<< /Type /Catalog /Pages 2 0 R /Metadata 4 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R >>",
+		fmt.Sprintf("<< /Type /Metadata /Subtype /XML /Length %d >>\nstream\n%s\nendstream", len(xmp), xmp),
+	)
+
+	r := Preflight(data, Limits{RequirePDFA: true})
+	assert.True(t, r.OK(), "%+v", r.Problems)
+	assert.Equal(t, "PDF/A-2B", r.PDFA)
+	assert.Equal(t, 1, r.Pages)
+	assert.NotNil(t, r.Document())
+}
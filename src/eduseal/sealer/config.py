@@ -1,5 +1,5 @@
 from pydantic import BaseModel
-from typing import Optional, List
+from typing import Optional, List, Dict
 import yaml
 import os
 import sys
@@ -36,6 +36,8 @@ class CFG(BaseModel):
     queue: Queue
     pkcs11: PKCS11
     metadata: PdfSignatureMetadata
+    # appearance_images maps an image name, as used in sign requests, to a file on the sealer
+    appearance_images: Dict[str, str] = {}
 
 def parse(log: Logger) -> CFG:
     file_name = os.getenv("EDUSEAL_CONFIG_YAML")
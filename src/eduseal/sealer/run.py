@@ -8,13 +8,17 @@ import json
 from pkcs11 import Session, UserAlreadyLoggedIn
 from pyhanko.sign.pkcs11 import open_pkcs11_session
 from pyhanko.sign import signers
-from pyhanko.sign.fields import SigSeedSubFilter
+from pyhanko.sign.fields import SigSeedSubFilter, SigFieldSpec
+from pyhanko import stamp
+from pyhanko.pdf_utils import images
 from pyhanko.pdf_utils.incremental_writer import IncrementalPdfFileWriter
 from pyhanko.sign.pkcs11 import PKCS11Signer
 from pyhanko.sign.signers.pdf_signer import PdfSigner
 from pyhanko.pdf_utils.crypt.api import PdfKeyNotAvailableError
 from pyhanko.pdf_utils.misc import PdfReadError
 
+from google.protobuf import json_format
+
 from eduseal.sealer.v1_sealer_pb2 import SealRequest, SealReply
 import eduseal.sealer.v1_sealer_pb2_grpc as pb2_grpc
 from eduseal.sealer.config import parse, CFG
@@ -101,12 +105,14 @@ class Sealer(Common, pb2_grpc.SealerServicer):
         self.logger.debug("pkcs11 signer created")
 
         try:
+            # per request metadata is checked against the organization's templates by apigw, empty fields keep the configured value
+            md = in_data.metadata
             signature_meta = signers.PdfSignatureMetadata(
-                field_name="Signature1",
-                location=self.config.metadata.location,
-                reason=self.config.metadata.reason,
-                name=self.config.metadata.name,
-                contact_info=self.config.metadata.contact_info,
+                field_name=md.field_name or "Signature1",
+                location=md.location or self.config.metadata.location,
+                reason=md.reason or self.config.metadata.reason,
+                name=md.name or self.config.metadata.name,
+                contact_info=md.contact_info or self.config.metadata.contact_info,
                 subfilter=SigSeedSubFilter.ADOBE_PKCS7_DETACHED
             )
         except Exception as _e:
@@ -118,14 +124,24 @@ class Sealer(Common, pb2_grpc.SealerServicer):
                 sealer_backend=self.service_name,
             )
 
+        try:
+            pdf_signer = self.pdf_signer(in_data=in_data, signature_meta=signature_meta, signer=pkcs11_signer)
+        except Exception as _e:
+            self.logger.debug(f"signature appearance creation failed, err: {_e}")
+            return SealReply(
+                transaction_id=in_data.transaction_id,
+                data="",
+                error=f"signature appearance creation failed, err: {_e}",
+                sealer_backend=self.service_name,
+            )
+
         signed_pdf = BytesIO()
 
         try:
-            await signers.async_sign_pdf(
+            await pdf_signer.async_sign_pdf(
                 pdf_out=pdf_writer,
                 output=signed_pdf,
-                signer=pkcs11_signer,
-                signature_meta=signature_meta,
+                appearance_text_params=self.appearance_text_params(in_data=in_data, signature_meta=signature_meta),
             )
 
         except PdfKeyNotAvailableError as _e:
@@ -151,6 +167,53 @@ class Sealer(Common, pb2_grpc.SealerServicer):
             error="",
         )
 
+    def pdf_signer(self, in_data: SealRequest, signature_meta: signers.PdfSignatureMetadata, signer: PKCS11Signer) -> PdfSigner:
+        """returns a signer that draws the requested visible stamp, without appearance the signature is invisible"""
+        if not in_data.HasField("appearance"):
+            return PdfSigner(signature_meta=signature_meta, signer=signer)
+
+        appearance = in_data.appearance
+        rect = appearance.rect
+
+        background = None
+        if appearance.image:
+            background = images.PdfImage(self.config.appearance_images[appearance.image])
+
+        stamp_style = stamp.TextStampStyle(
+            stamp_text=appearance_stamp_text(appearance.text),
+            background=background,
+        )
+
+        return PdfSigner(
+            signature_meta=signature_meta,
+            signer=signer,
+            stamp_style=stamp_style,
+            new_field_spec=SigFieldSpec(
+                sig_field_name=signature_meta.field_name,
+                on_page=max(appearance.page, 1) - 1,
+                box=(int(rect.llx), int(rect.lly), int(rect.urx), int(rect.ury)),
+            ),
+        )
+
+    def appearance_text_params(self, in_data: SealRequest, signature_meta: signers.PdfSignatureMetadata) -> dict:
+        return {
+            "reason": signature_meta.reason or "",
+            "location": signature_meta.location or "",
+            "transaction_id": in_data.transaction_id,
+        }
+
+
+def appearance_stamp_text(text: str) -> str:
+    """converts a {placeholder} template, as checked by apigw, to pyhanko's %(placeholder)s interpolation"""
+    if not text:
+        return "%(signer)s\nTime: %(ts)s"
+
+    text = text.replace("%", "%%")
+    for placeholder in ("signer", "ts", "reason", "location", "transaction_id"):
+        text = text.replace("{" + placeholder + "}", "%(" + placeholder + ")s")
+    return text
+
+
 class QueueServer4(Common):
     def __init__(self) -> None:
         super().__init__()
@@ -208,7 +271,13 @@ class QueueServer4(Common):
 
             await msg.in_progress()
 
-            reply = await self.sealer.Seal(in_data=SealRequest(**json.loads(msg.data)))
+            await nc.publish(
+                subject=f"SEALING.{msg.headers['Nats-Msg-Id']}",
+                headers={"sealer_backend": self.service_name},
+            )
+
+            request = json_format.ParseDict(json.loads(msg.data), SealRequest(), ignore_unknown_fields=True)
+            reply = await self.sealer.Seal(in_data=request)
             d = dict(
                 transaction_id=reply.transaction_id,
                 data=reply.data,
@@ -216,7 +285,7 @@ class QueueServer4(Common):
                 sealer_backend=reply.sealer_backend,
             )
             await js.publish(
-                subject="CACHE",
+                subject=f"CACHE.{msg.headers['Nats-Msg-Id']}",
                 payload=json.dumps(d).encode(),
                 headers={"Nats-Msg-Id": msg.headers["Nats-Msg-Id"]},
             )
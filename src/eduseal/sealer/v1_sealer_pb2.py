@@ -14,7 +14,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0fv1-sealer.proto\x12\tv1.sealer\"3\n\x0bSealRequest\x12\x16\n\x0etransaction_id\x18\x01 \x01(\t\x12\x0c\n\x04\x64\x61ta\x18\x02 \x01(\t\"X\n\tSealReply\x12\x16\n\x0esealer_backend\x18\x01 \x01(\t\x12\x16\n\x0etransaction_id\x18\x02 \x01(\t\x12\x0c\n\x04\x64\x61ta\x18\x03 \x01(\t\x12\r\n\x05\x65rror\x18\x04 \x01(\t2@\n\x06Sealer\x12\x36\n\x04Seal\x12\x16.v1.sealer.SealRequest\x1a\x14.v1.sealer.SealReply\"\x00\x42\'Z%eduseal/internal/gen/sealer/v1_sealerb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0fv1-sealer.proto\x12\tv1.sealer\"\x97\x01\n\x0bSealRequest\x12\x16\n\x0etransaction_id\x18\x01 \x01(\t\x12\x0c\n\x04\x64\x61ta\x18\x02 \x01(\t\x12.\n\x08metadata\x18\x03 \x01(\x0b\x32\x1c.v1.sealer.SignatureMetadata\x12\x32\n\nappearance\x18\x04 \x01(\x0b\x32\x1e.v1.sealer.SignatureAppearance\"m\n\x11SignatureMetadata\x12\x0e\n\x06reason\x18\x01 \x01(\t\x12\x10\n\x08location\x18\x02 \x01(\t\x12\x0c\n\x04name\x18\x03 \x01(\t\x12\x14\n\x0c\x63ontact_info\x18\x04 \x01(\t\x12\x12\n\nfield_name\x18\x05 \x01(\t\"d\n\x13SignatureAppearance\x12\x0c\n\x04page\x18\x01 \x01(\x05\x12\"\n\x04rect\x18\x02 \x01(\x0b\x32\x14.v1.sealer.Rectangle\x12\r\n\x05image\x18\x03 \x01(\t\x12\x0c\n\x04text\x18\x04 \x01(\t\"?\n\tRectangle\x12\x0b\n\x03llx\x18\x01 \x01(\x01\x12\x0b\n\x03lly\x18\x02 \x01(\x01\x12\x0b\n\x03urx\x18\x03 \x01(\x01\x12\x0b\n\x03ury\x18\x04 \x01(\x01\"X\n\tSealReply\x12\x16\n\x0esealer_backend\x18\x01 \x01(\t\x12\x16\n\x0etransaction_id\x18\x02 \x01(\t\x12\x0c\n\x04\x64\x61ta\x18\x03 \x01(\t\x12\r\n\x05\x65rror\x18\x04 \x01(\t2@\n\x06Sealer\x12\x36\n\x04Seal\x12\x16.v1.sealer.SealRequest\x1a\x14.v1.sealer.SealReply\"\x00\x42\'Z%eduseal/internal/gen/sealer/v1_sealerb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -22,10 +22,16 @@ _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'v1_sealer_pb2', _globals)
 if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'Z%eduseal/internal/gen/sealer/v1_sealer'
-  _globals['_SEALREQUEST']._serialized_start=30
-  _globals['_SEALREQUEST']._serialized_end=81
-  _globals['_SEALREPLY']._serialized_start=83
-  _globals['_SEALREPLY']._serialized_end=171
-  _globals['_SEALER']._serialized_start=173
-  _globals['_SEALER']._serialized_end=237
+  _globals['_SEALREQUEST']._serialized_start=31
+  _globals['_SEALREQUEST']._serialized_end=182
+  _globals['_SIGNATUREMETADATA']._serialized_start=184
+  _globals['_SIGNATUREMETADATA']._serialized_end=293
+  _globals['_SIGNATUREAPPEARANCE']._serialized_start=295
+  _globals['_SIGNATUREAPPEARANCE']._serialized_end=395
+  _globals['_RECTANGLE']._serialized_start=397
+  _globals['_RECTANGLE']._serialized_end=460
+  _globals['_SEALREPLY']._serialized_start=462
+  _globals['_SEALREPLY']._serialized_end=550
+  _globals['_SEALER']._serialized_start=552
+  _globals['_SEALER']._serialized_end=616
 # @@protoc_insertion_point(module_scope)
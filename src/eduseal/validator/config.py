@@ -1,5 +1,5 @@
 from pydantic import BaseModel
-from typing import Optional
+from typing import Optional, List
 import yaml
 import os
 import sys
@@ -15,6 +15,10 @@ class GRPCServer(BaseModel):
 class CFG(BaseModel):
     grpc_server: GRPCServer
     validation_certificates_path: str
+    # eduseal_certificates_path holds the sealing certificates, signatures by them are reported as eduSeal seals
+    eduseal_certificates_path: Optional[str] = None
+    # allow_fetching enables OCSP and CRL fetching, without it revocation is reported as not_checked
+    allow_fetching: bool = False
 
 def parse(log: Logger) -> CFG:
     file_name = os.getenv("EDUSEAL_CONFIG_YAML")
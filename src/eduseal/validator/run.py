@@ -9,12 +9,16 @@ from concurrent import futures
 import grpc
 
 from pyhanko.sign.validation import validate_pdf_signature
+from pyhanko.sign.validation.status import PdfSignatureStatus
+from pyhanko.sign.general import SignatureCoverageLevel
+from pyhanko.sign.diff_analysis import ModificationLevel
+from asn1crypto import x509
 from pyhanko_certvalidator import ValidationContext
 from pyhanko_certvalidator.registry import TrustRootList
-from pyhanko.keys import load_cert_from_pemder
+from pyhanko.keys import load_cert_from_pemder, load_certs_from_pemder
 from pyhanko.pdf_utils.reader import PdfFileReader
 
-from eduseal.validator.v1_validator_pb2 import ValidateReply, ValidateRequest
+from eduseal.validator.v1_validator_pb2 import ValidateReply, ValidateRequest, SignatureReport, Certificate, TimestampReport
 import eduseal.validator.v1_validator_pb2_grpc as pb2_grpc
 from eduseal.validator.config import parse, CFG
 
@@ -42,7 +46,9 @@ class Validator(Common, pb2_grpc.ValidatorServicer):
 
         self.validation_context = ValidationContext(
             trust_roots=self.build_trust_roots(),
+            allow_fetching=self.config.allow_fetching,
         )
+        self.eduseal_fingerprints = self.build_eduseal_fingerprints()
 
     def Validate(self, in_data: ValidateRequest, context) -> ValidateReply:
         try:
@@ -73,6 +79,8 @@ class Validator(Common, pb2_grpc.ValidatorServicer):
                 error=f"Validation error {e}",
             )
 
+        signatures = [self.signature_report(embedded_sig=embedded_sig) for embedded_sig in pdf.embedded_signatures]
+
         try:
             transaction_id = self.get_transaction_id_from_keywords(pdf=pdf)
         except Exception as e:
@@ -90,8 +98,64 @@ class Validator(Common, pb2_grpc.ValidatorServicer):
             valid_signature=status.valid,
             transaction_id=transaction_id,
             error="",
+            signatures=signatures,
         )
 
+    def signature_report(self, embedded_sig) -> SignatureReport:
+        """validates one embedded signature, errors are reported in the report rather than raised"""
+        report = SignatureReport(field_name=embedded_sig.field_name or "")
+        try:
+            status: PdfSignatureStatus = validate_pdf_signature(
+                embedded_sig=embedded_sig,
+                signer_validation_context=self.validation_context,
+            )
+        except Exception as e:
+            self.logger.error(f"Validation error, field {report.field_name}: {e}")
+            report.error = f"Validation error {e}"
+            return report
+
+        report.intact = status.intact
+        report.valid = status.valid
+        report.trusted = status.trusted
+        report.signer_subject = status.signing_cert.subject.human_friendly
+        report.chain.extend(certificate_report(cert) for cert in signature_chain(status))
+        report.eduseal = status.signing_cert.sha256 in self.eduseal_fingerprints
+
+        if status.signer_reported_dt is not None:
+            report.signing_time = int(status.signer_reported_dt.timestamp())
+
+        timestamp = status.timestamp_validity
+        if timestamp is not None:
+            report.timestamp.CopyFrom(TimestampReport(
+                present=True,
+                time=int(timestamp.timestamp.timestamp()),
+                tsa_subject=timestamp.signing_cert.subject.human_friendly,
+                valid=timestamp.valid,
+            ))
+
+        if status.revocation_details is not None:
+            report.revocation_status = "revoked"
+        elif status.trusted and self.config.allow_fetching:
+            report.revocation_status = "good"
+        else:
+            report.revocation_status = "not_checked"
+
+        report.coverage = {
+            SignatureCoverageLevel.ENTIRE_FILE: "entire_file",
+            SignatureCoverageLevel.ENTIRE_REVISION: "entire_revision",
+        }.get(status.coverage, "partial")
+        report.modified_after = status.coverage != SignatureCoverageLevel.ENTIRE_FILE
+
+        level = status.modification_level
+        report.modification_level = {
+            ModificationLevel.NONE: "none",
+            ModificationLevel.LTA_UPDATES: "lta_updates",
+            ModificationLevel.FORM_FILLING: "form_filling",
+            ModificationLevel.ANNOTATIONS: "annotations",
+        }.get(level, "other") if level is not None else ""
+
+        return report
+
     def get_transaction_id_from_keywords(self,pdf: PdfFileReader) -> Optional[str]:
         """simple function to get transaction_id from a list of keywords"""
         for keyword in pdf.document_meta_view.keywords:
@@ -112,6 +176,42 @@ class Validator(Common, pb2_grpc.ValidatorServicer):
                 itertools.chain(load_cert_from_pemder(abs_path), trust_root_list)
         return trust_root_list
 
+    def build_eduseal_fingerprints(self) -> set:
+        fingerprints = set()
+        path = self.config.eduseal_certificates_path
+        if path is None:
+            return fingerprints
+        for file in os.listdir(path):
+            filename = os.fsdecode(file)
+            if filename.endswith(".crt"):
+                self.logger.info(f"found eduseal certificate file: {filename}")
+                for cert in load_certs_from_pemder([path + "/" + filename]):
+                    fingerprints.add(cert.sha256)
+        return fingerprints
+
+
+def signature_chain(status: PdfSignatureStatus) -> list:
+    """the validation path from the signer to the trust root, or only the signer certificate when no path was found"""
+    path = status.validation_path
+    if path is None:
+        return [status.signing_cert]
+    certs = list(path.iter_certs(include_root=True)) if hasattr(path, "iter_certs") else list(path)
+    # the path is ordered from the root, the report starts at the signer
+    certs.reverse()
+    return certs
+
+
+def certificate_report(cert: x509.Certificate) -> Certificate:
+    return Certificate(
+        subject=cert.subject.human_friendly,
+        issuer=cert.issuer.human_friendly,
+        serial_number=format(cert.serial_number, "x"),
+        not_before=int(cert.not_valid_before.timestamp()),
+        not_after=int(cert.not_valid_after.timestamp()),
+        sha256_fingerprint=cert.sha256.hex(),
+    )
+
+
 class GRPCServer(Common):
     def __init__(self) -> None:
         super().__init__()
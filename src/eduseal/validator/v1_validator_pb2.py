@@ -14,7 +14,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x12v1-validator.proto\x12\x0cv1.validator\"\x1f\n\x0fValidateRequest\x12\x0c\n\x04\x64\x61ta\x18\x01 \x01(\t\"\x85\x01\n\rValidateReply\x12\x1a\n\x12validation_backend\x18\x01 \x01(\t\x12\x18\n\x10intact_signature\x18\x02 \x01(\x08\x12\x17\n\x0fvalid_signature\x18\x03 \x01(\x08\x12\x16\n\x0etransaction_id\x18\x04 \x01(\t\x12\r\n\x05\x65rror\x18\x05 \x01(\t2U\n\tValidator\x12H\n\x08Validate\x12\x1d.v1.validator.ValidateRequest\x1a\x1b.v1.validator.ValidateReply\"\x00\x42-Z+eduseal/internal/gen/validator/v1_validatorb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x12v1-validator.proto\x12\x0cv1.validator\"\x1f\n\x0fValidateRequest\x12\x0c\n\x04\x64\x61ta\x18\x01 \x01(\t\"\xb8\x01\n\rValidateReply\x12\x1a\n\x12validation_backend\x18\x01 \x01(\t\x12\x18\n\x10intact_signature\x18\x02 \x01(\x08\x12\x17\n\x0fvalid_signature\x18\x03 \x01(\x08\x12\x16\n\x0etransaction_id\x18\x04 \x01(\t\x12\r\n\x05\x65rror\x18\x05 \x01(\t\x12\x31\n\nsignatures\x18\x06 \x03(\x0b\x32\x1d.v1.validator.SignatureReport\"\xe0\x02\n\x0fSignatureReport\x12\x12\n\nfield_name\x18\x01 \x01(\t\x12\x16\n\x0esigner_subject\x18\x02 \x01(\t\x12(\n\x05\x63hain\x18\x03 \x03(\x0b\x32\x19.v1.validator.Certificate\x12\x14\n\x0csigning_time\x18\x04 \x01(\x03\x12\x30\n\ttimestamp\x18\x05 \x01(\x0b\x32\x1d.v1.validator.TimestampReport\x12\x19\n\x11revocation_status\x18\x06 \x01(\t\x12\x10\n\x08\x63overage\x18\x07 \x01(\t\x12\x16\n\x0emodified_after\x18\x08 \x01(\x08\x12\x1a\n\x12modification_level\x18\t \x01(\t\x12\x0e\n\x06intact\x18\n \x01(\x08\x12\r\n\x05valid\x18\x0b \x01(\x08\x12\x0f\n\x07trusted\x18\x0c \x01(\x08\x12\x0f\n\x07\x65\x64useal\x18\r \x01(\x08\x12\r\n\x05\x65rror\x18\x0e \x01(\t\"\x88\x01\n\x0b\x43\x65rtificate\x12\x0f\n\x07subject\x18\x01 \x01(\t\x12\x0e\n\x06issuer\x18\x02 \x01(\t\x12\x15\n\rserial_number\x18\x03 \x01(\t\x12\x12\n\nnot_before\x18\x04 \x01(\x03\x12\x11\n\tnot_after\x18\x05 \x01(\x03\x12\x1a\n\x12sha256_fingerprint\x18\x06 \x01(\t\"T\n\x0fTimestampReport\x12\x0f\n\x07present\x18\x01 \x01(\x08\x12\x0c\n\x04time\x18\x02 \x01(\x03\x12\x13\n\x0btsa_subject\x18\x03 \x01(\t\x12\r\n\x05valid\x18\x04 \x01(\x08\x32U\n\tValidator\x12H\n\x08Validate\x12\x1d.v1.validator.ValidateRequest\x1a\x1b.v1.validator.ValidateReply\"\x00\x42-Z+eduseal/internal/gen/validator/v1_validatorb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -25,7 +25,13 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_VALIDATEREQUEST']._serialized_start=36
   _globals['_VALIDATEREQUEST']._serialized_end=67
   _globals['_VALIDATEREPLY']._serialized_start=70
-  _globals['_VALIDATEREPLY']._serialized_end=203
-  _globals['_VALIDATOR']._serialized_start=205
-  _globals['_VALIDATOR']._serialized_end=290
+  _globals['_VALIDATEREPLY']._serialized_end=254
+  _globals['_SIGNATUREREPORT']._serialized_start=257
+  _globals['_SIGNATUREREPORT']._serialized_end=609
+  _globals['_CERTIFICATE']._serialized_start=612
+  _globals['_CERTIFICATE']._serialized_end=748
+  _globals['_TIMESTAMPREPORT']._serialized_start=750
+  _globals['_TIMESTAMPREPORT']._serialized_end=834
+  _globals['_VALIDATOR']._serialized_start=836
+  _globals['_VALIDATOR']._serialized_end=921
 # @@protoc_insertion_point(module_scope)